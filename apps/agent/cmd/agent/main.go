@@ -1,13 +1,18 @@
 package main
 
 import (
+	"agent/internal/alert"
 	"agent/internal/config"
 	"agent/internal/logger"
+	"agent/internal/paths"
 	"agent/internal/processor"
 	"agent/internal/sender"
 	"agent/internal/state"
+	"agent/internal/transformer"
 	"agent/internal/utils"
+	"agent/internal/validator"
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -22,9 +27,12 @@ type program struct {
 	exit            chan struct{}
 	cfg             *config.Config
 	appState        *state.State
+	transformers    *transformer.Pipeline
+	alerts          *alert.Manager
 	configPath      string
 	logPath         string
 	statePath       string
+	legacyPaths     paths.Paths
 	processingFiles map[string]bool
 	processingMutex sync.Mutex
 	wg              sync.WaitGroup
@@ -38,9 +46,23 @@ func (p *program) Start(s service.Service) error {
 }
 
 func (p *program) run() {
+	// Copy files left next to the executable by older versions before anything opens them
+	migrated, migrateErr := paths.MigrateLegacy(p.legacyPaths, paths.Paths{
+		Config: p.configPath,
+		State:  p.statePath,
+		Log:    p.logPath,
+	})
+
 	// Initialize logger
 	logger.InitLogger(p.logPath)
 	logger.Info.Println("Agent service starting...")
+	logger.Info.Printf("Using config %s, state %s, log %s", p.configPath, p.statePath, p.logPath)
+	for _, m := range migrated {
+		logger.Info.Printf("Migrated legacy file: %s", m)
+	}
+	if migrateErr != nil {
+		logger.Warning.Printf("Legacy file migration incomplete: %v", migrateErr)
+	}
 
 	// Load configuration
 	var err error
@@ -49,6 +71,16 @@ func (p *program) run() {
 		logger.Error.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	p.transformers, err = transformer.NewPipeline(p.cfg.Transformers)
+	if err != nil {
+		logger.Error.Fatalf("Failed to configure transformers: %v", err)
+	}
+
+	p.alerts, err = alert.NewManager(p.cfg.Alerts)
+	if err != nil {
+		logger.Error.Fatalf("Failed to configure alerts: %v", err)
+	}
+
 	// Load state
 	p.appState, err = state.LoadState(p.statePath)
 	if err != nil {
@@ -70,6 +102,9 @@ func (p *program) run() {
 	for {
 		select {
 		case <-ticker.C:
+			// Uploads may have stopped after the last failure, so the outage is
+			// also checked on every cycle
+			p.alerts.CheckOffline()
 			p.scanAndProcessFiles()
 		case <-p.exit:
 			ticker.Stop()
@@ -88,6 +123,10 @@ func (p *program) Stop(s service.Service) error {
 		}
 	}
 	close(p.exit)
+	if p.alerts != nil {
+		// Give in-flight notifications a chance to be delivered
+		p.alerts.Wait()
+	}
 	logger.Close()
 	return nil
 }
@@ -99,16 +138,39 @@ func main() {
 	}
 	exPath := filepath.Dir(ex)
 
+	// Flags take precedence over environment variables, which take precedence over the OS defaults
+	defaults := paths.FromEnv()
+	configPath := flag.String("config", defaults.Config, "path to the configuration file (env "+paths.EnvConfigPath+")")
+	statePath := flag.String("state", defaults.State, "path to the state file (env "+paths.EnvStatePath+")")
+	logPath := flag.String("log", defaults.Log, "path to the log file (env "+paths.EnvLogPath+")")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [install|uninstall|start|stop|restart]\n", filepath.Base(ex))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	resolved, err := paths.Paths{Config: *configPath, State: *statePath, Log: *logPath}.Absolute()
+	if err != nil {
+		log.Fatalf("Failed to resolve data paths: %v", err)
+	}
+
 	prg := &program{
-		configPath: filepath.Join(exPath, "config", "config.json"),
-		logPath:    filepath.Join(exPath, "logs", "app.log"),
-		statePath:  filepath.Join(exPath, "state.json"),
+		configPath:  resolved.Config,
+		logPath:     resolved.Log,
+		statePath:   resolved.State,
+		legacyPaths: paths.Legacy(exPath),
 	}
 
 	svcConfig := &service.Config{
 		Name:        "GoAgent",
 		DisplayName: "Go File Agent",
 		Description: "Monitors a directory and sends modified files.",
+		// Persist the resolved paths so the installed service uses the same files
+		Arguments: []string{
+			"-config", resolved.Config,
+			"-state", resolved.State,
+			"-log", resolved.Log,
+		},
 	}
 
 	s, err := service.New(prg, svcConfig)
@@ -116,8 +178,8 @@ func main() {
 		log.Fatal(err)
 	}
 
-	if len(os.Args) > 1 {
-		err = service.Control(s, os.Args[1])
+	if flag.NArg() > 0 {
+		err = service.Control(s, flag.Arg(0))
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -185,12 +247,35 @@ func (p *program) processFileWrapper(filePath string) {
 	p.appState.UpdateFileStatus(filePath, state.StatusProcessing, nil)
 	logger.Info.Printf("Processing %s", filePath)
 
-	var err error
+	// Convert other export formats to racecheck before validating and uploading
+	uploadPath, cleanup, err := p.transformers.Prepare(filePath)
+	if err != nil {
+		p.quarantine(filePath, fmt.Errorf("transformation failed: %w", err), nil)
+		return
+	}
+	defer cleanup()
+	if uploadPath != filePath {
+		logger.Info.Printf("Transformed %s to racecheck format", filePath)
+	}
+
+	// Validate before uploading so malformed files never reach the backend
+	if quarantined := p.validateFile(filePath, uploadPath); quarantined {
+		return
+	}
+
 	for i := 0; i < p.cfg.MaxRetries; i++ {
-		err = p.processFile(filePath)
+		err = p.processFile(filePath, uploadPath)
 		if err == nil {
+			p.alerts.UploadSucceeded()
 			break // Success
 		}
+		if sender.IsRejected(err) {
+			// The backend rejected the file itself, so retrying would fail the same way
+			logger.Error.Printf("Upload of %s was rejected: %v", filePath, err)
+			break
+		}
+		// Only connectivity and server errors count towards the offline alert
+		p.alerts.UploadFailed(err)
 		logger.Error.Printf("Attempt %d/%d failed for %s: %v", i+1, p.cfg.MaxRetries, filePath, err)
 		p.appState.IncrementRetryCount(filePath)
 
@@ -199,8 +284,9 @@ func (p *program) processFileWrapper(filePath string) {
 	}
 
 	if err != nil {
-		logger.Error.Printf("All retries failed for %s. Moving to error directory.", filePath)
+		logger.Error.Printf("Upload failed for %s. Moving to error directory.", filePath)
 		p.appState.UpdateFileStatus(filePath, state.StatusFailed, err)
+		p.alerts.FileFailed(filePath, err)
 		errDir := p.cfg.ErrorDirectory
 		if moveErr := utils.MoveFile(filePath, errDir, true); moveErr != nil {
 			logger.Error.Printf("Failed to move file %s to error directory: %v", filePath, moveErr)
@@ -216,8 +302,64 @@ func (p *program) processFileWrapper(filePath string) {
 	}
 }
 
-// processFile contains the core logic for processing a single file.
-func (p *program) processFile(filePath string) error {
+// validateFile checks the structure of the racecheck file that will be uploaded for
+// filePath and quarantines the original file along with a readable report if it
+// has errors. It returns true if the file was quarantined.
+func (p *program) validateFile(filePath, uploadPath string) bool {
+	if !validator.ShouldValidate(uploadPath) {
+		return false
+	}
+
+	report, err := validator.ValidateFile(uploadPath)
+	if err != nil {
+		// Let the upload path retry and report read errors as usual
+		logger.Warning.Printf("Could not validate %s: %v", filePath, err)
+		return false
+	}
+	report.FilePath = filePath
+
+	for _, issue := range report.Issues {
+		if issue.Severity == validator.SeverityWarning {
+			logger.Warning.Printf("Validation warning for %s at line %d: %s", filePath, issue.Line, issue.Message)
+		}
+	}
+
+	if !report.HasErrors() {
+		return false
+	}
+
+	p.quarantine(filePath, fmt.Errorf("validation failed with %d errors", report.ErrorCount()), report)
+	return true
+}
+
+// quarantine moves a file that cannot be uploaded to the quarantine directory and
+// writes a report next to it explaining why.
+func (p *program) quarantine(filePath string, reason error, report *validator.Report) {
+	logger.Error.Printf("Quarantining %s: %v", filePath, reason)
+	p.appState.UpdateFileStatus(filePath, state.StatusQuarantined, reason)
+	p.alerts.FileQuarantined(filePath, reason)
+
+	quarantineDir := p.cfg.QuarantineDirectory
+	if moveErr := utils.MoveFile(filePath, quarantineDir, true); moveErr != nil {
+		logger.Error.Printf("Failed to move file %s to quarantine directory: %v", filePath, moveErr)
+		return
+	}
+
+	reportPath := filepath.Join(quarantineDir, filepath.Base(filePath)+".report.txt")
+	var writeErr error
+	if report != nil {
+		writeErr = report.WriteFile(reportPath)
+	} else {
+		writeErr = os.WriteFile(reportPath, []byte(fmt.Sprintf("File %s was quarantined: %v\n", filepath.Base(filePath), reason)), 0644)
+	}
+	if writeErr != nil {
+		logger.Error.Printf("Failed to write quarantine report %s: %v", reportPath, writeErr)
+	}
+}
+
+// processFile contains the core logic for processing a single file. uploadPath is
+// the file actually sent, which differs from filePath when it was transformed.
+func (p *program) processFile(filePath, uploadPath string) error {
 	logger.Info.Printf("Starting upload for file: %s", filePath)
 
 	// Get the file's hash from the state
@@ -227,12 +369,21 @@ func (p *program) processFile(filePath string) error {
 	}
 	hash := fileState.Hash
 
+	// The backend verifies the hash against the uploaded content, not the original file
+	if uploadPath != filePath {
+		var err error
+		hash, err = utils.CalculateSHA256(uploadPath)
+		if err != nil {
+			return fmt.Errorf("could not hash transformed file: %w", err)
+		}
+	}
+
 	// Create a context with a timeout for the operation
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(p.cfg.HTTPTimeoutSeconds)*time.Second)
 	defer cancel()
 
 	// Upload the file and its hash
-	err := sender.SendFile(ctx, filePath, p.cfg.UploadEndpoint, hash, time.Duration(p.cfg.HTTPTimeoutSeconds)*time.Second)
+	err := sender.SendFile(ctx, uploadPath, p.cfg.UploadEndpoint, hash, time.Duration(p.cfg.HTTPTimeoutSeconds)*time.Second)
 	if err != nil {
 		return fmt.Errorf("upload failed: %w", err)
 	}
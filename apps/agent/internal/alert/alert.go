@@ -0,0 +1,201 @@
+package alert
+
+import (
+	"agent/internal/config"
+	"agent/internal/logger"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind identifies the situation that triggered an alert.
+type Kind string
+
+const (
+	// KindFailure is sent when a file is moved to the error directory after all retries.
+	KindFailure Kind = "failure"
+	// KindOffline is sent when uploads have been failing for longer than the configured period.
+	KindOffline Kind = "offline"
+	// KindRecovery is sent when an upload succeeds after an offline alert.
+	KindRecovery Kind = "recovery"
+)
+
+const notifyTimeout = 30 * time.Second
+
+// Alert is the message delivered to every notifier.
+type Alert struct {
+	Kind     Kind      `json:"kind"`
+	Title    string    `json:"title"`
+	Message  string    `json:"message"`
+	FilePath string    `json:"filePath,omitempty"`
+	Host     string    `json:"host"`
+	Time     time.Time `json:"time"`
+}
+
+// Notifier delivers alerts to a single destination.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, a Alert) error
+}
+
+type subscription struct {
+	notifier Notifier
+	kinds    map[Kind]bool
+}
+
+// Manager tracks upload outcomes and dispatches alerts to the configured notifiers.
+type Manager struct {
+	subscriptions []subscription
+	offlineAfter  time.Duration
+	host          string
+
+	mu             sync.Mutex
+	failingSince   time.Time
+	lastError      error
+	offlineAlerted bool
+	wg             sync.WaitGroup
+}
+
+// NewManager builds a Manager from the alerts configuration.
+func NewManager(cfg config.AlertsConfig) (*Manager, error) {
+	host, _ := os.Hostname()
+	m := &Manager{
+		offlineAfter: time.Duration(cfg.OfflineAfterMinutes) * time.Minute,
+		host:         host,
+	}
+
+	for i, nc := range cfg.Notifiers {
+		var n Notifier
+		var err error
+		switch strings.ToLower(nc.Type) {
+		case "webhook":
+			n, err = NewWebhookNotifier(nc)
+		case "email":
+			n, err = NewEmailNotifier(nc)
+		case "command":
+			n, err = NewCommandNotifier(nc)
+		default:
+			err = fmt.Errorf("unknown notifier type %q", nc.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid notifier %d: %w", i+1, err)
+		}
+
+		kinds := make(map[Kind]bool)
+		for _, e := range nc.Events {
+			k := Kind(strings.ToLower(e))
+			if k != KindFailure && k != KindOffline && k != KindRecovery {
+				return nil, fmt.Errorf("invalid notifier %d: unknown event %q", i+1, e)
+			}
+			kinds[k] = true
+		}
+		m.subscriptions = append(m.subscriptions, subscription{notifier: n, kinds: kinds})
+	}
+
+	return m, nil
+}
+
+// FileFailed reports that a file could not be uploaded after all retries.
+func (m *Manager) FileFailed(filePath string, err error) {
+	m.dispatch(Alert{
+		Kind:     KindFailure,
+		Title:    "Upload failed",
+		Message:  fmt.Sprintf("File %s could not be uploaded and was moved to the error directory: %v", filePath, err),
+		FilePath: filePath,
+	})
+}
+
+// FileQuarantined reports that a file was not uploaded because it failed validation.
+func (m *Manager) FileQuarantined(filePath string, reason error) {
+	m.dispatch(Alert{
+		Kind:     KindFailure,
+		Title:    "File quarantined",
+		Message:  fmt.Sprintf("File %s was not uploaded and was moved to the quarantine directory: %v", filePath, reason),
+		FilePath: filePath,
+	})
+}
+
+// UploadFailed records a failed upload attempt and sends an offline alert once
+// uploads have kept failing for the configured period.
+func (m *Manager) UploadFailed(err error) {
+	m.mu.Lock()
+	if m.failingSince.IsZero() {
+		m.failingSince = time.Now()
+	}
+	m.lastError = err
+	m.mu.Unlock()
+
+	m.CheckOffline()
+}
+
+// CheckOffline sends the offline alert if uploads have kept failing for the
+// configured period. It is called periodically so the alert still fires when
+// no new upload is attempted after the last failure.
+func (m *Manager) CheckOffline() {
+	m.mu.Lock()
+	shouldAlert := !m.failingSince.IsZero() && !m.offlineAlerted && time.Since(m.failingSince) >= m.offlineAfter
+	if shouldAlert {
+		m.offlineAlerted = true
+	}
+	since, err := m.failingSince, m.lastError
+	m.mu.Unlock()
+
+	if shouldAlert {
+		m.dispatch(Alert{
+			Kind:    KindOffline,
+			Title:   "Results are not being published",
+			Message: fmt.Sprintf("Uploads have been failing since %s. Last error: %v", since.Format(time.RFC3339), err),
+		})
+	}
+}
+
+// UploadSucceeded clears the failure tracking and sends a recovery alert if an
+// offline alert was sent.
+func (m *Manager) UploadSucceeded() {
+	m.mu.Lock()
+	wasOffline := m.offlineAlerted
+	since := m.failingSince
+	m.failingSince = time.Time{}
+	m.lastError = nil
+	m.offlineAlerted = false
+	m.mu.Unlock()
+
+	if wasOffline {
+		m.dispatch(Alert{
+			Kind:    KindRecovery,
+			Title:   "Results publishing recovered",
+			Message: fmt.Sprintf("Uploads are succeeding again after failing for %s.", time.Since(since).Round(time.Second)),
+		})
+	}
+}
+
+// Wait blocks until every pending notification has been delivered or has timed out.
+func (m *Manager) Wait() {
+	m.wg.Wait()
+}
+
+// dispatch delivers the alert asynchronously so slow destinations never block uploads.
+func (m *Manager) dispatch(a Alert) {
+	a.Host = m.host
+	a.Time = time.Now().UTC()
+
+	for _, sub := range m.subscriptions {
+		if len(sub.kinds) > 0 && !sub.kinds[a.Kind] {
+			continue
+		}
+		m.wg.Add(1)
+		go func(n Notifier) {
+			defer m.wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+			defer cancel()
+			if err := n.Notify(ctx, a); err != nil {
+				logger.Error.Printf("Failed to send %s alert via %s: %v", a.Kind, n.Name(), err)
+				return
+			}
+			logger.Info.Printf("Sent %s alert via %s", a.Kind, n.Name())
+		}(sub.notifier)
+	}
+}
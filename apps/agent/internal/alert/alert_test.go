@@ -0,0 +1,248 @@
+package alert
+
+import (
+	"agent/internal/config"
+	"agent/internal/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	logger.Info = log.New(io.Discard, "", 0)
+	logger.Warning = log.New(io.Discard, "", 0)
+	logger.Error = log.New(io.Discard, "", 0)
+	os.Exit(m.Run())
+}
+
+// recorder is a notifier that keeps every alert it receives.
+type recorder struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (r *recorder) Name() string { return "recorder" }
+
+func (r *recorder) Notify(ctx context.Context, a Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = append(r.alerts, a)
+	return nil
+}
+
+func (r *recorder) kinds() []Kind {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kinds := make([]Kind, len(r.alerts))
+	for i, a := range r.alerts {
+		kinds[i] = a.Kind
+	}
+	return kinds
+}
+
+func TestNewManager(t *testing.T) {
+	tests := []struct {
+		name      string
+		notifiers []config.NotifierConfig
+		err       string
+	}{
+		{"no notifiers", nil, ""},
+		{"webhook", []config.NotifierConfig{{Type: "Webhook", URL: "http://localhost", Events: []string{"FAILURE"}}}, ""},
+		{"command", []config.NotifierConfig{{Type: "command"}}, ""},
+		{"unknown type", []config.NotifierConfig{{Type: "sms"}}, `unknown notifier type "sms"`},
+		{"unknown event", []config.NotifierConfig{{Type: "webhook", URL: "http://localhost", Events: []string{"started"}}}, `unknown event "started"`},
+		{"webhook without url", []config.NotifierConfig{{Type: "webhook"}}, "requires a url"},
+		{"email without recipients", []config.NotifierConfig{{Type: "email", SMTPHost: "smtp.local", From: "agent@local"}}, "requires smtp_host, from and to"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewManager(config.AlertsConfig{Notifiers: tt.notifiers})
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("NewManager returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("NewManager error = %v, expected it to mention %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestManagerDispatch(t *testing.T) {
+	failure := errors.New("connection refused")
+	tests := []struct {
+		name     string
+		kinds    map[Kind]bool
+		events   func(m *Manager)
+		expected []Kind
+	}{
+		{
+			name: "offline once and recovery",
+			events: func(m *Manager) {
+				m.UploadFailed(failure)
+				m.Wait()
+				m.UploadFailed(failure)
+				m.Wait()
+				m.UploadSucceeded()
+			},
+			expected: []Kind{KindOffline, KindRecovery},
+		},
+		{
+			name:     "success without outage",
+			events:   func(m *Manager) { m.UploadSucceeded() },
+			expected: []Kind{},
+		},
+		{
+			name: "file failures",
+			events: func(m *Manager) {
+				m.FileFailed("results.racecheck", failure)
+				m.Wait()
+				m.FileQuarantined("broken.racecheck", failure)
+			},
+			expected: []Kind{KindFailure, KindFailure},
+		},
+		{
+			name:  "subscribed kinds only",
+			kinds: map[Kind]bool{KindFailure: true},
+			events: func(m *Manager) {
+				m.UploadFailed(failure)
+				m.Wait()
+				m.FileFailed("results.racecheck", failure)
+			},
+			expected: []Kind{KindFailure},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			m := &Manager{subscriptions: []subscription{{notifier: r, kinds: tt.kinds}}}
+			tt.events(m)
+			m.Wait()
+
+			got := r.kinds()
+			if len(got) != len(tt.expected) {
+				t.Fatalf("alerts = %v, expected %v", got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("alerts = %v, expected %v", got, tt.expected)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestManagerCheckOffline(t *testing.T) {
+	r := &recorder{}
+	m := &Manager{subscriptions: []subscription{{notifier: r}}, offlineAfter: time.Hour}
+
+	m.CheckOffline()
+	m.UploadFailed(errors.New("connection refused"))
+	m.Wait()
+	if kinds := r.kinds(); len(kinds) != 0 {
+		t.Fatalf("expected no alerts before the offline period, got %v", kinds)
+	}
+
+	// No further uploads are attempted, but the outage keeps growing
+	m.mu.Lock()
+	m.failingSince = time.Now().Add(-2 * time.Hour)
+	m.mu.Unlock()
+	m.CheckOffline()
+	m.CheckOffline()
+	m.Wait()
+	if kinds := r.kinds(); len(kinds) != 1 || kinds[0] != KindOffline {
+		t.Errorf("alerts = %v, expected a single offline alert", kinds)
+	}
+	if !strings.Contains(r.alerts[0].Message, "connection refused") {
+		t.Errorf("message = %q, expected the last error", r.alerts[0].Message)
+	}
+}
+
+func TestManagerOfflineDelay(t *testing.T) {
+	r := &recorder{}
+	m := &Manager{subscriptions: []subscription{{notifier: r}}, offlineAfter: 1 << 62}
+	m.UploadFailed(errors.New("timeout"))
+	m.UploadSucceeded()
+	m.Wait()
+
+	if kinds := r.kinds(); len(kinds) != 0 {
+		t.Errorf("expected no alerts before the offline period, got %v", kinds)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		err    bool
+	}{
+		{"accepted", http.StatusNoContent, false},
+		{"rejected", http.StatusBadGateway, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received Alert
+			var token string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				token = r.Header.Get("X-Token")
+				json.NewDecoder(r.Body).Decode(&received)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			n, err := NewWebhookNotifier(config.NotifierConfig{URL: server.URL, Headers: map[string]string{"X-Token": "secret"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = n.Notify(context.Background(), Alert{Kind: KindFailure, Title: "Upload failed"})
+			if (err != nil) != tt.err {
+				t.Errorf("Notify error = %v, expected error: %v", err, tt.err)
+			}
+			if received.Kind != KindFailure || received.Title != "Upload failed" || token != "secret" {
+				t.Errorf("received %+v with token %q", received, token)
+			}
+		})
+	}
+}
+
+func TestCommandNotifier(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	output := filepath.Join(t.TempDir(), "alert.txt")
+	n, err := NewCommandNotifier(config.NotifierConfig{
+		Program: "sh",
+		Args:    []string{"-c", `printf '%s|%s' "$1" "$2" > "$3"`, "sh", "{title}", "{message}", output},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := n.Notify(context.Background(), Alert{Title: "Upload failed", Message: "File results.racecheck"}); err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "Upload failed|File results.racecheck" {
+		t.Errorf("command received %q", content)
+	}
+}
@@ -0,0 +1,182 @@
+package alert
+
+import (
+	"agent/internal/config"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// WebhookNotifier POSTs the alert as JSON to a URL.
+type WebhookNotifier struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewWebhookNotifier creates a webhook notifier.
+func NewWebhookNotifier(cfg config.NotifierConfig) (*WebhookNotifier, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("webhook notifier requires a url")
+	}
+	return &WebhookNotifier{url: cfg.URL, headers: cfg.Headers, client: &http.Client{}}, nil
+}
+
+// Name implements Notifier.
+func (n *WebhookNotifier) Name() string { return "webhook " + n.url }
+
+// Notify implements Notifier.
+func (n *WebhookNotifier) Notify(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range n.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("received non-OK status code: %d. Response: %s", resp.StatusCode, string(responseBody))
+	}
+	return nil
+}
+
+// EmailNotifier sends the alert through an SMTP server.
+type EmailNotifier struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+// NewEmailNotifier creates an SMTP email notifier.
+func NewEmailNotifier(cfg config.NotifierConfig) (*EmailNotifier, error) {
+	if cfg.SMTPHost == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return nil, errors.New("email notifier requires smtp_host, from and to")
+	}
+	port := cfg.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+	return &EmailNotifier{
+		addr:     net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(port)),
+		host:     cfg.SMTPHost,
+		username: cfg.Username,
+		password: cfg.Password,
+		from:     cfg.From,
+		to:       cfg.To,
+	}, nil
+}
+
+// Name implements Notifier.
+func (n *EmailNotifier) Name() string { return "email " + strings.Join(n.to, ",") }
+
+// Notify implements Notifier. net/smtp has no context support, so the context
+// only bounds how long Notify waits for the send to finish.
+func (n *EmailNotifier) Notify(ctx context.Context, a Alert) error {
+	var auth smtp.Auth
+	if n.username != "" {
+		auth = smtp.PlainAuth("", n.username, n.password, n.host)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&msg, "Subject: [%s] %s\r\n", a.Host, a.Title)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(&msg, "%s\r\n\r\nHost: %s\r\nTime: %s\r\n", a.Message, a.Host, a.Time.Format("2006-01-02 15:04:05 MST"))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(n.addr, auth, n.from, n.to, msg.Bytes())
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CommandNotifier runs a local command, by default the OS desktop notification tool.
+// The placeholders {title} and {message} in the arguments are replaced with the alert text.
+type CommandNotifier struct {
+	program string
+	args    []string
+}
+
+// NewCommandNotifier creates a command notifier.
+func NewCommandNotifier(cfg config.NotifierConfig) (*CommandNotifier, error) {
+	if cfg.Program != "" {
+		return &CommandNotifier{program: cfg.Program, args: cfg.Args}, nil
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return &CommandNotifier{
+			program: "osascript",
+			args:    []string{"-e", "display notification {message} with title {title}"},
+		}, nil
+	case "windows":
+		return &CommandNotifier{
+			program: "msg",
+			args:    []string{"*", "{title}: {message}"},
+		}, nil
+	default:
+		return &CommandNotifier{
+			program: "notify-send",
+			args:    []string{"{title}", "{message}"},
+		}, nil
+	}
+}
+
+// Name implements Notifier.
+func (n *CommandNotifier) Name() string { return "command " + n.program }
+
+// Notify implements Notifier.
+func (n *CommandNotifier) Notify(ctx context.Context, a Alert) error {
+	title, message := a.Title, a.Message
+	if n.program == "osascript" {
+		// AppleScript expects quoted string literals
+		title, message = strconv.Quote(title), strconv.Quote(message)
+	}
+
+	replacer := strings.NewReplacer("{title}", title, "{message}", message)
+	args := make([]string, len(n.args))
+	for i, arg := range n.args {
+		args[i] = replacer.Replace(arg)
+	}
+
+	output, err := exec.CommandContext(ctx, n.program, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -11,11 +11,69 @@ type Config struct {
 	DirectoryToWatch     string `json:"directory_to_watch"`
 	CompletedDirectory   string `json:"completed_directory"`
 	ErrorDirectory       string `json:"error_directory"`
+	QuarantineDirectory  string `json:"quarantine_directory"`
 	UploadEndpoint       string `json:"upload_endpoint"`
 	CheckIntervalSeconds int    `json:"check_interval_seconds"`
 	HTTPTimeoutSeconds   int    `json:"http_timeout_seconds"`
 	MaxRetries           int    `json:"max_retries"`
 	RetryDelaySeconds    int    `json:"retry_delay_seconds"`
+
+	Transformers []TransformerConfig `json:"transformers"`
+	Alerts       AlertsConfig        `json:"alerts"`
+}
+
+// AlertsConfig configures the notifications sent when uploads fail.
+type AlertsConfig struct {
+	// OfflineAfterMinutes is how long uploads must keep failing before an offline
+	// alert is sent. Defaults to 15.
+	OfflineAfterMinutes int              `json:"offline_after_minutes"`
+	Notifiers           []NotifierConfig `json:"notifiers"`
+}
+
+// NotifierConfig declares a single alert destination. Only the fields relevant
+// to Type need to be set.
+type NotifierConfig struct {
+	// Type selects the notifier: "webhook", "email" or "command".
+	Type string `json:"type"`
+	// Events limits the alerts sent to this notifier ("failure", "offline",
+	// "recovery"). All alerts are sent when empty.
+	Events []string `json:"events"`
+
+	// Webhook settings
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+
+	// Email settings
+	SMTPHost string   `json:"smtp_host"`
+	SMTPPort int      `json:"smtp_port"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+
+	// Command settings. When Program is empty the OS notification command is used.
+	Program string   `json:"program"`
+	Args    []string `json:"args"`
+}
+
+// TransformerConfig declares how files with a given extension are converted to
+// the racecheck format before being uploaded.
+type TransformerConfig struct {
+	// Extension of the input files this transformer applies to, e.g. ".csv".
+	Extension string `json:"extension"`
+	// Type selects the transformer implementation. Only "csv" is supported.
+	Type string `json:"type"`
+	// Delimiter is the field separator. Defaults to ",".
+	Delimiter string `json:"delimiter"`
+	// HasHeader indicates that the first row contains column names.
+	HasHeader bool `json:"has_header"`
+	// EventName is written in the racecheck header. Defaults to the file name.
+	EventName string `json:"event_name"`
+	// RaceColumn is the racecheck column used to split rows into races. Defaults to MODALIDAD.
+	RaceColumn string `json:"race_column"`
+	// ColumnMapping maps each racecheck column (SEXO, NOMBRE, ...) to a source
+	// column name, or to its 1-based index when the file has no header.
+	ColumnMapping map[string]string `json:"column_mapping"`
 }
 
 // LoadConfig reads the configuration from the given path.
@@ -32,5 +90,13 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, err
 	}
 
+	if cfg.Alerts.OfflineAfterMinutes <= 0 {
+		cfg.Alerts.OfflineAfterMinutes = 15
+	}
+
+	if cfg.QuarantineDirectory == "" {
+		cfg.QuarantineDirectory = filepath.Join(cfg.ErrorDirectory, "quarantine")
+	}
+
 	return &cfg, nil
 }
@@ -0,0 +1,150 @@
+package paths
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Environment variables that override the default data paths.
+const (
+	EnvConfigPath = "QTIMER_AGENT_CONFIG"
+	EnvStatePath  = "QTIMER_AGENT_STATE"
+	EnvLogPath    = "QTIMER_AGENT_LOG"
+)
+
+const appDirName = "QTimerAgent"
+
+// Paths holds the locations of the files the agent reads and writes.
+type Paths struct {
+	Config string
+	State  string
+	Log    string
+}
+
+// Defaults returns the OS-appropriate default locations. Unlike the executable
+// directory, these are writable when the agent is installed under
+// "Program Files" or "/usr/local/bin".
+func Defaults() Paths {
+	switch runtime.GOOS {
+	case "windows":
+		base := os.Getenv("ProgramData")
+		if base == "" {
+			base = `C:\ProgramData`
+		}
+		dir := filepath.Join(base, appDirName)
+		return Paths{
+			Config: filepath.Join(dir, "config.json"),
+			State:  filepath.Join(dir, "state.json"),
+			Log:    filepath.Join(dir, "logs", "app.log"),
+		}
+	case "darwin":
+		dir := filepath.Join("/Library", "Application Support", appDirName)
+		return Paths{
+			Config: filepath.Join(dir, "config.json"),
+			State:  filepath.Join(dir, "state.json"),
+			Log:    filepath.Join("/Library", "Logs", appDirName, "app.log"),
+		}
+	default:
+		return Paths{
+			Config: filepath.Join("/etc", "qtimer-agent", "config.json"),
+			State:  filepath.Join("/var", "lib", "qtimer-agent", "state.json"),
+			Log:    filepath.Join("/var", "log", "qtimer-agent", "app.log"),
+		}
+	}
+}
+
+// FromEnv returns the defaults with any environment variable overrides applied.
+func FromEnv() Paths {
+	p := Defaults()
+	if v := os.Getenv(EnvConfigPath); v != "" {
+		p.Config = v
+	}
+	if v := os.Getenv(EnvStatePath); v != "" {
+		p.State = v
+	}
+	if v := os.Getenv(EnvLogPath); v != "" {
+		p.Log = v
+	}
+	return p
+}
+
+// Legacy returns the locations used by older versions of the agent, which
+// stored everything relative to the executable directory.
+func Legacy(exeDir string) Paths {
+	return Paths{
+		Config: filepath.Join(exeDir, "config", "config.json"),
+		State:  filepath.Join(exeDir, "state.json"),
+		Log:    filepath.Join(exeDir, "logs", "app.log"),
+	}
+}
+
+// Absolute resolves every path against the current working directory so the
+// values can be handed to the service manager.
+func (p Paths) Absolute() (Paths, error) {
+	var err error
+	if p.Config, err = filepath.Abs(p.Config); err != nil {
+		return p, err
+	}
+	if p.State, err = filepath.Abs(p.State); err != nil {
+		return p, err
+	}
+	if p.Log, err = filepath.Abs(p.Log); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// MigrateLegacy copies files from the legacy locations to the current ones when
+// they do not exist yet. The old files are left in place because the executable
+// directory is frequently read-only. It returns a description of each file copied.
+func MigrateLegacy(legacy, current Paths) ([]string, error) {
+	pairs := [][2]string{
+		{legacy.Config, current.Config},
+		{legacy.State, current.State},
+		{legacy.Log, current.Log},
+	}
+
+	var migrated []string
+	for _, pair := range pairs {
+		src, dst := pair[0], pair[1]
+		if filepath.Clean(src) == filepath.Clean(dst) {
+			continue
+		}
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if err := copyFile(src, dst); err != nil {
+			return migrated, fmt.Errorf("failed to migrate %s to %s: %w", src, dst, err)
+		}
+		migrated = append(migrated, fmt.Sprintf("%s -> %s", src, dst))
+	}
+	return migrated, nil
+}
+
+func copyFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -3,6 +3,7 @@ package sender
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -12,6 +13,34 @@ import (
 	"time"
 )
 
+// StatusError is returned when the backend answers with a non-OK status code.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("received non-OK status code: %d. Response: %s", e.StatusCode, e.Body)
+}
+
+// rejectedStatuses are the status codes with which the backend refuses the file
+// itself, so uploading it again would fail the same way. Other client errors,
+// such as 408 Request Timeout or 429 Too Many Requests, are worth retrying.
+var rejectedStatuses = map[int]bool{
+	http.StatusBadRequest:            true,
+	http.StatusConflict:              true,
+	http.StatusRequestEntityTooLarge: true,
+	http.StatusUnsupportedMediaType:  true,
+	http.StatusUnprocessableEntity:   true,
+}
+
+// IsRejected reports whether the backend was reachable but rejected the file,
+// as opposed to being unreachable, busy or failing internally.
+func IsRejected(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && rejectedStatuses[statusErr.StatusCode]
+}
+
 // SendFile sends a file to the specified endpoint with its hash.
 func SendFile(ctx context.Context, filePath, endpoint, fileHash string, timeout time.Duration) error {
 	file, err := os.Open(filePath)
@@ -55,7 +84,7 @@ func SendFile(ctx context.Context, filePath, endpoint, fileHash string, timeout
 	if resp.StatusCode != http.StatusOK {
 		// Leer el cuerpo de la respuesta para obtener más detalles del error, si es posible
 		responseBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("received non-OK status code: %d. Response: %s", resp.StatusCode, string(responseBody))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(responseBody)}
 	}
 
 	return nil
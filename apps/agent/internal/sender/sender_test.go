@@ -0,0 +1,33 @@
+package sender
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestIsRejected(t *testing.T) {
+	tests := []struct {
+		err      error
+		rejected bool
+	}{
+		{&StatusError{StatusCode: http.StatusBadRequest}, true},
+		{&StatusError{StatusCode: http.StatusConflict}, true},
+		{&StatusError{StatusCode: http.StatusRequestEntityTooLarge}, true},
+		{&StatusError{StatusCode: http.StatusUnsupportedMediaType}, true},
+		{&StatusError{StatusCode: http.StatusUnprocessableEntity}, true},
+		{fmt.Errorf("upload failed: %w", &StatusError{StatusCode: http.StatusBadRequest}), true},
+		{&StatusError{StatusCode: http.StatusRequestTimeout}, false},
+		{&StatusError{StatusCode: http.StatusTooManyRequests}, false},
+		{&StatusError{StatusCode: http.StatusInternalServerError}, false},
+		{&StatusError{StatusCode: http.StatusBadGateway}, false},
+		{errors.New("connection refused"), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRejected(tt.err); got != tt.rejected {
+			t.Errorf("IsRejected(%v) = %v, expected %v", tt.err, got, tt.rejected)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package state
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"sync"
 	"time"
 )
@@ -19,6 +20,8 @@ const (
 	StatusFailed FileStatus = "Failed"
 	// StatusCompleted means the file was successfully processed and moved.
 	StatusCompleted FileStatus = "Completed"
+	// StatusQuarantined means the file failed validation and was moved without being uploaded.
+	StatusQuarantined FileStatus = "Quarantined"
 )
 
 // FileState represents the state of a single file.
@@ -75,6 +78,10 @@ func (s *State) SaveState(path string) error {
 		return err
 	}
 
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
 	return os.WriteFile(path, data, 0644)
 }
 
@@ -0,0 +1,231 @@
+package transformer
+
+import (
+	"agent/internal/config"
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// StandardColumns is the column order used by the timing software's racecheck exports.
+var StandardColumns = []string{"SEXO", "NOMBRE", "CHIP", "DORSAL", "MODALIDAD", "CATEGORIA", "TIEMPO", "POSICION", "POS.CAT.", "RITMO"}
+
+// requiredMappings are the racecheck columns a CSV mapping must provide.
+var requiredMappings = []string{"NOMBRE", "DORSAL", "TIEMPO"}
+
+const defaultRaceName = "GENERAL"
+
+// CSVTransformer converts delimited text exports (comma, semicolon, tab...) into racecheck.
+type CSVTransformer struct {
+	delimiter  rune
+	hasHeader  bool
+	eventName  string
+	raceColumn string
+	mapping    map[string]string
+	columns    []string
+}
+
+// NewCSVTransformer validates the configuration and builds a CSV transformer.
+func NewCSVTransformer(cfg config.TransformerConfig) (*CSVTransformer, error) {
+	delimiter := ','
+	if cfg.Delimiter != "" {
+		if cfg.Delimiter == `\t` {
+			cfg.Delimiter = "\t"
+		}
+		r, size := utf8.DecodeRuneInString(cfg.Delimiter)
+		if size != len(cfg.Delimiter) {
+			return nil, fmt.Errorf("delimiter must be a single character, got %q", cfg.Delimiter)
+		}
+		delimiter = r
+	}
+
+	if len(cfg.ColumnMapping) == 0 {
+		return nil, errors.New("column_mapping is required")
+	}
+	mapping := make(map[string]string, len(cfg.ColumnMapping))
+	for target, source := range cfg.ColumnMapping {
+		target = strings.ToUpper(strings.TrimSpace(target))
+		if target == "" || strings.TrimSpace(source) == "" {
+			return nil, fmt.Errorf("column_mapping contains an empty entry")
+		}
+		if !cfg.HasHeader {
+			if n, err := strconv.Atoi(source); err != nil || n < 1 {
+				return nil, fmt.Errorf("column %s must map to a 1-based index when has_header is false", target)
+			}
+		}
+		mapping[target] = strings.TrimSpace(source)
+	}
+	for _, required := range requiredMappings {
+		if _, ok := mapping[required]; !ok {
+			return nil, fmt.Errorf("column_mapping must include %s", required)
+		}
+	}
+
+	raceColumn := strings.ToUpper(cfg.RaceColumn)
+	if raceColumn == "" {
+		raceColumn = "MODALIDAD"
+	}
+
+	// Keep the standard column order and append any extra mapped columns
+	columns := append([]string{}, StandardColumns...)
+	var extra []string
+	for target := range mapping {
+		if !contains(StandardColumns, target) {
+			extra = append(extra, target)
+		}
+	}
+	sort.Strings(extra)
+	columns = append(columns, extra...)
+
+	return &CSVTransformer{
+		delimiter:  delimiter,
+		hasHeader:  cfg.HasHeader,
+		eventName:  cfg.EventName,
+		raceColumn: raceColumn,
+		mapping:    mapping,
+		columns:    columns,
+	}, nil
+}
+
+// Transform implements Transformer.
+func (t *CSVTransformer) Transform(srcPath, dstPath string) error {
+	in, err := os.Open(filepath.Clean(srcPath))
+	if err != nil {
+		return fmt.Errorf("failed to open input file: %w", err)
+	}
+	defer in.Close()
+
+	reader := csv.NewReader(bufio.NewReader(in))
+	reader.Comma = t.delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filepath.Base(srcPath), err)
+	}
+	if len(records) > 0 && len(records[0]) > 0 {
+		records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff")
+	}
+
+	sourceIndex, err := t.resolveColumns(records)
+	if err != nil {
+		return err
+	}
+	if t.hasHeader && len(records) > 0 {
+		records = records[1:]
+	}
+
+	// Group rows by race, keeping the order in which races first appear
+	var raceOrder []string
+	rowsByRace := make(map[string][][]string)
+	for lineIdx, record := range records {
+		if isBlank(record) {
+			continue
+		}
+		row := make([]string, len(t.columns))
+		for i, column := range t.columns {
+			idx, ok := sourceIndex[column]
+			if !ok {
+				continue
+			}
+			if idx >= len(record) {
+				return fmt.Errorf("row %d has %d columns but %s is mapped to column %d", lineIdx+1, len(record), column, idx+1)
+			}
+			row[i] = sanitize(record[idx])
+		}
+
+		race := defaultRaceName
+		if idx, ok := sourceIndex[t.raceColumn]; ok && strings.TrimSpace(record[idx]) != "" {
+			race = sanitize(record[idx])
+		}
+		if _, seen := rowsByRace[race]; !seen {
+			raceOrder = append(raceOrder, race)
+		}
+		rowsByRace[race] = append(rowsByRace[race], row)
+	}
+
+	eventName := t.eventName
+	if eventName == "" {
+		eventName = strings.TrimSuffix(filepath.Base(srcPath), filepath.Ext(srcPath))
+	}
+
+	out, err := os.Create(dstPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	w := bufio.NewWriter(out)
+	fmt.Fprintf(w, "1|%s\n", sanitize(eventName))
+	for i, race := range raceOrder {
+		fmt.Fprintf(w, ";%d|%s\n", i+1, race)
+		fmt.Fprintf(w, ";%s\n", strings.Join(t.columns, "|"))
+		for _, row := range rowsByRace[race] {
+			fmt.Fprintf(w, "%s\n", strings.Join(row, "|"))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return out.Close()
+}
+
+// resolveColumns maps each racecheck column to its 0-based index in the source records.
+func (t *CSVTransformer) resolveColumns(records [][]string) (map[string]int, error) {
+	index := make(map[string]int, len(t.mapping))
+	if !t.hasHeader {
+		for target, source := range t.mapping {
+			n, _ := strconv.Atoi(source)
+			index[target] = n - 1
+		}
+		return index, nil
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("file is empty, expected a header row")
+	}
+	headerIndex := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		headerIndex[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+	for target, source := range t.mapping {
+		i, ok := headerIndex[strings.ToUpper(source)]
+		if !ok {
+			return nil, fmt.Errorf("column %q mapped to %s not found in header", source, target)
+		}
+		index[target] = i
+	}
+	return index, nil
+}
+
+// sanitize removes characters that would break the pipe-separated format.
+func sanitize(value string) string {
+	value = strings.NewReplacer("|", "/", "\r", " ", "\n", " ").Replace(value)
+	return strings.TrimSpace(value)
+}
+
+func isBlank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package transformer
+
+import (
+	"agent/internal/config"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RacecheckExtension is the extension of the files produced by every transformer.
+const RacecheckExtension = ".racecheck"
+
+// Transformer converts an input file into the racecheck pipe format.
+type Transformer interface {
+	// Transform reads srcPath and writes the racecheck representation to dstPath.
+	Transform(srcPath, dstPath string) error
+}
+
+// Pipeline selects the transformer to apply to a file based on its extension.
+type Pipeline struct {
+	byExtension map[string]Transformer
+}
+
+// NewPipeline builds a pipeline from the transformers declared in the configuration.
+func NewPipeline(cfgs []config.TransformerConfig) (*Pipeline, error) {
+	p := &Pipeline{byExtension: make(map[string]Transformer)}
+	for _, cfg := range cfgs {
+		ext := strings.ToLower(cfg.Extension)
+		if ext == "" {
+			return nil, fmt.Errorf("transformer is missing an extension")
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		if ext == RacecheckExtension {
+			return nil, fmt.Errorf("transformer for %s would overwrite racecheck files", ext)
+		}
+		if _, dup := p.byExtension[ext]; dup {
+			return nil, fmt.Errorf("more than one transformer configured for %s", ext)
+		}
+
+		var t Transformer
+		var err error
+		switch strings.ToLower(cfg.Type) {
+		case "", "csv":
+			t, err = NewCSVTransformer(cfg)
+		default:
+			err = fmt.Errorf("unknown transformer type %q", cfg.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid transformer for %s: %w", ext, err)
+		}
+		p.byExtension[ext] = t
+	}
+	return p, nil
+}
+
+// Prepare returns the path of the file to upload for filePath. Files without a
+// configured transformer are returned unchanged. Transformed files are written to
+// a temporary directory under the same base name with the racecheck extension,
+// because the backend associates uploads with events by file name. The returned
+// cleanup function removes any temporary output and is always safe to call.
+func (p *Pipeline) Prepare(filePath string) (string, func(), error) {
+	noop := func() {}
+	if p == nil {
+		return filePath, noop, nil
+	}
+
+	t, ok := p.byExtension[strings.ToLower(filepath.Ext(filePath))]
+	if !ok {
+		return filePath, noop, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "agent-transform-")
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(tmpDir) }
+
+	base := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	dstPath := filepath.Join(tmpDir, base+RacecheckExtension)
+	if err := t.Transform(filePath, dstPath); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	return dstPath, cleanup, nil
+}
@@ -0,0 +1,182 @@
+package transformer
+
+import (
+	"agent/internal/config"
+	"agent/internal/validator"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewPipeline(t *testing.T) {
+	mapping := map[string]string{"NOMBRE": "Name", "DORSAL": "Bib", "TIEMPO": "Time"}
+	tests := []struct {
+		name string
+		cfgs []config.TransformerConfig
+		err  string
+	}{
+		{"valid", []config.TransformerConfig{{Extension: "csv", HasHeader: true, ColumnMapping: mapping}}, ""},
+		{"missing extension", []config.TransformerConfig{{ColumnMapping: mapping}}, "missing an extension"},
+		{"racecheck", []config.TransformerConfig{{Extension: ".racecheck", ColumnMapping: mapping}}, "overwrite racecheck"},
+		{"duplicate", []config.TransformerConfig{{Extension: ".csv", HasHeader: true, ColumnMapping: mapping}, {Extension: ".CSV", HasHeader: true, ColumnMapping: mapping}}, "more than one"},
+		{"unknown type", []config.TransformerConfig{{Extension: ".xls", Type: "excel", ColumnMapping: mapping}}, "unknown transformer type"},
+		{"missing mapping", []config.TransformerConfig{{Extension: ".csv", HasHeader: true}}, "column_mapping is required"},
+		{"missing required column", []config.TransformerConfig{{Extension: ".csv", HasHeader: true, ColumnMapping: map[string]string{"NOMBRE": "Name"}}}, "must include DORSAL"},
+		{"index without header", []config.TransformerConfig{{Extension: ".txt", ColumnMapping: mapping}}, "1-based index"},
+		{"long delimiter", []config.TransformerConfig{{Extension: ".csv", Delimiter: ";;", HasHeader: true, ColumnMapping: mapping}}, "single character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPipeline(tt.cfgs)
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("NewPipeline returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("NewPipeline error = %v, expected it to mention %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestPrepareRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.TransformerConfig
+		fileName string
+		content  string
+		expected string
+	}{
+		{
+			name: "header and races",
+			cfg: config.TransformerConfig{
+				Extension: ".csv",
+				Delimiter: ";",
+				HasHeader: true,
+				EventName: "Corrida Casablanca",
+				ColumnMapping: map[string]string{
+					"SEXO": "Sexo", "NOMBRE": "Nombre", "DORSAL": "Dorsal", "MODALIDAD": "Distancia",
+					"CATEGORIA": "Categoria", "TIEMPO": "Tiempo", "POSICION": "Lugar", "EQUIPO": "Club",
+				},
+			},
+			fileName: "casablanca.csv",
+			content: "\ufeffDorsal;Nombre;Sexo;Distancia;Categoria;Tiempo;Lugar;Club\n" +
+				"121;Cristobal Inostroza;M;10K;Senior;00:33:37;1;Trotadores\n" +
+				"7;Ana | Muñoz;F;5K;Damas;00:21:10;1;\n" +
+				"\n" +
+				"104;Benjamin Ruiz;M;10K;Senior;00:34:40;2;Los Andes\n",
+			expected: "1|Corrida Casablanca\n" +
+				";1|10K\n" +
+				";SEXO|NOMBRE|CHIP|DORSAL|MODALIDAD|CATEGORIA|TIEMPO|POSICION|POS.CAT.|RITMO|EQUIPO\n" +
+				"M|Cristobal Inostroza||121|10K|Senior|00:33:37|1|||Trotadores\n" +
+				"M|Benjamin Ruiz||104|10K|Senior|00:34:40|2|||Los Andes\n" +
+				";2|5K\n" +
+				";SEXO|NOMBRE|CHIP|DORSAL|MODALIDAD|CATEGORIA|TIEMPO|POSICION|POS.CAT.|RITMO|EQUIPO\n" +
+				"F|Ana / Muñoz||7|5K|Damas|00:21:10|1|||\n",
+		},
+		{
+			name: "indexes without header",
+			cfg: config.TransformerConfig{
+				Extension: ".txt",
+				Delimiter: `\t`,
+				ColumnMapping: map[string]string{
+					"NOMBRE": "2", "DORSAL": "1", "TIEMPO": "3", "POSICION": "4",
+					"SEXO": "5", "MODALIDAD": "6", "CATEGORIA": "7",
+				},
+			},
+			fileName: "Trail Maipo.txt",
+			content:  "45\tPaula Rojas\t02:03:04\t1\tF\t21K\tDamas\n",
+			expected: "1|Trail Maipo\n" +
+				";1|21K\n" +
+				";SEXO|NOMBRE|CHIP|DORSAL|MODALIDAD|CATEGORIA|TIEMPO|POSICION|POS.CAT.|RITMO\n" +
+				"F|Paula Rojas||45|21K|Damas|02:03:04|1||\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := NewPipeline([]config.TransformerConfig{tt.cfg})
+			if err != nil {
+				t.Fatalf("NewPipeline returned error: %v", err)
+			}
+			src := writeFile(t, tt.fileName, tt.content)
+
+			dst, cleanup, err := pipeline.Prepare(src)
+			if err != nil {
+				t.Fatalf("Prepare returned error: %v", err)
+			}
+			defer cleanup()
+
+			base := strings.TrimSuffix(tt.fileName, filepath.Ext(tt.fileName))
+			if filepath.Base(dst) != base+RacecheckExtension {
+				t.Errorf("output file = %q, expected the source name with the racecheck extension", filepath.Base(dst))
+			}
+			output, err := os.ReadFile(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(output) != tt.expected {
+				t.Errorf("output =\n%s\nexpected\n%s", output, tt.expected)
+			}
+
+			// The transformed file must pass the same validation as a native export
+			report, err := validator.ValidateFile(dst)
+			if err != nil {
+				t.Fatalf("ValidateFile returned error: %v", err)
+			}
+			if report.HasErrors() {
+				t.Errorf("transformed file does not validate:\n%s", report)
+			}
+
+			cleanup()
+			if _, err := os.Stat(dst); !os.IsNotExist(err) {
+				t.Errorf("expected cleanup to remove %s", dst)
+			}
+		})
+	}
+}
+
+func TestPrepareWithoutTransformer(t *testing.T) {
+	pipeline, err := NewPipeline(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := writeFile(t, "results.racecheck", "1|Corrida\n")
+
+	dst, cleanup, err := pipeline.Prepare(src)
+	defer cleanup()
+	if err != nil || dst != src {
+		t.Errorf("Prepare = %q, %v, expected the file unchanged", dst, err)
+	}
+}
+
+func TestPrepareMissingHeaderColumn(t *testing.T) {
+	pipeline, err := NewPipeline([]config.TransformerConfig{{
+		Extension:     ".csv",
+		HasHeader:     true,
+		ColumnMapping: map[string]string{"NOMBRE": "Name", "DORSAL": "Bib", "TIEMPO": "Time"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := writeFile(t, "results.csv", "Name,Bib\nAna,1\n")
+
+	_, cleanup, err := pipeline.Prepare(src)
+	defer cleanup()
+	if err == nil || !strings.Contains(err.Error(), `"Time"`) {
+		t.Errorf("Prepare error = %v, expected the missing column to be reported", err)
+	}
+}
@@ -0,0 +1,259 @@
+package validator
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RacecheckExtension is the extension of the files this package knows how to validate.
+const RacecheckExtension = ".racecheck"
+
+// Severity indicates whether an issue prevents the file from being uploaded.
+type Severity string
+
+const (
+	// SeverityError marks an issue that causes the file to be quarantined.
+	SeverityError Severity = "ERROR"
+	// SeverityWarning marks an issue that is reported but does not block the upload.
+	SeverityWarning Severity = "WARNING"
+)
+
+// Issue describes a single problem found in a file.
+type Issue struct {
+	Line     int
+	Severity Severity
+	Message  string
+	Raw      string
+}
+
+// Report is the result of validating a file.
+type Report struct {
+	FilePath  string
+	EventName string
+	Races     int
+	Rows      int
+	Issues    []Issue
+}
+
+// requiredColumns are the columns the backend relies on to publish results.
+var requiredColumns = []string{"SEXO", "NOMBRE", "DORSAL", "MODALIDAD", "CATEGORIA", "TIEMPO", "POSICION"}
+
+var timePattern = regexp.MustCompile(`^\d{1,2}:\d{2}(:\d{2})?([.,]\d{1,3})?$`)
+
+// statusMarkers are accepted in place of a time or position for non-finishers.
+var statusMarkers = map[string]bool{"DNF": true, "DNS": true, "DSQ": true, "OTL": true}
+
+// HasErrors reports whether the file has at least one blocking issue.
+func (r *Report) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorCount returns the number of blocking issues.
+func (r *Report) ErrorCount() int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			count++
+		}
+	}
+	return count
+}
+
+// String renders the report in a human readable form.
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Validation report for %s\n", filepath.Base(r.FilePath))
+	fmt.Fprintf(&b, "Event: %s\n", r.EventName)
+	fmt.Fprintf(&b, "Races: %d, rows: %d, errors: %d, warnings: %d\n\n", r.Races, r.Rows, r.ErrorCount(), len(r.Issues)-r.ErrorCount())
+	if len(r.Issues) == 0 {
+		b.WriteString("No issues found.\n")
+		return b.String()
+	}
+	for _, issue := range r.Issues {
+		fmt.Fprintf(&b, "[%s] line %d: %s\n", issue.Severity, issue.Line, issue.Message)
+		if issue.Raw != "" {
+			fmt.Fprintf(&b, "    %s\n", issue.Raw)
+		}
+	}
+	return b.String()
+}
+
+// WriteFile writes the rendered report to path.
+func (r *Report) WriteFile(path string) error {
+	return os.WriteFile(path, []byte(r.String()), 0644)
+}
+
+func (r *Report) add(line int, severity Severity, raw string, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, Issue{
+		Line:     line,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+		Raw:      raw,
+	})
+}
+
+// ShouldValidate reports whether the file at path is a racecheck file.
+func ShouldValidate(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), RacecheckExtension)
+}
+
+// ValidateFile parses the racecheck structure of the file at path and reports
+// every problem found. An error is returned only if the file cannot be read.
+func ValidateFile(path string) (*Report, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	report := &Report{FilePath: path}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var headers []string
+	columnIndex := make(map[string]int)
+	lineNum := 0
+	inRace := false
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+			validateEventHeader(report, lineNum, line)
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, ";SEXO|"):
+			if !inRace {
+				report.add(lineNum, SeverityWarning, line, "column header found before any race line")
+			}
+			headers = strings.Split(strings.TrimPrefix(line, ";"), "|")
+			columnIndex = validateHeaders(report, lineNum, line, headers)
+		case strings.HasPrefix(line, ";"):
+			validateRaceLine(report, lineNum, line)
+			inRace = true
+			headers = nil
+		default:
+			if headers == nil {
+				report.add(lineNum, SeverityError, line, "data row found before a ;SEXO| column header")
+				continue
+			}
+			report.Rows++
+			validateRow(report, lineNum, line, headers, columnIndex)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		report.add(lineNum+1, SeverityError, "", "could not read file: %v", err)
+	}
+
+	if lineNum == 0 {
+		report.add(1, SeverityError, "", "file is empty")
+	} else if report.Rows == 0 {
+		report.add(lineNum, SeverityWarning, "", "file contains no participant rows")
+	}
+
+	return report, nil
+}
+
+func validateEventHeader(report *Report, lineNum int, line string) {
+	name := line
+	if idx := strings.Index(line, "|"); idx != -1 {
+		name = line[idx+1:]
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		report.add(lineNum, SeverityError, line, "event name in header cannot be empty")
+		return
+	}
+	report.EventName = name
+}
+
+func validateRaceLine(report *Report, lineNum int, line string) {
+	parts := strings.SplitN(strings.TrimPrefix(line, ";"), "|", 2)
+	if len(parts) != 2 {
+		report.add(lineNum, SeverityError, line, "race line must have the form ;N|RACE")
+		return
+	}
+	if _, err := strconv.Atoi(strings.TrimSpace(parts[0])); err != nil {
+		report.add(lineNum, SeverityError, line, "race number %q is not a number", parts[0])
+	}
+	if strings.TrimSpace(parts[1]) == "" {
+		report.add(lineNum, SeverityError, line, "race name cannot be empty")
+	}
+	report.Races++
+}
+
+func validateHeaders(report *Report, lineNum int, line string, headers []string) map[string]int {
+	index := make(map[string]int, len(headers))
+	for i, h := range headers {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			report.add(lineNum, SeverityError, line, "column %d has an empty name", i+1)
+			continue
+		}
+		if _, dup := index[h]; dup {
+			report.add(lineNum, SeverityError, line, "column %s appears more than once", h)
+			continue
+		}
+		index[h] = i
+	}
+	for _, required := range requiredColumns {
+		if _, ok := index[required]; !ok {
+			report.add(lineNum, SeverityError, line, "required column %s is missing", required)
+		}
+	}
+	return index
+}
+
+func validateRow(report *Report, lineNum int, line string, headers []string, columnIndex map[string]int) {
+	values := strings.Split(line, "|")
+	if len(values) != len(headers) {
+		report.add(lineNum, SeverityError, line, "expected %d columns but found %d", len(headers), len(values))
+		return
+	}
+
+	value := func(column string) (string, bool) {
+		i, ok := columnIndex[column]
+		if !ok {
+			return "", false
+		}
+		return strings.TrimSpace(values[i]), true
+	}
+
+	if name, ok := value("NOMBRE"); ok && name == "" {
+		report.add(lineNum, SeverityError, line, "participant name is empty")
+	}
+	if bib, ok := value("DORSAL"); ok && bib == "" {
+		report.add(lineNum, SeverityError, line, "bib number is empty")
+	}
+	if t, ok := value("TIEMPO"); ok && !statusMarkers[strings.ToUpper(t)] && !timePattern.MatchString(t) {
+		report.add(lineNum, SeverityError, line, "time %q is not in HH:MM:SS format", t)
+	}
+	if pos, ok := value("POSICION"); ok && !statusMarkers[strings.ToUpper(pos)] {
+		if _, err := strconv.Atoi(pos); err != nil {
+			report.add(lineNum, SeverityWarning, line, "position %q is not a number", pos)
+		}
+	}
+	if pos, ok := value("POS.CAT."); ok && pos != "" && !statusMarkers[strings.ToUpper(pos)] {
+		if _, err := strconv.Atoi(pos); err != nil {
+			report.add(lineNum, SeverityWarning, line, "category position %q is not a number", pos)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sampleDir holds the racecheck exports used as reference data for the project
+const sampleDir = "../../../../data"
+
+func TestValidateFileSamples(t *testing.T) {
+	samples, err := filepath.Glob(filepath.Join(sampleDir, "*"+RacecheckExtension))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) == 0 {
+		t.Skip("no sample files found")
+	}
+
+	for _, sample := range samples {
+		t.Run(filepath.Base(sample), func(t *testing.T) {
+			report, err := ValidateFile(sample)
+			if err != nil {
+				t.Fatalf("ValidateFile returned error: %v", err)
+			}
+			if report.HasErrors() {
+				t.Errorf("expected no errors, got:\n%s", report)
+			}
+			if report.EventName == "" || report.Races == 0 || report.Rows == 0 {
+				t.Errorf("unexpected report: event %q, %d races, %d rows", report.EventName, report.Races, report.Rows)
+			}
+		})
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	const header = ";SEXO|NOMBRE|CHIP|DORSAL|MODALIDAD|CATEGORIA|TIEMPO|POSICION|POS.CAT.|RITMO\n"
+	tests := []struct {
+		name     string
+		content  string
+		errors   int
+		warnings int
+		message  string
+	}{
+		{
+			name:    "valid",
+			content: "1|Corrida\n;1|10K\n" + header + "M|Ana|QT1|1|10K|Senior|00:40:00|1|1|04:00 min/Km\n",
+		},
+		{
+			name:    "status markers",
+			content: "1|Corrida\n;1|10K\n" + header + "M|Ana|QT1|1|10K|Senior|DNF|DNF|DNF|\n",
+		},
+		{
+			name:    "empty event name",
+			content: "1|\n;1|10K\n" + header + "M|Ana|QT1|1|10K|Senior|00:40:00|1|1|\n",
+			errors:  1,
+			message: "event name",
+		},
+		{
+			name:    "missing required column",
+			content: "1|Corrida\n;1|10K\n;SEXO|NOMBRE|DORSAL|MODALIDAD|CATEGORIA|TIEMPO\nM|Ana|1|10K|Senior|00:40:00\n",
+			errors:  1,
+			message: "POSICION",
+		},
+		{
+			name:    "invalid time",
+			content: "1|Corrida\n;1|10K\n" + header + "M|Ana|QT1|1|10K|Senior|40 min|1|1|\n",
+			errors:  1,
+			message: "HH:MM:SS",
+		},
+		{
+			name:    "column count",
+			content: "1|Corrida\n;1|10K\n" + header + "M|Ana|QT1|1|10K\n",
+			errors:  1,
+			message: "expected 10 columns",
+		},
+		{
+			name:     "row before header",
+			content:  "1|Corrida\n;1|10K\nM|Ana|QT1|1|10K|Senior|00:40:00|1|1|\n",
+			errors:   1,
+			warnings: 1,
+			message:  "before a ;SEXO| column header",
+		},
+		{
+			name:     "non numeric position",
+			content:  "1|Corrida\n;1|10K\n" + header + "M|Ana|QT1|1|10K|Senior|00:40:00|1ro|1|\n",
+			warnings: 1,
+			message:  "position",
+		},
+		{
+			name:     "no participants",
+			content:  "1|Corrida\n;1|10K\n" + header,
+			warnings: 1,
+			message:  "no participant rows",
+		},
+		{
+			name:    "empty file",
+			content: "",
+			errors:  1,
+			message: "file is empty",
+		},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_")+RacecheckExtension)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := ValidateFile(path)
+			if err != nil {
+				t.Fatalf("ValidateFile returned error: %v", err)
+			}
+			errors := report.ErrorCount()
+			warnings := len(report.Issues) - errors
+			if errors != tt.errors || warnings != tt.warnings {
+				t.Fatalf("got %d errors and %d warnings, expected %d and %d:\n%s", errors, warnings, tt.errors, tt.warnings, report)
+			}
+			if tt.message != "" && !strings.Contains(report.Issues[0].Message, tt.message) {
+				t.Errorf("issue %q does not mention %q", report.Issues[0].Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestValidateFileMissing(t *testing.T) {
+	if _, err := ValidateFile(filepath.Join(t.TempDir(), "missing.racecheck")); err == nil {
+		t.Error("expected an error for a file that does not exist")
+	}
+}
+
+func TestShouldValidate(t *testing.T) {
+	tests := map[string]bool{
+		"results.racecheck": true,
+		"RESULTS.RACECHECK": true,
+		"results.csv":       false,
+		"racecheck":         false,
+	}
+	for path, expected := range tests {
+		if got := ShouldValidate(path); got != expected {
+			t.Errorf("ShouldValidate(%q) = %v, expected %v", path, got, expected)
+		}
+	}
+}
@@ -26,8 +26,34 @@ func main() {
 	}
 
 	eventRepository := repositories.NewMongoEventRepository(mongoClient)
+	athleteRepository := repositories.NewMongoAthleteRepository(mongoClient)
+	seriesRepository := repositories.NewMongoSeriesRepository(mongoClient)
 
-	eventService := services.NewEventService(eventRepository)
+	if err := eventRepository.EnsureIndexes(); err != nil {
+		log.Printf("Warning: could not ensure event indexes: %v", err)
+	}
+
+	athleteService := services.NewAthleteService(athleteRepository, eventRepository)
+	seriesService := services.NewSeriesService(seriesRepository, eventRepository, athleteRepository)
+	eventService := services.NewEventService(eventRepository, athleteService, seriesService)
+
+	// Completar el resultado tipado de los participantes cargados con versiones anteriores.
+	// Corre en segundo plano para no retrasar el inicio del servidor.
+	go func() {
+		if migrated, err := eventService.MigrateLegacyResults(); err != nil {
+			log.Printf("Warning: could not migrate legacy participant results: %v", err)
+		} else if migrated > 0 {
+			log.Printf("Migrated %d legacy participant results", migrated)
+		}
+
+		// Vincular con el registro de atletas los eventos cargados antes de existir el
+		// registro. Se hace después de la migración porque usa el resultado tipado.
+		if linked, err := athleteService.LinkExistingEvents(); err != nil {
+			log.Printf("Warning: could not link existing events to athletes: %v", err)
+		} else if linked > 0 {
+			log.Printf("Linked %d existing events to athletes", linked)
+		}
+	}()
 
 	cloudinaryService, err := services.NewCloudinaryService()
 	if err != nil {
@@ -35,6 +61,8 @@ func main() {
 	}
 
 	eventHandler := handlers.NewEventHandler(eventService, cloudinaryService)
+	athleteHandler := handlers.NewAthleteHandler(athleteService)
+	seriesHandler := handlers.NewSeriesHandler(seriesService)
 
 	r := gin.Default()
 
@@ -64,6 +92,7 @@ func main() {
 			events.POST("/create", eventHandler.CreateEvent)
 			events.POST("/upload", eventHandler.Upload)
 			events.POST("/:id/upload", eventHandler.UploadToEvent)
+			events.POST("/:id/upload/commit", eventHandler.CommitUpload)
 			events.POST("/upload-image", eventHandler.UploadImageToCloudinary)
 			events.GET("", eventHandler.GetEvents)
 			events.GET("/slug/:slug", eventHandler.GetEventBySlug)
@@ -72,8 +101,56 @@ func main() {
 			events.PATCH("/:id/image", eventHandler.UpdateEventImage)
 			events.DELETE("/:id", eventHandler.DeleteEvent)
 			events.PATCH("/:id/status", eventHandler.UpdateEventStatus)
+			events.GET("/:id/races", eventHandler.GetEventRaces)
+			events.PUT("/:id/races/:number", eventHandler.UpdateRaceDistance)
+			events.GET("/:id/races/:number/splits", eventHandler.GetRaceSplits)
+			events.GET("/:id/races/:number/laps", eventHandler.GetRaceLapChart)
+			events.GET("/:id/uploads", eventHandler.GetEventUploads)
+			events.GET("/:id/changes", eventHandler.GetEventChanges)
+			events.GET("/:id/versions", eventHandler.GetEventVersions)
+			events.GET("/:id/versions/:versionId", eventHandler.GetEventVersion)
+			events.GET("/:id/versions/:versionId/file", eventHandler.DownloadEventVersion)
+			events.POST("/:id/versions/:versionId/rollback", eventHandler.RollbackEventVersion)
+			events.GET("/:id/export", eventHandler.ExportEvent)
 			events.GET("/:id/participants", eventHandler.GetParticipants)
 			events.GET("/:id/participants/comparison", eventHandler.GetParticipantComparison)
+			events.GET("/:id/participants/:bib/certificate", eventHandler.GetParticipantCertificate)
+			events.PUT("/:id/participants/:bib/status", eventHandler.UpdateParticipantStatus)
+			events.GET("/:id/statuses", eventHandler.GetParticipantStatuses)
+			events.GET("/:id/corrections", eventHandler.GetCorrections)
+			events.GET("/:id/corrections/audit", eventHandler.GetCorrectionAudit)
+			events.GET("/:id/corrections/:bib", eventHandler.GetCorrection)
+			events.PUT("/:id/corrections/:bib", eventHandler.SaveCorrection)
+			events.DELETE("/:id/corrections/:bib", eventHandler.DeleteCorrection)
+			events.POST("/:id/start-list", eventHandler.ImportStartList)
+			events.GET("/:id/start-list", eventHandler.GetStartList)
+			events.PUT("/:id/start-list/publish", eventHandler.PublishStartList)
+			events.DELETE("/:id/start-list", eventHandler.DeleteStartList)
+			events.GET("/:id/start-list/reconciliation", eventHandler.ReconcileStartList)
+			events.PUT("/:id/certificate", eventHandler.UpdateCertificateTemplate)
+			events.GET("/:id/teams", eventHandler.GetEventTeams)
+			events.PUT("/:id/teams/scoring", eventHandler.UpdateTeamScoring)
+			events.PUT("/:id/ranking", eventHandler.UpdateRankingSource)
+		}
+
+		athletes := api.Group("/athletes")
+		{
+			athletes.GET("", athleteHandler.GetAthletes)
+			athletes.GET("/:id", athleteHandler.GetAthlete)
+			athletes.GET("/:id/results", athleteHandler.GetAthleteResults)
+			athletes.PUT("/:id", athleteHandler.UpdateAthlete)
+			athletes.POST("/:id/merge", athleteHandler.MergeAthletes)
+			athletes.POST("/:id/split", athleteHandler.SplitAthlete)
+		}
+
+		series := api.Group("/series")
+		{
+			series.POST("", seriesHandler.CreateSeries)
+			series.GET("", seriesHandler.GetSeriesList)
+			series.GET("/:id", seriesHandler.GetSeries)
+			series.PUT("/:id", seriesHandler.UpdateSeries)
+			series.DELETE("/:id", seriesHandler.DeleteSeries)
+			series.GET("/:id/standings", seriesHandler.GetStandings)
 		}
 	}
 
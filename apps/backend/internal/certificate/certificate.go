@@ -0,0 +1,175 @@
+// Package certificate renders finisher certificates as PDF or PNG. Both
+// formats share the same layout, expressed as fractions of the page so it
+// scales to any output size.
+package certificate
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	// Decoders for background images
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Certificate formats.
+const (
+	FormatPDF = "pdf"
+	FormatPNG = "png"
+)
+
+// DefaultTitle is used when the event template has no title.
+const DefaultTitle = "Certificado de finalización"
+
+// DefaultTextColor is used when the event template has no text color.
+var DefaultTextColor = color.RGBA{R: 0x22, G: 0x22, B: 0x22, A: 0xFF}
+
+// ErrUnknownFormat is returned by Render for formats other than PDF and PNG.
+var ErrUnknownFormat = errors.New("unknown certificate format")
+
+// Certificate is the content of a finisher certificate.
+type Certificate struct {
+	Title            string
+	EventName        string
+	EventDate        time.Time
+	ParticipantName  string
+	Bib              string
+	Modality         string
+	Category         string
+	FinishTime       string
+	Position         int
+	CategoryPosition int
+	TextColor        color.RGBA
+	// Background is drawn to cover the whole page. Nil leaves a white page.
+	Background image.Image
+}
+
+// ContentType returns the MIME type of a certificate format.
+func ContentType(format string) string {
+	if format == FormatPNG {
+		return "image/png"
+	}
+	return "application/pdf"
+}
+
+// Render writes the certificate in the given format.
+func Render(w io.Writer, format string, c Certificate) error {
+	if c.Title == "" {
+		c.Title = DefaultTitle
+	}
+	if c.TextColor == (color.RGBA{}) {
+		c.TextColor = DefaultTextColor
+	}
+
+	switch format {
+	case FormatPDF:
+		return renderPDF(w, c)
+	case FormatPNG:
+		return renderPNG(w, c)
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+}
+
+// textLine is a centered line of text. Y is the baseline and Size the font
+// size, both as fractions of the page height.
+type textLine struct {
+	Text string
+	Y    float64
+	Size float64
+	Bold bool
+}
+
+// layout returns the lines of the certificate on a landscape page.
+func layout(c Certificate) []textLine {
+	lines := []textLine{
+		{Text: strings.ToUpper(c.Title), Y: 0.22, Size: 0.060, Bold: true},
+		{Text: "Se certifica que", Y: 0.33, Size: 0.034},
+		{Text: c.ParticipantName, Y: 0.44, Size: 0.072, Bold: true},
+	}
+
+	completed := "completó"
+	if c.Modality != "" {
+		completed += " la modalidad " + c.Modality
+	}
+	lines = append(lines,
+		textLine{Text: completed + " de", Y: 0.53, Size: 0.034},
+		textLine{Text: c.EventName, Y: 0.62, Size: 0.050, Bold: true},
+	)
+	if !c.EventDate.IsZero() {
+		lines = append(lines, textLine{Text: FormatDate(c.EventDate), Y: 0.68, Size: 0.028})
+	}
+
+	if c.FinishTime != "" {
+		lines = append(lines, textLine{Text: "Tiempo oficial " + c.FinishTime, Y: 0.78, Size: 0.040, Bold: true})
+	}
+
+	var places []string
+	if c.Position > 0 {
+		places = append(places, "Lugar general "+ordinal(c.Position))
+	}
+	if c.CategoryPosition > 0 {
+		place := "Lugar en categoría " + ordinal(c.CategoryPosition)
+		if c.Category != "" {
+			place += " (" + c.Category + ")"
+		}
+		places = append(places, place)
+	} else if c.Category != "" {
+		places = append(places, "Categoría "+c.Category)
+	}
+	if len(places) > 0 {
+		lines = append(lines, textLine{Text: strings.Join(places, "  ·  "), Y: 0.85, Size: 0.030})
+	}
+	if c.Bib != "" {
+		lines = append(lines, textLine{Text: "Dorsal " + c.Bib, Y: 0.91, Size: 0.024})
+	}
+	return lines
+}
+
+var monthNames = []string{
+	"enero", "febrero", "marzo", "abril", "mayo", "junio",
+	"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre",
+}
+
+// FormatDate formats a date in Spanish, e.g. "10 de marzo de 2024".
+func FormatDate(t time.Time) string {
+	return fmt.Sprintf("%d de %s de %d", t.Day(), monthNames[t.Month()-1], t.Year())
+}
+
+func ordinal(n int) string {
+	return strconv.Itoa(n) + "°"
+}
+
+// cover scales img to fill width x height, cropping the excess from the center.
+func cover(img image.Image, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	src := img.Bounds()
+
+	// Largest source rectangle with the aspect ratio of the page
+	crop := src
+	if src.Dx()*height > src.Dy()*width {
+		w := src.Dy() * width / height
+		crop.Min.X = src.Min.X + (src.Dx()-w)/2
+		crop.Max.X = crop.Min.X + w
+	} else {
+		h := src.Dx() * height / width
+		crop.Min.Y = src.Min.Y + (src.Dy()-h)/2
+		crop.Max.Y = crop.Min.Y + h
+	}
+
+	xdraw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, crop, xdraw.Src, nil)
+	return dst
+}
+
+// panel is the translucent area behind the text that keeps it readable over
+// busy backgrounds, as fractions of the page.
+var panel = struct{ X, Y, W, H, Alpha float64 }{X: 0.06, Y: 0.10, W: 0.88, H: 0.85, Alpha: 0.78}
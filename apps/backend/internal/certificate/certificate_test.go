@@ -0,0 +1,101 @@
+package certificate
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testCertificate() Certificate {
+	return Certificate{
+		EventName:        "Corrida Casablanca 2024",
+		EventDate:        time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		ParticipantName:  "Ana Muñoz",
+		Bib:              "45",
+		Modality:         "10K",
+		Category:         "Damas",
+		FinishTime:       "00:35:00",
+		Position:         12,
+		CategoryPosition: 2,
+	}
+}
+
+func TestLayout(t *testing.T) {
+	var texts []string
+	for _, line := range layout(testCertificate()) {
+		texts = append(texts, line.Text)
+	}
+	joined := strings.Join(texts, "\n")
+
+	for _, expected := range []string{
+		"Ana Muñoz",
+		"completó la modalidad 10K de",
+		"Corrida Casablanca 2024",
+		"10 de marzo de 2024",
+		"Tiempo oficial 00:35:00",
+		"Lugar general 12°  ·  Lugar en categoría 2° (Damas)",
+		"Dorsal 45",
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("layout is missing %q:\n%s", expected, joined)
+		}
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	background := image.NewRGBA(image.Rect(0, 0, 800, 300))
+	for i := range background.Pix {
+		background.Pix[i] = 0x40
+	}
+	cert := testCertificate()
+	cert.Background = background
+
+	var buf bytes.Buffer
+	if err := Render(&buf, FormatPNG, cert); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("output is not a PNG: %v", err)
+	}
+	if img.Bounds().Dx() != pngWidth || img.Bounds().Dy() != pngHeight {
+		t.Errorf("size = %v, expected %dx%d", img.Bounds(), pngWidth, pngHeight)
+	}
+	// Outside the panel the background shows through
+	if r, _, _, _ := img.At(5, 5).RGBA(); r>>8 != 0x40 {
+		t.Errorf("corner red = %#x, expected the background", r>>8)
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	cert := testCertificate()
+	cert.TextColor = color.RGBA{R: 0x1d, G: 0x4e, B: 0xd8, A: 0xff}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, FormatPDF, cert); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "%PDF-") {
+		t.Error("output is not a PDF")
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	if err := Render(&bytes.Buffer{}, "gif", testCertificate()); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("error = %v, expected ErrUnknownFormat", err)
+	}
+}
+
+func TestCover(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 100))
+	dst := cover(src, 200, 200)
+	if dst.Bounds().Dx() != 200 || dst.Bounds().Dy() != 200 {
+		t.Errorf("size = %v, expected 200x200", dst.Bounds())
+	}
+}
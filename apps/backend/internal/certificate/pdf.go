@@ -0,0 +1,69 @@
+package certificate
+
+import (
+	"bytes"
+	"image/jpeg"
+	"io"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// A4 landscape in millimetres and points.
+const (
+	pdfWidthMm  = 297.0
+	pdfHeightMm = 210.0
+	pdfHeightPt = 595.28
+	mmPerPoint  = 25.4 / 72
+)
+
+func renderPDF(w io.Writer, c Certificate) error {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetTitle(c.Title+" - "+c.ParticipantName, true)
+	pdf.AddPage()
+
+	if c.Background != nil {
+		// fpdf only embeds JPEG, PNG and GIF, so every background is re-encoded
+		// at the PNG resolution
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, cover(c.Background, pngWidth, pngHeight), &jpeg.Options{Quality: 88}); err != nil {
+			return err
+		}
+		options := fpdf.ImageOptions{ImageType: "JPG"}
+		pdf.RegisterImageOptionsReader("background", options, &buf)
+		pdf.ImageOptions("background", 0, 0, pdfWidthMm, pdfHeightMm, false, options, 0, "")
+
+		pdf.SetAlpha(panel.Alpha, "Normal")
+		pdf.SetFillColor(255, 255, 255)
+		pdf.Rect(panel.X*pdfWidthMm, panel.Y*pdfHeightMm, panel.W*pdfWidthMm, panel.H*pdfHeightMm, "F")
+		pdf.SetAlpha(1, "Normal")
+	}
+
+	// The core fonts use Windows-1252
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTextColor(int(c.TextColor.R), int(c.TextColor.G), int(c.TextColor.B))
+	maxWidth := panel.W * pdfWidthMm * 0.94
+
+	for _, line := range layout(c) {
+		style := ""
+		if line.Bold {
+			style = "B"
+		}
+		size := line.Size * pdfHeightPt
+		text := tr(line.Text)
+
+		// Shrink long names and event names to fit the panel
+		pdf.SetFont("Helvetica", style, size)
+		if width := pdf.GetStringWidth(text); width > maxWidth {
+			size *= maxWidth / width
+			pdf.SetFont("Helvetica", style, size)
+		}
+
+		height := size * mmPerPoint
+		pdf.SetXY(0, line.Y*pdfHeightMm-height*0.8)
+		pdf.CellFormat(pdfWidthMm, height, text, "", 0, "C", false, 0, "")
+	}
+
+	return pdf.Output(w)
+}
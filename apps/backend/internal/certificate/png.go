@@ -0,0 +1,99 @@
+package certificate
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// A4 landscape at 150 DPI.
+const (
+	pngWidth  = 1754
+	pngHeight = 1240
+)
+
+var (
+	fontsOnce    sync.Once
+	regularFont  *opentype.Font
+	boldFont     *opentype.Font
+	errLoadFonts error
+)
+
+func loadFonts() error {
+	fontsOnce.Do(func() {
+		regularFont, errLoadFonts = opentype.Parse(goregular.TTF)
+		if errLoadFonts != nil {
+			return
+		}
+		boldFont, errLoadFonts = opentype.Parse(gobold.TTF)
+	})
+	return errLoadFonts
+}
+
+func renderPNG(w io.Writer, c Certificate) error {
+	if err := loadFonts(); err != nil {
+		return err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, pngWidth, pngHeight))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	if c.Background != nil {
+		draw.Draw(img, img.Bounds(), cover(c.Background, pngWidth, pngHeight), image.Point{}, draw.Src)
+
+		area := image.Rect(
+			int(panel.X*pngWidth), int(panel.Y*pngHeight),
+			int((panel.X+panel.W)*pngWidth), int((panel.Y+panel.H)*pngHeight),
+		)
+		white := image.NewUniform(color.NRGBA{R: 255, G: 255, B: 255, A: uint8(panel.Alpha * 255)})
+		draw.Draw(img, area, white, image.Point{}, draw.Over)
+	}
+
+	maxWidth := panel.W * pngWidth * 0.94
+	for _, line := range layout(c) {
+		f := regularFont
+		if line.Bold {
+			f = boldFont
+		}
+
+		face, err := fitFace(f, line.Text, line.Size*pngHeight, maxWidth)
+		if err != nil {
+			return err
+		}
+
+		drawer := &font.Drawer{Dst: img, Src: image.NewUniform(c.TextColor), Face: face}
+		width := drawer.MeasureString(line.Text)
+		drawer.Dot = fixed.Point26_6{
+			X: (fixed.I(pngWidth) - width) / 2,
+			Y: fixed.I(int(line.Y * pngHeight)),
+		}
+		drawer.DrawString(line.Text)
+		face.Close()
+	}
+
+	return png.Encode(w, img)
+}
+
+// fitFace returns a face of the given size, shrunk so text fits in maxWidth pixels.
+func fitFace(f *opentype.Font, text string, size, maxWidth float64) (font.Face, error) {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+
+	width := float64(font.MeasureString(face, text)) / 64
+	if width <= maxWidth {
+		return face, nil
+	}
+	face.Close()
+	return opentype.NewFace(f, &opentype.FaceOptions{Size: size * maxWidth / width, DPI: 72, Hinting: font.HintingFull})
+}
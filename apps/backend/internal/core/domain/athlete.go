@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Athlete es un corredor identificado en los resultados de distintos eventos
+type Athlete struct {
+	ID   primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name string             `bson:"name" json:"name"`
+	// NormalizedNames son los nombres con que aparece en los resultados, normalizados
+	// con utils.NormalizeName
+	NormalizedNames []string `bson:"normalizedNames" json:"normalizedNames"`
+	Chips           []string `bson:"chips" json:"chips"`
+	// NationalID es el RUT o documento del corredor, sin puntos ni guiones
+	NationalID string    `bson:"nationalId,omitempty" json:"nationalId,omitempty"`
+	Sex        string    `bson:"sex" json:"sex"`
+	CreatedAt  time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// AthleteResult vincula el resultado de un participante en un evento con un atleta.
+// Guarda una copia del resultado publicado para armar el historial sin recorrer event_data.
+type AthleteResult struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AthleteID primitive.ObjectID `bson:"athleteId" json:"athleteId"`
+	EventID   primitive.ObjectID `bson:"eventId" json:"eventId"`
+	// ResultKey identifica al participante dentro del evento entre una carga y la siguiente
+	ResultKey  string `bson:"resultKey" json:"resultKey"`
+	RaceNumber int    `bson:"raceNumber" json:"raceNumber"`
+	RaceName   string `bson:"raceName" json:"raceName"`
+	Result     Result `bson:"result" json:"result"`
+	// Manual indica que un administrador asignó el resultado al atleta; al volver a cargar
+	// el evento se conserva aunque el nombre ya no coincida
+	Manual    bool      `bson:"manual" json:"manual"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
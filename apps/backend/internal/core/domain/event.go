@@ -22,4 +22,75 @@ type Event struct {
 	UniqueModalities []string           `bson:"uniqueModalities" json:"uniqueModalities"`
 	UniqueCategories []string           `bson:"uniqueCategories" json:"uniqueCategories"`
 	RecordsCount     int                `bson:"recordsCount" json:"recordsCount"`
+	Races            []Race             `bson:"races" json:"races"`
+	// ActiveVersionID es la versión del archivo cuyos resultados están publicados
+	ActiveVersionID primitive.ObjectID `bson:"activeVersionId,omitempty" json:"activeVersionId,omitempty"`
+	// ActiveGeneration es la generación de event_data visible para los lectores
+	ActiveGeneration primitive.ObjectID `bson:"activeGeneration,omitempty" json:"-"`
+	// PreviousGeneration es la generación reemplazada por la activa. Se conserva hasta el
+	// siguiente reemplazo para los lectores que obtuvieron la generación antes del cambio.
+	PreviousGeneration primitive.ObjectID `bson:"previousGeneration,omitempty" json:"-"`
+	// Certificate configura el certificado de finalización; nil usa la plantilla por defecto
+	Certificate *CertificateTemplate `bson:"certificate,omitempty" json:"certificate,omitempty"`
+	// TeamScoring configura la clasificación por equipos; nil usa la configuración por defecto
+	TeamScoring *TeamScoring `bson:"teamScoring,omitempty" json:"teamScoring,omitempty"`
+	// RankingSource indica qué posiciones se publican; vacío usa las del archivo
+	RankingSource string `bson:"rankingSource,omitempty" json:"rankingSource,omitempty"`
+	// StartList describe la lista de inscritos; nil si no se cargó
+	StartList *StartList `bson:"startList,omitempty" json:"startList,omitempty"`
 }
+
+// Origen de las posiciones publicadas del evento
+const (
+	RankingSourceFile     = "file"
+	RankingSourceComputed = "computed"
+)
+
+// CertificateTemplate es la plantilla del certificado de finalización del evento
+type CertificateTemplate struct {
+	Title string `bson:"title" json:"title"`
+	// BackgroundURL es la imagen de fondo. Si está vacía se usa ImageURL del evento.
+	BackgroundURL string `bson:"backgroundUrl" json:"backgroundUrl"`
+	// NoBackground genera el certificado sobre fondo blanco aunque el evento tenga imagen
+	NoBackground bool `bson:"noBackground" json:"noBackground"`
+	// TextColor es el color del texto en formato "#RRGGBB"
+	TextColor string `bson:"textColor" json:"textColor"`
+}
+
+// TeamScoring configura cómo se calcula la clasificación por equipos de cada carrera
+type TeamScoring struct {
+	// Method es TeamScoringPositions o TeamScoringTimes
+	Method string `bson:"method" json:"method"`
+	// Scorers es la cantidad de mejores corredores de cada equipo que suman al puntaje
+	Scorers int `bson:"scorers" json:"scorers"`
+	// BySex calcula una clasificación por sexo en cada carrera
+	BySex bool `bson:"bySex" json:"bySex"`
+	// IncludeIncomplete muestra, sin posición, a los equipos con menos de Scorers corredores
+	IncludeIncomplete bool `bson:"includeIncomplete" json:"includeIncomplete"`
+}
+
+// Métodos de la clasificación por equipos
+const (
+	// TeamScoringPositions suma los lugares de los corredores; gana el menor total
+	TeamScoringPositions = "positions"
+	// TeamScoringTimes suma los tiempos de los corredores, p. ej. en relevos; gana el menor total
+	TeamScoringTimes = "times"
+)
+
+// Race es una sección ";N|NOMBRE" del archivo de resultados
+type Race struct {
+	Number           int    `bson:"number" json:"number"`
+	Name             string `bson:"name" json:"name"`
+	ParticipantCount int    `bson:"participantCount" json:"participantCount"`
+	WinnerTime       string `bson:"winnerTime" json:"winnerTime"`
+	WinnerTimeMs     int64  `bson:"winnerTimeMs" json:"winnerTimeMs"`
+	// DistanceKm se obtiene de la modalidad (p. ej. "10K") o la configura un administrador
+	DistanceKm     float64 `bson:"distanceKm" json:"distanceKm"`
+	DistanceSource string  `bson:"distanceSource" json:"distanceSource"`
+}
+
+// Orígenes de la distancia de una carrera
+const (
+	DistanceSourceModality = "modality"
+	DistanceSourceManual   = "manual"
+)
@@ -7,8 +7,15 @@ import (
 )
 
 type EventData struct {
-	ID        primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
-	EventID   primitive.ObjectID     `bson:"eventId" json:"eventId"`
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	EventID primitive.ObjectID `bson:"eventId" json:"eventId"`
+	// Generation agrupa los participantes de una misma publicación; solo la generación
+	// activa del evento es visible
+	Generation primitive.ObjectID `bson:"generation,omitempty" json:"-"`
+	RaceNumber int                `bson:"raceNumber" json:"raceNumber"`
+	RaceName   string             `bson:"raceName" json:"raceName"`
+	Result     Result             `bson:"result" json:"result"`
+	// Data conserva las columnas originales del archivo para mostrarlas tal cual
 	Data      map[string]interface{} `bson:"data" json:"data"`
 	CreatedAt time.Time              `bson:"createdAt" json:"createdAt"`
 }
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ParticipantStatus es el estado de un participante fijado por un administrador. Se guarda
+// aparte de event_data para volver a aplicarlo sobre cada carga del archivo.
+type ParticipantStatus struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	EventID primitive.ObjectID `bson:"eventId" json:"eventId"`
+	// Bib es el dorsal del participante, sin espacios y en mayúsculas
+	Bib       string    `bson:"bib" json:"bib"`
+	Status    string    `bson:"status" json:"status"`
+	Reason    string    `bson:"reason,omitempty" json:"reason,omitempty"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
@@ -0,0 +1,92 @@
+package domain
+
+// Result es el resultado tipado de un participante, obtenido a partir de las
+// columnas del archivo de resultados
+type Result struct {
+	Sex      string `bson:"sex" json:"sex"`
+	Name     string `bson:"name" json:"name"`
+	Chip     string `bson:"chip" json:"chip"`
+	Bib      string `bson:"bib" json:"bib"`
+	Modality string `bson:"modality" json:"modality"`
+	Category string `bson:"category" json:"category"`
+	// Club es el club o equipo, de una columna opcional CLUB o EQUIPO
+	Club string `bson:"club,omitempty" json:"club,omitempty"`
+	// Position y CategoryPosition son las posiciones publicadas: las del archivo o, si el
+	// evento usa la clasificación calculada, las calculadas a partir de los tiempos
+	Position         int `bson:"position" json:"position"`
+	CategoryPosition int `bson:"categoryPosition" json:"categoryPosition"`
+	// SexPosition se calcula siempre a partir de los tiempos; los archivos no la traen
+	SexPosition int `bson:"sexPosition" json:"sexPosition"`
+	// FilePosition y FileCategoryPosition son las posiciones que indica el archivo
+	FilePosition         int `bson:"filePosition" json:"filePosition"`
+	FileCategoryPosition int `bson:"fileCategoryPosition" json:"fileCategoryPosition"`
+	// ComputedPosition y ComputedCategoryPosition se calculan a partir de los tiempos.
+	// Son 0 para quienes no terminaron.
+	ComputedPosition         int `bson:"computedPosition" json:"computedPosition"`
+	ComputedCategoryPosition int `bson:"computedCategoryPosition" json:"computedCategoryPosition"`
+	// Status indica que el participante no terminó la carrera (ResultStatusDNF, etc.). Está
+	// vacío para quienes terminaron.
+	Status string `bson:"status,omitempty" json:"status,omitempty"`
+	// StatusReason es el motivo del estado, p. ej. de una descalificación
+	StatusReason string `bson:"statusReason,omitempty" json:"statusReason,omitempty"`
+	// StatusManual indica que el estado lo fijó un administrador y no el archivo
+	StatusManual bool `bson:"statusManual,omitempty" json:"statusManual,omitempty"`
+	// Corrected indica que se aplicó una corrección de un administrador sobre los datos del archivo
+	Corrected bool `bson:"corrected,omitempty" json:"corrected,omitempty"`
+	// FinishTimeMs es 0 cuando el tiempo no se pudo interpretar
+	FinishTimeMs   int64  `bson:"finishTimeMs" json:"finishTimeMs"`
+	FinishTimeText string `bson:"finishTimeText" json:"finishTimeText"`
+	// Pace se calcula a partir de la distancia de la carrera, en formato "MM:SS min/Km"
+	Pace            string            `bson:"pace" json:"pace"`
+	PaceMsPerKm     int64             `bson:"paceMsPerKm" json:"paceMsPerKm"`
+	GapToLeaderMs   int64             `bson:"gapToLeaderMs" json:"gapToLeaderMs"`
+	GapToPreviousMs int64             `bson:"gapToPreviousMs" json:"gapToPreviousMs"`
+	Extras          map[string]string `bson:"extras,omitempty" json:"extras,omitempty"`
+	// Splits son los tiempos parciales del participante, de las columnas SPLIT_n y VUELTA_n,
+	// ordenados por tipo y número. Las columnas originales también quedan en Extras.
+	Splits []Split `bson:"splits,omitempty" json:"splits,omitempty"`
+}
+
+// Split es el tiempo de un participante en un punto de control o al completar una vuelta
+type Split struct {
+	// Kind es SplitKindCheckpoint o SplitKindLap
+	Kind   string `bson:"kind" json:"kind"`
+	Number int    `bson:"number" json:"number"`
+	// Column es la columna del archivo, p. ej. "SPLIT_1" o "VUELTA_2"
+	Column string `bson:"column" json:"column"`
+	Text   string `bson:"text" json:"text"`
+	// ElapsedMs es el tiempo desde la largada. Es 0 si falta una vuelta anterior.
+	ElapsedMs int64 `bson:"elapsedMs" json:"elapsedMs"`
+	// LapMs es el tiempo desde el punto de control anterior o la duración de la vuelta.
+	// Es 0 si falta el punto de control anterior.
+	LapMs int64 `bson:"lapMs" json:"lapMs"`
+}
+
+// Tipos de tiempos parciales
+const (
+	// SplitKindCheckpoint es el tiempo acumulado al pasar por un punto de control
+	SplitKindCheckpoint = "checkpoint"
+	// SplitKindLap es la duración de una vuelta
+	SplitKindLap = "lap"
+)
+
+// Estados de los participantes que no terminaron la carrera
+const (
+	// ResultStatusDNF no terminó la carrera
+	ResultStatusDNF = "DNF"
+	// ResultStatusDNS no partió
+	ResultStatusDNS = "DNS"
+	// ResultStatusDSQ fue descalificado
+	ResultStatusDSQ = "DSQ"
+	// ResultStatusOTL terminó fuera del tiempo límite
+	ResultStatusOTL = "OTL"
+)
+
+// IsResultStatus indica si status es uno de los estados de resultado válidos
+func IsResultStatus(status string) bool {
+	switch status {
+	case ResultStatusDNF, ResultStatusDNS, ResultStatusDSQ, ResultStatusOTL:
+		return true
+	}
+	return false
+}
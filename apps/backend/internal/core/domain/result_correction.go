@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ResultCorrection corrige los datos del participante con el dorsal indicado. Se guarda
+// aparte de event_data para volver a aplicarla sobre cada carga del archivo: los operadores
+// de cronometraje suelen corregir los nombres en el sitio antes que en el software.
+type ResultCorrection struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	EventID primitive.ObjectID `bson:"eventId" json:"eventId"`
+	// Bib es el dorsal del participante, sin espacios y en mayúsculas
+	Bib    string           `bson:"bib" json:"bib"`
+	Fields CorrectionFields `bson:"fields" json:"fields"`
+	// Note explica el motivo de la corrección
+	Note      string    `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedBy string    `bson:"createdBy,omitempty" json:"createdBy,omitempty"`
+	UpdatedBy string    `bson:"updatedBy,omitempty" json:"updatedBy,omitempty"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// CorrectionFields son los campos corregidos. Los vacíos conservan el valor del archivo.
+type CorrectionFields struct {
+	Name     string `bson:"name,omitempty" json:"name,omitempty"`
+	Sex      string `bson:"sex,omitempty" json:"sex,omitempty"`
+	Category string `bson:"category,omitempty" json:"category,omitempty"`
+	Modality string `bson:"modality,omitempty" json:"modality,omitempty"`
+	Club     string `bson:"club,omitempty" json:"club,omitempty"`
+	// FinishTime es el tiempo corregido en formato HH:MM:SS
+	FinishTime string `bson:"finishTime,omitempty" json:"finishTime,omitempty"`
+}
+
+// IsEmpty indica si no se corrige ningún campo
+func (f CorrectionFields) IsEmpty() bool {
+	return f == CorrectionFields{}
+}
+
+// CorrectionAuditEntry registra quién creó, modificó o eliminó una corrección y los campos
+// corregidos antes y después del cambio
+type CorrectionAuditEntry struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	EventID      primitive.ObjectID `bson:"eventId" json:"eventId"`
+	CorrectionID primitive.ObjectID `bson:"correctionId" json:"correctionId"`
+	Bib          string             `bson:"bib" json:"bib"`
+	Action       string             `bson:"action" json:"action"`
+	Before       *CorrectionFields  `bson:"before,omitempty" json:"before,omitempty"`
+	After        *CorrectionFields  `bson:"after,omitempty" json:"after,omitempty"`
+	Note         string             `bson:"note,omitempty" json:"note,omitempty"`
+	User         string             `bson:"user,omitempty" json:"user,omitempty"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// Acciones registradas en la auditoría de correcciones
+const (
+	CorrectionActionCreated = "created"
+	CorrectionActionUpdated = "updated"
+	CorrectionActionDeleted = "deleted"
+)
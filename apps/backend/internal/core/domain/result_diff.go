@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ChangeLogEntry registra los cambios de resultados que introdujo una nueva carga del archivo
+type ChangeLogEntry struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	EventID       primitive.ObjectID `bson:"eventId" json:"eventId"`
+	UploadID      primitive.ObjectID `bson:"uploadId,omitempty" json:"uploadId,omitempty"`
+	VersionID     primitive.ObjectID `bson:"versionId,omitempty" json:"versionId,omitempty"`
+	VersionNumber int                `bson:"versionNumber" json:"versionNumber"`
+	// Rollback indica que los cambios se deben a volver a una versión anterior
+	Rollback  bool       `bson:"rollback" json:"rollback"`
+	FileName  string     `bson:"fileName" json:"fileName"`
+	FileHash  string     `bson:"fileHash" json:"fileHash"`
+	Diff      ResultDiff `bson:"diff" json:"diff"`
+	CreatedAt time.Time  `bson:"createdAt" json:"createdAt"`
+}
+
+// ResultDiff resume las diferencias entre los participantes publicados de un evento
+// y los de un nuevo archivo de resultados
+type ResultDiff struct {
+	AddedCount     int            `bson:"addedCount" json:"addedCount"`
+	RemovedCount   int            `bson:"removedCount" json:"removedCount"`
+	ChangedCount   int            `bson:"changedCount" json:"changedCount"`
+	UnchangedCount int            `bson:"unchangedCount" json:"unchangedCount"`
+	Added          []ResultChange `bson:"added" json:"added"`
+	Removed        []ResultChange `bson:"removed" json:"removed"`
+	Changed        []ResultChange `bson:"changed" json:"changed"`
+}
+
+// ResultChange identifica a un participante por su dorsal o chip y los campos que cambiaron
+type ResultChange struct {
+	Key        string        `bson:"key" json:"key"`
+	Bib        string        `bson:"bib" json:"bib"`
+	Chip       string        `bson:"chip" json:"chip"`
+	Name       string        `bson:"name" json:"name"`
+	RaceNumber int           `bson:"raceNumber" json:"raceNumber"`
+	Fields     []FieldChange `bson:"fields,omitempty" json:"fields,omitempty"`
+}
+
+// FieldChange es el valor anterior y el nuevo de un campo del resultado
+type FieldChange struct {
+	Field  string `bson:"field" json:"field"`
+	Before string `bson:"before" json:"before"`
+	After  string `bson:"after" json:"after"`
+}
+
+// HasChanges indica si el nuevo archivo agrega, elimina o modifica algún participante
+func (d *ResultDiff) HasChanges() bool {
+	return d.AddedCount > 0 || d.RemovedCount > 0 || d.ChangedCount > 0
+}
+
+// FilterBib deja solo los cambios del participante con el dorsal indicado y retorna
+// si quedó alguno. Los contadores se actualizan según lo filtrado.
+func (d *ResultDiff) FilterBib(bib string) bool {
+	filter := func(changes []ResultChange) []ResultChange {
+		filtered := []ResultChange{}
+		for _, change := range changes {
+			if strings.EqualFold(change.Bib, bib) {
+				filtered = append(filtered, change)
+			}
+		}
+		return filtered
+	}
+
+	d.Added = filter(d.Added)
+	d.Removed = filter(d.Removed)
+	d.Changed = filter(d.Changed)
+	d.AddedCount = len(d.Added)
+	d.RemovedCount = len(d.Removed)
+	d.ChangedCount = len(d.Changed)
+	d.UnchangedCount = 0
+	return d.HasChanges()
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Series es un campeonato de varias fechas, p. ej. "XCO Metropolitano 2025". Cada evento
+// de EventIDs es una fecha y el orden de la lista es el orden de las fechas.
+type Series struct {
+	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Name        string               `bson:"name" json:"name"`
+	Slug        string               `bson:"slug" json:"slug"`
+	Description string               `bson:"description" json:"description"`
+	EventIDs    []primitive.ObjectID `bson:"eventIds" json:"eventIds"`
+	Scoring     SeriesScoring        `bson:"scoring" json:"scoring"`
+	CreatedAt   time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
+
+// SeriesScoring son las reglas de puntaje del campeonato
+type SeriesScoring struct {
+	// PointsTable son los puntos por posición en la categoría: el primer valor para el
+	// primer lugar, el segundo para el segundo, etc. Vacía usa la tabla por defecto.
+	PointsTable []int `bson:"pointsTable" json:"pointsTable"`
+	// ParticipationPoints se otorgan a quienes terminan fuera de la tabla de puntos
+	ParticipationPoints int `bson:"participationPoints" json:"participationPoints"`
+	// BestOf cuenta solo las N mejores fechas de cada corredor; 0 cuenta todas
+	BestOf int `bson:"bestOf" json:"bestOf"`
+	// DropWorst descarta las N peores fechas, incluidas las fechas en que no corrió
+	DropWorst int `bson:"dropWorst" json:"dropWorst"`
+}
+
+// SeriesStandings es la clasificación del campeonato por categoría, calculada a partir
+// de los resultados publicados de cada fecha
+type SeriesStandings struct {
+	SeriesID   primitive.ObjectID `bson:"_id" json:"seriesId"`
+	Rounds     []SeriesRound      `bson:"rounds" json:"rounds"`
+	Categories []CategoryStanding `bson:"categories" json:"categories"`
+	UpdatedAt  time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// SeriesRound es una fecha del campeonato. Held indica si ya tiene resultados publicados.
+type SeriesRound struct {
+	Number    int                `bson:"number" json:"number"`
+	EventID   primitive.ObjectID `bson:"eventId" json:"eventId"`
+	EventName string             `bson:"eventName" json:"eventName"`
+	EventSlug string             `bson:"eventSlug" json:"eventSlug"`
+	EventDate time.Time          `bson:"eventDate" json:"eventDate"`
+	Held      bool               `bson:"held" json:"held"`
+}
+
+// CategoryStanding es la clasificación de una categoría del campeonato
+type CategoryStanding struct {
+	Category string          `bson:"category" json:"category"`
+	Entries  []StandingEntry `bson:"entries" json:"entries"`
+}
+
+// StandingEntry es un corredor en la clasificación de una categoría. AthleteID está vacío
+// si sus resultados no están vinculados al registro de atletas.
+type StandingEntry struct {
+	Position  int                `bson:"position" json:"position"`
+	AthleteID primitive.ObjectID `bson:"athleteId,omitempty" json:"athleteId,omitempty"`
+	Name      string             `bson:"name" json:"name"`
+	Points    int                `bson:"points" json:"points"`
+	Rounds    []RoundScore       `bson:"rounds" json:"rounds"`
+}
+
+// RoundScore es el puntaje de un corredor en una fecha. Counted indica si la fecha suma
+// al total después de aplicar BestOf y DropWorst.
+type RoundScore struct {
+	Round            int  `bson:"round" json:"round"`
+	CategoryPosition int  `bson:"categoryPosition" json:"categoryPosition"`
+	Points           int  `bson:"points" json:"points"`
+	Counted          bool `bson:"counted" json:"counted"`
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StartList describe la lista de inscritos cargada para el evento
+type StartList struct {
+	FileName     string `bson:"fileName" json:"fileName"`
+	EntriesCount int    `bson:"entriesCount" json:"entriesCount"`
+	// Published muestra la lista de inscritos al público, p. ej. antes de la carrera
+	Published  bool      `bson:"published" json:"published"`
+	UploadedBy string    `bson:"uploadedBy,omitempty" json:"uploadedBy,omitempty"`
+	UploadedAt time.Time `bson:"uploadedAt" json:"uploadedAt"`
+}
+
+// StartListEntry es un participante inscrito en el evento
+type StartListEntry struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	EventID primitive.ObjectID `bson:"eventId" json:"eventId"`
+	// Bib es el dorsal del participante, sin espacios y en mayúsculas
+	Bib      string `bson:"bib" json:"bib"`
+	Chip     string `bson:"chip,omitempty" json:"chip,omitempty"`
+	Name     string `bson:"name" json:"name"`
+	Sex      string `bson:"sex,omitempty" json:"sex,omitempty"`
+	Category string `bson:"category,omitempty" json:"category,omitempty"`
+	Modality string `bson:"modality,omitempty" json:"modality,omitempty"`
+	Club     string `bson:"club,omitempty" json:"club,omitempty"`
+	// Line es la línea del archivo de inscritos
+	Line int `bson:"line" json:"line"`
+}
+
+// StartListSummary compara los participantes de un archivo de resultados con la lista de
+// inscritos del evento
+type StartListSummary struct {
+	Registered int `bson:"registered" json:"registered"`
+	// Matched son los inscritos que aparecen en el archivo
+	Matched int `bson:"matched" json:"matched"`
+	// NotStartedBibs son los dorsales inscritos que no aparecen en el archivo o que no partieron
+	NotStartedBibs     []string `bson:"notStartedBibs" json:"notStartedBibs"`
+	UnregisteredCount  int      `bson:"unregisteredCount" json:"unregisteredCount"`
+	CategoryMismatches int      `bson:"categoryMismatches" json:"categoryMismatches"`
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Upload registra un archivo de resultados procesado junto con su reporte de validación
+type Upload struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	EventID      primitive.ObjectID `bson:"eventId,omitempty" json:"eventId,omitempty"`
+	FileName     string             `bson:"fileName" json:"fileName"`
+	FileHash     string             `bson:"fileHash" json:"fileHash"`
+	Encoding     string             `bson:"encoding" json:"encoding"`
+	RecordsCount int                `bson:"recordsCount" json:"recordsCount"`
+	Rejected     bool               `bson:"rejected" json:"rejected"`
+	Report       IngestReport       `bson:"report" json:"report"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// IngestReport es el resultado de validar las filas de un archivo de resultados
+type IngestReport struct {
+	// Encoding es la codificación detectada del archivo, p. ej. "UTF-8" o "Windows-1252"
+	Encoding     string        `bson:"encoding" json:"encoding"`
+	Issues       []IngestIssue `bson:"issues" json:"issues"`
+	ErrorCount   int           `bson:"errorCount" json:"errorCount"`
+	WarningCount int           `bson:"warningCount" json:"warningCount"`
+	// StartList compara el archivo con los inscritos; nil si el evento no tiene lista de inscritos
+	StartList *StartListSummary `bson:"startList,omitempty" json:"startList,omitempty"`
+}
+
+// IngestIssue es un problema encontrado en una línea del archivo
+type IngestIssue struct {
+	Line       int    `bson:"line" json:"line"`
+	RaceNumber int    `bson:"raceNumber" json:"raceNumber"`
+	Code       string `bson:"code" json:"code"`
+	Severity   string `bson:"severity" json:"severity"`
+	Reason     string `bson:"reason" json:"reason"`
+	Raw        string `bson:"raw" json:"raw"`
+}
+
+// Severidad de los problemas de validación
+const (
+	IssueSeverityError   = "error"
+	IssueSeverityWarning = "warning"
+)
+
+// Códigos de los problemas de validación
+const (
+	IssueSkippedRow           = "skipped_row"
+	IssueDuplicateBib         = "duplicate_bib"
+	IssueDuplicateChip        = "duplicate_chip"
+	IssueNonMonotonicPosition = "non_monotonic_position"
+	IssueInvalidTime          = "invalid_time"
+	IssueBlankName            = "blank_name"
+	// La posición del archivo no coincide con la calculada a partir de los tiempos
+	IssuePositionMismatch         = "position_mismatch"
+	IssueCategoryPositionMismatch = "category_position_mismatch"
+	// Comparación con la lista de inscritos
+	IssueMissingBib       = "missing_bib"
+	IssueUnregisteredBib  = "unregistered_bib"
+	IssueCategoryMismatch = "category_mismatch"
+)
+
+// Add agrega un problema al reporte y actualiza los contadores
+func (r *IngestReport) Add(issue IngestIssue) {
+	r.Issues = append(r.Issues, issue)
+	if issue.Severity == IssueSeverityError {
+		r.ErrorCount++
+	} else {
+		r.WarningCount++
+	}
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EventVersion es una carga publicada del archivo de resultados de un evento. Las versiones
+// no se modifican; volver a una versión anterior solo cambia la versión activa del evento.
+type EventVersion struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	EventID  primitive.ObjectID `bson:"eventId" json:"eventId"`
+	Number   int                `bson:"number" json:"number"`
+	FileName string             `bson:"fileName" json:"fileName"`
+	FileHash string             `bson:"fileHash" json:"fileHash"`
+	// Format es el formato del archivo original: racecheck, csv, xlsx o iofxml
+	Format           string    `bson:"format,omitempty" json:"format,omitempty"`
+	Content          []byte    `bson:"content,omitempty" json:"-"`
+	RecordsCount     int       `bson:"recordsCount" json:"recordsCount"`
+	UniqueModalities []string  `bson:"uniqueModalities" json:"uniqueModalities"`
+	UniqueCategories []string  `bson:"uniqueCategories" json:"uniqueCategories"`
+	Races            []Race    `bson:"races" json:"races"`
+	UploadedBy       string    `bson:"uploadedBy" json:"uploadedBy"`
+	CreatedAt        time.Time `bson:"createdAt" json:"createdAt"`
+	// Active se calcula al listar las versiones y no se guarda
+	Active bool `bson:"-" json:"active"`
+}
@@ -17,12 +17,23 @@ type FindParticipantsResult struct {
 	TotalCount   int64               `json:"totalCount"`
 }
 
+type FindAthletesResult struct {
+	Athletes   []*domain.Athlete `json:"athletes"`
+	TotalCount int64             `json:"totalCount"`
+}
+
+type FindSeriesResult struct {
+	Series     []*domain.Series `json:"series"`
+	TotalCount int64            `json:"totalCount"`
+}
+
 type ComparisonResult struct {
 	FirstPlace           *domain.EventData   `json:"firstPlace"`
 	PreviousParticipants []*domain.EventData `json:"previousParticipants"`
 }
 
 type EventRepository interface {
+	EnsureIndexes() error
 	Save(event *domain.Event) error
 	FindByName(name string) (*domain.Event, error)
 	FindBySlug(slug string) (*domain.Event, error)
@@ -33,10 +44,78 @@ type EventRepository interface {
 	Delete(id primitive.ObjectID) error
 	UpdateStatus(id primitive.ObjectID, status string) error
 	UpdateFileHash(id primitive.ObjectID, hash string) error
-	UpdateFileStats(id primitive.ObjectID, hash string, uniqueModalities []string, uniqueCategories []string, recordsCount int) error
 	DeleteEventData(eventID primitive.ObjectID) error
-	SaveAllData(data []domain.EventData) (int, error)
+	ReplaceEventData(event *domain.Event, data []domain.EventData) (int, error)
+	FindDataWithoutResult(limit int) ([]*domain.EventData, error)
+	UpdateDataResult(id primitive.ObjectID, result domain.Result) error
+	UpdateDataResults(data []*domain.EventData) error
+	FindAllData(eventID primitive.ObjectID) ([]*domain.EventData, error)
+	FindEventIDsWithRecords() ([]primitive.ObjectID, error)
+	FindRaceData(eventID primitive.ObjectID, raceNumber int) ([]*domain.EventData, error)
+	UpdateRaces(id primitive.ObjectID, races []domain.Race) error
+	UpdateCertificateTemplate(id primitive.ObjectID, template *domain.CertificateTemplate) error
+	UpdateTeamScoring(id primitive.ObjectID, scoring *domain.TeamScoring) error
+	UpdateRankingSource(id primitive.ObjectID, source string) error
+	SaveParticipantStatus(status *domain.ParticipantStatus) error
+	DeleteParticipantStatus(eventID primitive.ObjectID, bib string) error
+	DeleteParticipantStatuses(eventID primitive.ObjectID) error
+	FindParticipantStatuses(eventID primitive.ObjectID) ([]*domain.ParticipantStatus, error)
+	SaveCorrection(correction *domain.ResultCorrection) error
+	DeleteCorrection(id primitive.ObjectID) error
+	DeleteCorrections(eventID primitive.ObjectID) error
+	FindCorrection(eventID primitive.ObjectID, bib string) (*domain.ResultCorrection, error)
+	FindCorrections(eventID primitive.ObjectID) ([]*domain.ResultCorrection, error)
+	SaveCorrectionAudit(entry *domain.CorrectionAuditEntry) error
+	FindCorrectionAudit(eventID primitive.ObjectID, bib *string) ([]*domain.CorrectionAuditEntry, error)
+	ReplaceStartList(eventID primitive.ObjectID, startList *domain.StartList, entries []*domain.StartListEntry) error
+	UpdateStartList(eventID primitive.ObjectID, startList *domain.StartList) error
+	DeleteStartList(eventID primitive.ObjectID) error
+	FindStartList(eventID primitive.ObjectID) ([]*domain.StartListEntry, error)
+	SaveUpload(upload *domain.Upload) error
+	FindUploads(eventID primitive.ObjectID) ([]*domain.Upload, error)
+	SaveChangeLogEntry(entry *domain.ChangeLogEntry) error
+	FindChangeLog(eventID primitive.ObjectID) ([]*domain.ChangeLogEntry, error)
+	SaveVersion(version *domain.EventVersion, data []domain.EventData) error
+	NextVersionNumber(eventID primitive.ObjectID) (int, error)
+	FindVersions(eventID primitive.ObjectID) ([]*domain.EventVersion, error)
+	FindVersion(id primitive.ObjectID) (*domain.EventVersion, error)
+	FindVersionData(versionID primitive.ObjectID) ([]domain.EventData, error)
+	DeleteVersion(id primitive.ObjectID) error
+	DeleteVersions(eventID primitive.ObjectID) error
 	Find(name *string, date *time.Time, page int, limit int) (*FindEventsResult, error)
-	FindData(eventID primitive.ObjectID, name, chip, dorsal, category, distance, sex, position *string, page int, limit int) (*FindParticipantsResult, error)
+	FindData(eventID primitive.ObjectID, name, chip, dorsal, category, distance, sex, position, race *string, page int, limit int) (*FindParticipantsResult, error)
 	GetParticipantComparison(eventID primitive.ObjectID, bib string, distance string, category string) (*ComparisonResult, error)
 }
+
+// AthleteRepository guarda el registro de atletas y los vínculos con sus resultados
+type AthleteRepository interface {
+	SaveAthlete(athlete *domain.Athlete) error
+	UpdateAthlete(athlete *domain.Athlete) error
+	DeleteAthletes(ids []primitive.ObjectID) error
+	FindAthleteByID(id primitive.ObjectID) (*domain.Athlete, error)
+	FindAthletesByIDs(ids []primitive.ObjectID) ([]*domain.Athlete, error)
+	FindAthletesByIdentity(nationalIDs, names, chips []string) ([]*domain.Athlete, error)
+	FindAthletes(name *string, page int, limit int) (*FindAthletesResult, error)
+	SaveAthleteResults(results []*domain.AthleteResult) error
+	DeleteAthleteResults(ids []primitive.ObjectID) error
+	DeleteEventAthleteResults(eventID primitive.ObjectID) ([]primitive.ObjectID, error)
+	FindEventAthleteResults(eventID primitive.ObjectID) ([]*domain.AthleteResult, error)
+	FindAthleteResults(athleteID primitive.ObjectID) ([]*domain.AthleteResult, error)
+	MoveAthleteResults(ids []primitive.ObjectID, athleteID primitive.ObjectID, manual bool) error
+	MoveAllAthleteResults(fromIDs []primitive.ObjectID, athleteID primitive.ObjectID) error
+	CountEventAthleteResults(eventID primitive.ObjectID) (int, error)
+	DeleteOrphanAthletes(ids []primitive.ObjectID) error
+}
+
+// SeriesRepository guarda los campeonatos y sus clasificaciones calculadas
+type SeriesRepository interface {
+	SaveSeries(series *domain.Series) error
+	UpdateSeries(series *domain.Series) error
+	DeleteSeries(id primitive.ObjectID) error
+	FindSeriesByID(id primitive.ObjectID) (*domain.Series, error)
+	FindSeriesBySlug(slug string) (*domain.Series, error)
+	FindSeries(page int, limit int) (*FindSeriesResult, error)
+	FindSeriesByEvent(eventID primitive.ObjectID) ([]*domain.Series, error)
+	SaveStandings(standings *domain.SeriesStandings) error
+	FindStandings(seriesID primitive.ObjectID) (*domain.SeriesStandings, error)
+}
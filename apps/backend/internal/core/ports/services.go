@@ -4,6 +4,8 @@ import (
 	"backend/internal/core/domain"
 	"mime/multipart"
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type UploadResult struct {
@@ -11,6 +13,302 @@ type UploadResult struct {
 	RecordsInserted int    `json:"recordsInserted"`
 	Reprocessed     bool   `json:"reprocessed"`
 	Message         string `json:"message"`
+	UploadID        string `json:"uploadId,omitempty"`
+	// Rejected indica que la carga superó el umbral de errores y no se guardaron los datos
+	Rejected bool                 `json:"rejected"`
+	Report   *domain.IngestReport `json:"report,omitempty"`
+}
+
+// UploadPreview muestra lo que cambiaría al publicar un archivo, sin modificar el evento.
+// La carga se publica enviando Token a la confirmación antes de ExpiresAt.
+type UploadPreview struct {
+	Token               string              `json:"token"`
+	ExpiresAt           time.Time           `json:"expiresAt"`
+	EventID             string              `json:"eventId"`
+	EventName           string              `json:"eventName"`
+	FileEventName       string              `json:"fileEventName"`
+	FileName            string              `json:"fileName"`
+	FileHash            string              `json:"fileHash"`
+	Identical           bool                `json:"identical"`
+	Races               []domain.Race       `json:"races"`
+	RecordsCount        int                 `json:"recordsCount"`
+	CurrentRecordsCount int                 `json:"currentRecordsCount"`
+	SampleRows          []domain.EventData  `json:"sampleRows"`
+	Report              domain.IngestReport `json:"report"`
+	WouldReject         bool                `json:"wouldReject"`
+	Diff                domain.ResultDiff   `json:"diff"`
+}
+
+// EventVersionDetail es una versión del archivo de resultados con sus participantes
+type EventVersionDetail struct {
+	Version      *domain.EventVersion `json:"version"`
+	Participants []domain.EventData   `json:"participants"`
+}
+
+// UploadOptions acompaña al archivo subido. Format, Delimiter, ColumnMapping y EventName
+// solo se usan para archivos que no están en formato racecheck; sin Format, el formato se
+// obtiene de la extensión del archivo.
+type UploadOptions struct {
+	UploadedBy    string
+	Format        string
+	Delimiter     string
+	ColumnMapping map[string]string
+	EventName     string
+}
+
+// ExportFilter limita los participantes exportados. Los campos vacíos no filtran y se
+// interpretan igual que los filtros del listado de participantes.
+type ExportFilter struct {
+	Race     string
+	Category string
+	Sex      string
+}
+
+// ExportFile es un archivo de resultados generado para descargar
+type ExportFile struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// UpdateCertificateTemplateRequest configura el certificado de finalización del evento.
+// Los campos vacíos usan los valores por defecto.
+type UpdateCertificateTemplateRequest struct {
+	Title         string `json:"title"`
+	BackgroundURL string `json:"backgroundUrl"`
+	NoBackground  bool   `json:"noBackground"`
+	TextColor     string `json:"textColor"`
+}
+
+// UpdateTeamScoringRequest configura la clasificación por equipos del evento. Los campos
+// vacíos usan los valores por defecto: suma de posiciones de los 3 mejores de cada equipo.
+type UpdateTeamScoringRequest struct {
+	Method            string `json:"method"`
+	Scorers           int    `json:"scorers"`
+	BySex             bool   `json:"bySex"`
+	IncludeIncomplete bool   `json:"includeIncomplete"`
+}
+
+// UpdateRankingSourceRequest indica qué posiciones publica el evento: "file", las del
+// archivo, o "computed", las calculadas a partir de los tiempos
+type UpdateRankingSourceRequest struct {
+	Source string `json:"source"`
+}
+
+// UpdateParticipantStatusRequest fija el estado de un participante (DNF, DNS, DSQ u OTL)
+// con un motivo opcional. Un estado vacío elimina el fijado y vuelve al del archivo.
+type UpdateParticipantStatusRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// CorrectionRequest corrige los datos de un participante. Los campos vacíos conservan el
+// valor del archivo.
+type CorrectionRequest struct {
+	Name       string `json:"name"`
+	Sex        string `json:"sex"`
+	Category   string `json:"category"`
+	Modality   string `json:"modality"`
+	Club       string `json:"club"`
+	FinishTime string `json:"finishTime"`
+	Note       string `json:"note"`
+}
+
+// StartListImportResult es el resultado de cargar la lista de inscritos del evento
+type StartListImportResult struct {
+	EventID      string `json:"eventId"`
+	EntriesCount int    `json:"entriesCount"`
+	Message      string `json:"message"`
+	// Rejected indica que la carga superó el umbral de errores y no se guardaron los inscritos
+	Rejected bool                `json:"rejected"`
+	Report   domain.IngestReport `json:"report"`
+}
+
+// EventStartList es la lista de inscritos del evento
+type EventStartList struct {
+	StartList *domain.StartList        `json:"startList"`
+	Entries   []*domain.StartListEntry `json:"entries"`
+}
+
+// PublishStartListRequest muestra u oculta al público la lista de inscritos
+type PublishStartListRequest struct {
+	Published bool `json:"published"`
+}
+
+// StartListReconciliation compara los inscritos con los resultados publicados del evento
+type StartListReconciliation struct {
+	Registered int `json:"registered"`
+	// Matched son los inscritos que aparecen en los resultados
+	Matched int `json:"matched"`
+	// NotStarted son los inscritos sin resultado o con estado DNS
+	NotStarted []*domain.StartListEntry `json:"notStarted"`
+	// Unregistered son los participantes con un dorsal que no está en la lista de inscritos
+	Unregistered       []StartListParticipant      `json:"unregistered"`
+	CategoryMismatches []StartListCategoryMismatch `json:"categoryMismatches"`
+}
+
+// StartListParticipant es un participante de los resultados en la comparación con los inscritos
+type StartListParticipant struct {
+	Bib        string `json:"bib"`
+	Name       string `json:"name"`
+	Category   string `json:"category"`
+	RaceNumber int    `json:"raceNumber"`
+	RaceName   string `json:"raceName"`
+}
+
+// StartListCategoryMismatch es un participante cuya categoría no coincide con la inscrita
+type StartListCategoryMismatch struct {
+	StartListParticipant
+	RegisteredCategory string `json:"registeredCategory"`
+}
+
+// RaceSplits son las clasificaciones de una carrera en cada punto de control o vuelta
+type RaceSplits struct {
+	RaceNumber int            `json:"raceNumber"`
+	RaceName   string         `json:"raceName"`
+	Splits     []SplitRanking `json:"splits"`
+}
+
+// SplitRanking es la clasificación por tiempo acumulado en un punto de control o vuelta
+type SplitRanking struct {
+	Kind    string              `json:"kind"`
+	Number  int                 `json:"number"`
+	Column  string              `json:"column"`
+	Entries []SplitRankingEntry `json:"entries"`
+}
+
+// SplitRankingEntry es un participante en la clasificación de un punto de control o vuelta
+type SplitRankingEntry struct {
+	Position      int    `json:"position"`
+	Bib           string `json:"bib"`
+	Name          string `json:"name"`
+	Category      string `json:"category"`
+	Text          string `json:"text"`
+	ElapsedMs     int64  `json:"elapsedMs"`
+	GapToLeaderMs int64  `json:"gapToLeaderMs"`
+	LapMs         int64  `json:"lapMs"`
+	// LapPosition es la posición según el tiempo del tramo; 0 si no se conoce
+	LapPosition int `json:"lapPosition"`
+}
+
+// LapChart muestra la posición de cada participante en cada punto de control o vuelta
+type LapChart struct {
+	RaceNumber int    `json:"raceNumber"`
+	RaceName   string `json:"raceName"`
+	// Columns son los puntos de control y vueltas, en el orden de Positions
+	Columns      []string      `json:"columns"`
+	Participants []LapChartRow `json:"participants"`
+}
+
+// LapChartRow es la evolución de la posición de un participante durante la carrera
+type LapChartRow struct {
+	Bib      string `json:"bib"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+	Status   string `json:"status,omitempty"`
+	// Positions es la posición en cada columna; 0 si no pasó por ese punto de control
+	Positions []int `json:"positions"`
+}
+
+// EventTeams es la clasificación por equipos del evento
+type EventTeams struct {
+	Scoring   domain.TeamScoring `json:"scoring"`
+	Standings []TeamStanding     `json:"standings"`
+}
+
+// TeamStanding es la clasificación por equipos de una carrera o, si la clasificación es
+// por sexo, de un sexo dentro de la carrera
+type TeamStanding struct {
+	RaceNumber int          `json:"raceNumber"`
+	RaceName   string       `json:"raceName"`
+	Sex        string       `json:"sex,omitempty"`
+	Teams      []TeamResult `json:"teams"`
+}
+
+// TeamResult es el puntaje de un equipo. Score es la suma de los lugares o de los tiempos
+// en milisegundos de sus corredores que puntúan; Position es 0 si el equipo está incompleto.
+type TeamResult struct {
+	Position  int          `json:"position"`
+	Team      string       `json:"team"`
+	Score     int64        `json:"score"`
+	ScoreText string       `json:"scoreText"`
+	Complete  bool         `json:"complete"`
+	Members   []TeamMember `json:"members"`
+}
+
+// TeamMember es un corredor del equipo. Place es su lugar entre los que terminaron la
+// carrera (o su sexo); Scoring indica si suma al puntaje del equipo.
+type TeamMember struct {
+	Bib            string `json:"bib"`
+	Name           string `json:"name"`
+	Place          int    `json:"place"`
+	FinishTimeText string `json:"finishTimeText"`
+	FinishTimeMs   int64  `json:"finishTimeMs"`
+	Scoring        bool   `json:"scoring"`
+}
+
+// AthleteHistory es el historial de resultados de un atleta, del más reciente al más antiguo
+type AthleteHistory struct {
+	Athlete       *domain.Athlete       `json:"athlete"`
+	Results       []*AthleteEventResult `json:"results"`
+	PersonalBests []*PersonalBest       `json:"personalBests"`
+}
+
+// AthleteEventResult es un resultado del atleta junto con los datos del evento. ID es el
+// vínculo que se indica para separar el resultado del atleta.
+type AthleteEventResult struct {
+	ID         string        `json:"id"`
+	EventID    string        `json:"eventId"`
+	EventName  string        `json:"eventName"`
+	EventSlug  string        `json:"eventSlug"`
+	EventDate  time.Time     `json:"eventDate"`
+	RaceNumber int           `json:"raceNumber"`
+	RaceName   string        `json:"raceName"`
+	DistanceKm float64       `json:"distanceKm"`
+	Result     domain.Result `json:"result"`
+	Manual     bool          `json:"manual"`
+}
+
+// PersonalBest es el mejor tiempo del atleta en una distancia, p. ej. "10K"
+type PersonalBest struct {
+	Distance       string    `json:"distance"`
+	DistanceKm     float64   `json:"distanceKm"`
+	FinishTimeMs   int64     `json:"finishTimeMs"`
+	FinishTimeText string    `json:"finishTimeText"`
+	Pace           string    `json:"pace"`
+	ResultID       string    `json:"resultId"`
+	EventID        string    `json:"eventId"`
+	EventName      string    `json:"eventName"`
+	EventDate      time.Time `json:"eventDate"`
+}
+
+// UpdateAthleteRequest corrige el nombre para mostrar o el documento de un atleta
+type UpdateAthleteRequest struct {
+	Name       string `json:"name"`
+	NationalID string `json:"nationalId"`
+}
+
+// MergeAthletesRequest indica los atletas que se unen al atleta de la ruta
+type MergeAthletesRequest struct {
+	AthleteIDs []string `json:"athleteIds"`
+}
+
+// SplitAthleteRequest indica los resultados que se separan en un atleta nuevo
+type SplitAthleteRequest struct {
+	ResultIDs []string `json:"resultIds"`
+}
+
+// SeriesRequest crea o reemplaza un campeonato. EventIDs son las fechas en orden y
+// aceptan el ID o el slug del evento.
+type SeriesRequest struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	EventIDs    []string             `json:"eventIds"`
+	Scoring     domain.SeriesScoring `json:"scoring"`
+}
+
+type CommitUploadRequest struct {
+	Token string `json:"token"`
 }
 
 type CreateEventRequest struct {
@@ -33,9 +331,17 @@ type UpdateEventRequest struct {
 	FileExtension string `json:"fileExtension"`
 }
 
+// UpdateRaceDistanceRequest configura la distancia de una carrera. Una distancia de 0
+// vuelve a usar la obtenida a partir de la modalidad.
+type UpdateRaceDistanceRequest struct {
+	DistanceKm float64 `json:"distanceKm"`
+}
+
 type EventService interface {
-	Upload(file *multipart.FileHeader, clientHash string) (*UploadResult, error)
-	UploadToEvent(file *multipart.FileHeader, clientHash string, eventID string) (*UploadResult, error)
+	Upload(file *multipart.FileHeader, clientHash string, options UploadOptions) (*UploadResult, error)
+	UploadToEvent(file *multipart.FileHeader, clientHash string, eventID string, options UploadOptions) (*UploadResult, error)
+	PreviewUploadToEvent(file *multipart.FileHeader, clientHash string, eventID string, options UploadOptions) (*UploadPreview, error)
+	CommitUploadPreview(eventID string, token string) (*UploadResult, error)
 	CreateEvent(req *CreateEventRequest) (*domain.Event, error)
 	GetEvent(id string) (*domain.Event, error)
 	GetEventBySlug(slug string) (*domain.Event, error)
@@ -45,6 +351,62 @@ type EventService interface {
 	UpdateEventStatus(id string, status string) (*domain.Event, error)
 	GetEvents(name *string, date *time.Time, page int, limit int) (*FindEventsResult, error)
 	GetEventsWithFilter(name *string, date *time.Time, page int, limit int, includeHidden bool) (*FindEventsResult, error)
-	GetParticipants(eventID string, name, chip, dorsal, category, distance, sex, position *string, page int, limit int) (*FindParticipantsResult, error)
+	GetParticipants(eventID string, name, chip, dorsal, category, distance, sex, position, race *string, page int, limit int) (*FindParticipantsResult, error)
+	GetEventRaces(eventID string) ([]domain.Race, error)
+	GetEventUploads(eventID string) ([]*domain.Upload, error)
+	GetEventChanges(eventID string, bib *string) ([]*domain.ChangeLogEntry, error)
+	GetEventVersions(eventID string) ([]*domain.EventVersion, error)
+	GetEventVersion(eventID string, versionID string) (*EventVersionDetail, error)
+	GetEventVersionFile(eventID string, versionID string) (*domain.EventVersion, error)
+	RollbackEventVersion(eventID string, versionID string) (*UploadResult, error)
+	ExportEvent(eventID string, format string, filter ExportFilter) (*ExportFile, error)
+	UpdateCertificateTemplate(eventID string, req *UpdateCertificateTemplateRequest) (*domain.Event, error)
+	GetParticipantCertificate(eventID string, bib string, format string) (*ExportFile, error)
+	UpdateTeamScoring(eventID string, req *UpdateTeamScoringRequest) (*domain.Event, error)
+	GetEventTeams(eventID string) (*EventTeams, error)
+	UpdateRankingSource(eventID string, req *UpdateRankingSourceRequest) (*domain.Event, error)
+	GetParticipantStatuses(eventID string) ([]*domain.ParticipantStatus, error)
+	UpdateParticipantStatus(eventID string, bib string, req *UpdateParticipantStatusRequest) (*domain.EventData, error)
+	GetCorrections(eventID string) ([]*domain.ResultCorrection, error)
+	GetCorrection(eventID string, bib string) (*domain.ResultCorrection, error)
+	SaveCorrection(eventID string, bib string, req *CorrectionRequest, user string) (*domain.ResultCorrection, error)
+	DeleteCorrection(eventID string, bib string, user string) error
+	GetCorrectionAudit(eventID string, bib *string) ([]*domain.CorrectionAuditEntry, error)
+	ImportStartList(fileHeader *multipart.FileHeader, eventID string, options UploadOptions) (*StartListImportResult, error)
+	GetStartList(eventID string, includeHidden bool) (*EventStartList, error)
+	PublishStartList(eventID string, req *PublishStartListRequest) (*domain.Event, error)
+	DeleteStartList(eventID string) error
+	ReconcileStartList(eventID string) (*StartListReconciliation, error)
+	GetRaceSplits(eventID string, raceNumber int) (*RaceSplits, error)
+	GetRaceLapChart(eventID string, raceNumber int) (*LapChart, error)
+	UpdateRaceDistance(eventID string, raceNumber int, distanceKm float64) (*domain.Race, error)
 	GetParticipantComparison(eventID string, bib string, distance string, category string) (*ComparisonResult, error)
+	MigrateLegacyResults() (int, error)
+}
+
+// AthleteService mantiene el registro de atletas que vincula los resultados de un mismo
+// corredor en distintos eventos
+type AthleteService interface {
+	LinkEventResults(eventID primitive.ObjectID, data []domain.EventData) error
+	UnlinkEvent(eventID primitive.ObjectID) error
+	LinkExistingEvents() (int, error)
+	GetAthletes(name *string, page int, limit int) (*FindAthletesResult, error)
+	GetAthlete(id string) (*domain.Athlete, error)
+	GetAthleteResults(id string, includeHidden bool) (*AthleteHistory, error)
+	UpdateAthlete(id string, req *UpdateAthleteRequest) (*domain.Athlete, error)
+	MergeAthletes(id string, req *MergeAthletesRequest) (*domain.Athlete, error)
+	SplitAthlete(id string, req *SplitAthleteRequest) (*domain.Athlete, error)
+}
+
+// SeriesService administra los campeonatos de varias fechas y calcula sus clasificaciones
+type SeriesService interface {
+	CreateSeries(req *SeriesRequest) (*domain.Series, error)
+	GetSeriesList(page int, limit int) (*FindSeriesResult, error)
+	GetSeries(id string) (*domain.Series, error)
+	GetSeriesBySlug(slug string) (*domain.Series, error)
+	UpdateSeries(id string, req *SeriesRequest) (*domain.Series, error)
+	DeleteSeries(id string) error
+	GetStandings(id string, category *string) (*domain.SeriesStandings, error)
+	RecalculateEventSeries(eventID primitive.ObjectID) error
+	RemoveEvent(eventID primitive.ObjectID) error
 }
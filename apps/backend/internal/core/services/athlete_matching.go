@@ -0,0 +1,323 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"backend/internal/utils"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// nationalIDColumns son las columnas, normalizadas con utils.NormalizeKey, que traen el
+// documento del corredor. No son columnas del formato racecheck, por lo que quedan en Extras.
+var nationalIDColumns = map[string]bool{
+	"RUT":        true,
+	"RUN":        true,
+	"DNI":        true,
+	"CEDULA":     true,
+	"DOCUMENTO":  true,
+	"NATIONALID": true,
+}
+
+var nonAlphanumericPattern = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// normalizeNationalID deja solo letras y dígitos, p. ej. "12.345.678-k" -> "12345678K"
+func normalizeNationalID(value string) string {
+	id := nonAlphanumericPattern.ReplaceAllString(strings.ToUpper(value), "")
+	return strings.TrimLeft(id, "0")
+}
+
+// normalizeSex unifica las formas en que los archivos indican el sexo. Retorna "M", "F"
+// o el valor normalizado si no es una forma conocida.
+func normalizeSex(value string) string {
+	switch key := utils.NormalizeKey(value); key {
+	case "M", "H", "V", "MASCULINO", "HOMBRE", "HOMBRES", "VARON", "VARONES", "MALE":
+		return "M"
+	case "F", "D", "FEMENINO", "MUJER", "MUJERES", "DAMA", "DAMAS", "FEMALE":
+		return "F"
+	default:
+		return key
+	}
+}
+
+func sexCompatible(a, b string) bool {
+	return a == "" || b == "" || a == b
+}
+
+// athleteIdentity son los datos de un resultado que permiten reconocer al corredor
+type athleteIdentity struct {
+	name       string
+	nationalID string
+	chip       string
+	sex        string
+}
+
+func identityOf(result domain.Result) athleteIdentity {
+	identity := athleteIdentity{
+		name: utils.NormalizeName(result.Name),
+		chip: strings.ToUpper(strings.TrimSpace(result.Chip)),
+		sex:  normalizeSex(result.Sex),
+	}
+	for column, value := range result.Extras {
+		if nationalIDColumns[utils.NormalizeKey(column)] {
+			if id := normalizeNationalID(value); id != "" {
+				identity.nationalID = id
+				break
+			}
+		}
+	}
+	return identity
+}
+
+// sharesNameWord indica si el atleta tiene alguna palabra de al menos 3 letras en común
+// con el nombre normalizado
+func sharesNameWord(athlete *domain.Athlete, name string) bool {
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(name) {
+		if len(word) >= 3 {
+			words[word] = true
+		}
+	}
+	for _, known := range athlete.NormalizedNames {
+		for _, word := range strings.Fields(known) {
+			if words[word] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// absorbIdentity agrega al atleta los datos del resultado que aún no tiene y retorna si cambió
+func absorbIdentity(athlete *domain.Athlete, identity athleteIdentity, displayName string) bool {
+	changed := false
+	if identity.name != "" && !containsString(athlete.NormalizedNames, identity.name) {
+		athlete.NormalizedNames = append(athlete.NormalizedNames, identity.name)
+		changed = true
+	}
+	if identity.chip != "" && !containsString(athlete.Chips, identity.chip) {
+		athlete.Chips = append(athlete.Chips, identity.chip)
+		changed = true
+	}
+	if athlete.NationalID == "" && identity.nationalID != "" {
+		athlete.NationalID = identity.nationalID
+		changed = true
+	}
+	if athlete.Sex == "" && identity.sex != "" {
+		athlete.Sex = identity.sex
+		changed = true
+	}
+	if athlete.Name == "" && strings.TrimSpace(displayName) != "" {
+		athlete.Name = strings.TrimSpace(displayName)
+		changed = true
+	}
+	return changed
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// athleteMatcher empareja los resultados de un evento con los atletas conocidos. Un atleta
+// recibe a lo sumo un resultado por evento, y los atletas creados o ampliados se indexan
+// de inmediato para los resultados siguientes.
+type athleteMatcher struct {
+	byID         map[primitive.ObjectID]*domain.Athlete
+	byNationalID map[string]*domain.Athlete
+	byName       map[string][]*domain.Athlete
+	byChip       map[string][]*domain.Athlete
+	used         map[*domain.Athlete]bool
+	created      []*domain.Athlete
+	changed      map[*domain.Athlete]bool
+}
+
+func newAthleteMatcher(athletes []*domain.Athlete) *athleteMatcher {
+	m := &athleteMatcher{
+		byID:         make(map[primitive.ObjectID]*domain.Athlete),
+		byNationalID: make(map[string]*domain.Athlete),
+		byName:       make(map[string][]*domain.Athlete),
+		byChip:       make(map[string][]*domain.Athlete),
+		used:         make(map[*domain.Athlete]bool),
+		changed:      make(map[*domain.Athlete]bool),
+	}
+	for _, athlete := range athletes {
+		if _, ok := m.byID[athlete.ID]; ok {
+			continue
+		}
+		m.byID[athlete.ID] = athlete
+		m.index(athlete)
+	}
+	return m
+}
+
+func (m *athleteMatcher) index(athlete *domain.Athlete) {
+	if athlete.NationalID != "" {
+		if _, ok := m.byNationalID[athlete.NationalID]; !ok {
+			m.byNationalID[athlete.NationalID] = athlete
+		}
+	}
+	for _, name := range athlete.NormalizedNames {
+		if !containsAthlete(m.byName[name], athlete) {
+			m.byName[name] = append(m.byName[name], athlete)
+		}
+	}
+	for _, chip := range athlete.Chips {
+		if !containsAthlete(m.byChip[chip], athlete) {
+			m.byChip[chip] = append(m.byChip[chip], athlete)
+		}
+	}
+}
+
+func containsAthlete(athletes []*domain.Athlete, athlete *domain.Athlete) bool {
+	for _, a := range athletes {
+		if a == athlete {
+			return true
+		}
+	}
+	return false
+}
+
+// keep reserva para el evento un atleta cuyo vínculo se conserva y le agrega los datos
+// del resultado actual
+func (m *athleteMatcher) keep(athlete *domain.Athlete, result domain.Result) {
+	m.used[athlete] = true
+	if absorbIdentity(athlete, identityOf(result), result.Name) {
+		m.changed[athlete] = true
+		m.index(athlete)
+	}
+}
+
+// match retorna el atleta del resultado, creándolo si no se encuentra. Retorna nil si el
+// resultado no tiene nombre ni documento con que identificarlo.
+func (m *athleteMatcher) match(result domain.Result) *domain.Athlete {
+	identity := identityOf(result)
+	if identity.name == "" && identity.nationalID == "" {
+		return nil
+	}
+
+	athlete := m.find(identity)
+	if athlete == nil {
+		athlete = &domain.Athlete{ID: primitive.NewObjectID(), NormalizedNames: []string{}, Chips: []string{}}
+		m.byID[athlete.ID] = athlete
+		m.created = append(m.created, athlete)
+	}
+
+	m.used[athlete] = true
+	if absorbIdentity(athlete, identity, result.Name) {
+		m.changed[athlete] = true
+	}
+	m.index(athlete)
+	return athlete
+}
+
+// find busca al atleta primero por documento, luego por nombre normalizado usando el chip
+// para elegir entre homónimos, y por último por chip si además comparte una palabra del
+// nombre, ya que los chips se reutilizan entre eventos
+func (m *athleteMatcher) find(identity athleteIdentity) *domain.Athlete {
+	if identity.nationalID != "" {
+		if athlete, ok := m.byNationalID[identity.nationalID]; ok {
+			if m.used[athlete] {
+				return nil
+			}
+			return athlete
+		}
+	}
+
+	available := func(athlete *domain.Athlete) bool {
+		if m.used[athlete] || !sexCompatible(athlete.Sex, identity.sex) {
+			return false
+		}
+		return athlete.NationalID == "" || identity.nationalID == "" || athlete.NationalID == identity.nationalID
+	}
+
+	var candidates []*domain.Athlete
+	for _, athlete := range m.byName[identity.name] {
+		if available(athlete) {
+			candidates = append(candidates, athlete)
+		}
+	}
+	if len(candidates) > 0 {
+		if identity.chip != "" {
+			for _, athlete := range candidates {
+				if containsString(athlete.Chips, identity.chip) {
+					return athlete
+				}
+			}
+		}
+		return candidates[0]
+	}
+
+	if identity.chip != "" {
+		for _, athlete := range m.byChip[identity.chip] {
+			if available(athlete) && sharesNameWord(athlete, identity.name) {
+				return athlete
+			}
+		}
+	}
+	return nil
+}
+
+// rebuildIdentity recalcula los nombres, chips y sexo de un atleta a partir de sus
+// resultados, p. ej. después de separar algunos en otro atleta
+func rebuildIdentity(athlete *domain.Athlete, results []*domain.AthleteResult) {
+	athlete.NormalizedNames = []string{}
+	athlete.Chips = []string{}
+	athlete.Sex = ""
+	for _, result := range results {
+		absorbIdentity(athlete, identityOf(result.Result), result.Result.Name)
+	}
+	if !containsString(athlete.NormalizedNames, utils.NormalizeName(athlete.Name)) && len(results) > 0 {
+		athlete.Name = strings.TrimSpace(results[0].Result.Name)
+	}
+}
+
+// distanceLabel agrupa las distancias para las mejores marcas, p. ej. 21.0975 -> "21.1K"
+func distanceLabel(distanceKm float64) string {
+	return strconv.FormatFloat(math.Round(distanceKm*10)/10, 'f', -1, 64) + "K"
+}
+
+// computePersonalBests retorna el mejor tiempo por distancia, de la distancia más corta a
+// la más larga. Ante un empate se conserva el resultado más antiguo. Los resultados con un
+// estado (DNF, DNS, DSQ, OTL) no cuentan aunque el archivo traiga un tiempo.
+func computePersonalBests(results []*ports.AthleteEventResult) []*ports.PersonalBest {
+	best := make(map[string]*ports.PersonalBest)
+	for _, result := range results {
+		if result.DistanceKm <= 0 || result.Result.FinishTimeMs <= 0 || result.Result.Status != "" {
+			continue
+		}
+		label := distanceLabel(result.DistanceKm)
+		current, ok := best[label]
+		if ok && (current.FinishTimeMs < result.Result.FinishTimeMs ||
+			current.FinishTimeMs == result.Result.FinishTimeMs && !result.EventDate.Before(current.EventDate)) {
+			continue
+		}
+		best[label] = &ports.PersonalBest{
+			Distance:       label,
+			DistanceKm:     math.Round(result.DistanceKm*10) / 10,
+			FinishTimeMs:   result.Result.FinishTimeMs,
+			FinishTimeText: result.Result.FinishTimeText,
+			Pace:           result.Result.Pace,
+			ResultID:       result.ID,
+			EventID:        result.EventID,
+			EventName:      result.EventName,
+			EventDate:      result.EventDate,
+		}
+	}
+
+	bests := make([]*ports.PersonalBest, 0, len(best))
+	for _, pb := range best {
+		bests = append(bests, pb)
+	}
+	sort.Slice(bests, func(i, j int) bool { return bests[i].DistanceKm < bests[j].DistanceKm })
+	return bests
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"backend/internal/utils"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := map[string]string{
+		"Cristóbal Inostroza":  "cristobal inostroza",
+		"INOSTROZA, Cristobal": "cristobal inostroza",
+		"  maría  josé PÉREZ ": "jose maria perez",
+		"":                     "",
+	}
+	for input, expected := range tests {
+		if got := utils.NormalizeName(input); got != expected {
+			t.Errorf("NormalizeName(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestAthleteMatcherMatchesAcrossEvents(t *testing.T) {
+	known := &domain.Athlete{
+		Name:            "Cristobal Inostroza",
+		NormalizedNames: []string{"cristobal inostroza"},
+		Chips:           []string{"QT00121"},
+		Sex:             "M",
+	}
+	matcher := newAthleteMatcher([]*domain.Athlete{known})
+
+	if got := matcher.match(domain.Result{Name: "INOSTROZA, Cristóbal", Sex: "M", Chip: "RU00300"}); got != known {
+		t.Fatalf("expected the same runner to match by normalized name, got %+v", got)
+	}
+	if !containsString(known.Chips, "RU00300") || !matcher.changed[known] {
+		t.Errorf("expected the new chip to be added, chips = %v", known.Chips)
+	}
+
+	// Un atleta recibe un solo resultado por evento: un homónimo es otro corredor
+	namesake := matcher.match(domain.Result{Name: "Cristobal Inostroza", Sex: "M"})
+	if namesake == nil || namesake == known || len(matcher.created) != 1 {
+		t.Errorf("expected a new athlete for the second result with the same name, got %+v", namesake)
+	}
+}
+
+func TestAthleteMatcherNationalID(t *testing.T) {
+	known := &domain.Athlete{
+		Name:            "Ana Rojas",
+		NormalizedNames: []string{"ana rojas"},
+		NationalID:      "123456789",
+		Sex:             "F",
+	}
+	matcher := newAthleteMatcher([]*domain.Athlete{known})
+
+	result := domain.Result{Name: "Ana María Rojas Soto", Sex: "F", Extras: map[string]string{"RUT": "12.345.678-9"}}
+	if got := matcher.match(result); got != known {
+		t.Fatalf("expected a match by national id, got %+v", got)
+	}
+
+	// Mismo nombre pero otro documento: no es la misma persona
+	matcher = newAthleteMatcher([]*domain.Athlete{known})
+	other := domain.Result{Name: "Ana Rojas", Sex: "F", Extras: map[string]string{"Cédula": "98765432"}}
+	if got := matcher.match(other); got == known {
+		t.Error("a different national id must not match by name")
+	}
+}
+
+func TestAthleteMatcherChipNeedsSharedName(t *testing.T) {
+	known := &domain.Athlete{
+		Name:            "Martin Hernan Paiva Perez",
+		NormalizedNames: []string{utils.NormalizeName("Martin Hernan Paiva Pérez")},
+		Chips:           []string{"QT00064"},
+		Sex:             "M",
+	}
+
+	matcher := newAthleteMatcher([]*domain.Athlete{known})
+	if got := matcher.match(domain.Result{Name: "Martin Paiva", Sex: "M", Chip: "qt00064"}); got != known {
+		t.Errorf("expected a match by chip and shared name, got %+v", got)
+	}
+
+	// Los chips se reutilizan entre eventos: otro nombre con el mismo chip es otro corredor
+	matcher = newAthleteMatcher([]*domain.Athlete{known})
+	if got := matcher.match(domain.Result{Name: "Miguel Angel Miranda Fernandez", Sex: "M", Chip: "QT00064"}); got == known {
+		t.Error("a reused chip must not match a runner with a different name")
+	}
+
+	matcher = newAthleteMatcher([]*domain.Athlete{known})
+	if got := matcher.match(domain.Result{Name: "Martin Paiva", Sex: "F", Chip: "QT00064"}); got == known {
+		t.Error("a result of another sex must not match")
+	}
+}
+
+func TestAthleteMatcherPrefersChipAmongNamesakes(t *testing.T) {
+	first := &domain.Athlete{ID: primitive.NewObjectID(), NormalizedNames: []string{"juan perez"}, Chips: []string{"QT001"}}
+	second := &domain.Athlete{ID: primitive.NewObjectID(), NormalizedNames: []string{"juan perez"}, Chips: []string{"QT002"}}
+	matcher := newAthleteMatcher([]*domain.Athlete{first, second})
+
+	if got := matcher.match(domain.Result{Name: "Juan Pérez", Chip: "QT002"}); got != second {
+		t.Errorf("expected the namesake with the same chip, got %+v", got)
+	}
+	if got := matcher.match(domain.Result{Name: "Juan Perez"}); got != first {
+		t.Errorf("expected the remaining namesake, got %+v", got)
+	}
+}
+
+func TestAthleteMatcherSkipsUnidentifiedResults(t *testing.T) {
+	matcher := newAthleteMatcher(nil)
+	if got := matcher.match(domain.Result{Bib: "10", Chip: "QT010"}); got != nil {
+		t.Errorf("expected no athlete for a result without name, got %+v", got)
+	}
+}
+
+func TestAthleteResultKeysNumberDuplicates(t *testing.T) {
+	data := []domain.EventData{
+		{Result: domain.Result{Bib: "7", Name: "Ana"}},
+		{Result: domain.Result{Bib: "7", Name: "Luis"}},
+		{Result: domain.Result{Bib: "8", Name: "Pedro"}},
+	}
+	keys := athleteResultKeys(data)
+	expected := []string{"bib:7", "bib:7#2", "bib:8"}
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Errorf("keys[%d] = %q, expected %q", i, keys[i], expected[i])
+		}
+	}
+}
+
+func TestRebuildIdentity(t *testing.T) {
+	athlete := &domain.Athlete{Name: "Juan Perez", NormalizedNames: []string{"juan perez", "juan pablo perez"}, Chips: []string{"A", "B"}}
+	rebuildIdentity(athlete, []*domain.AthleteResult{
+		{Result: domain.Result{Name: "Juan Pablo Pérez", Chip: "B", Sex: "Masculino"}},
+	})
+
+	if athlete.Name != "Juan Pablo Pérez" {
+		t.Errorf("name = %q, expected the name of the remaining result", athlete.Name)
+	}
+	if len(athlete.NormalizedNames) != 1 || len(athlete.Chips) != 1 || athlete.Chips[0] != "B" || athlete.Sex != "M" {
+		t.Errorf("identity = %+v", athlete)
+	}
+}
+
+func TestComputePersonalBests(t *testing.T) {
+	date := func(year int) time.Time { return time.Date(year, 3, 10, 0, 0, 0, 0, time.UTC) }
+	results := []*ports.AthleteEventResult{
+		{ID: "a", EventName: "Casablanca 2024", EventDate: date(2024), DistanceKm: 10, Result: domain.Result{FinishTimeMs: 2_100_000}},
+		{ID: "b", EventName: "San José 2023", EventDate: date(2023), DistanceKm: 10, Result: domain.Result{FinishTimeMs: 2_017_000}},
+		{ID: "c", EventName: "San José 2025", EventDate: date(2025), DistanceKm: 10, Result: domain.Result{FinishTimeMs: 2_017_000}},
+		{ID: "d", EventName: "Media 2024", EventDate: date(2024), DistanceKm: 21.0975, Result: domain.Result{FinishTimeMs: 5_400_000}},
+		{ID: "e", EventName: "DNF", EventDate: date(2024), DistanceKm: 21.1, Result: domain.Result{FinishTimeMs: 0}},
+		{ID: "f", EventName: "Sin distancia", EventDate: date(2024), Result: domain.Result{FinishTimeMs: 1_000_000}},
+		{ID: "g", EventName: "Fuera de tiempo", EventDate: date(2024), DistanceKm: 21.1, Result: domain.Result{FinishTimeMs: 5_000_000, Status: domain.ResultStatusOTL}},
+	}
+
+	bests := computePersonalBests(results)
+	if len(bests) != 2 {
+		t.Fatalf("expected personal bests for 10K and 21.1K, got %d", len(bests))
+	}
+	if bests[0].Distance != "10K" || bests[0].ResultID != "b" {
+		t.Errorf("10K best = %+v, expected the earliest of the tied results", bests[0])
+	}
+	if bests[1].Distance != "21.1K" || bests[1].ResultID != "d" {
+		t.Errorf("21.1K best = %+v", bests[1])
+	}
+}
@@ -0,0 +1,509 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"backend/internal/utils"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type athleteService struct {
+	athleteRepository ports.AthleteRepository
+	eventRepository   ports.EventRepository
+}
+
+func NewAthleteService(athleteRepository ports.AthleteRepository, eventRepository ports.EventRepository) ports.AthleteService {
+	return &athleteService{
+		athleteRepository: athleteRepository,
+		eventRepository:   eventRepository,
+	}
+}
+
+// LinkEventResults vincula los participantes publicados del evento con el registro de
+// atletas. Los vínculos de los participantes que siguen en el evento se conservan mientras
+// el nombre o el documento coincidan con el atleta, o siempre si los asignó un administrador;
+// los demás participantes se emparejan de nuevo y los vínculos de los que ya no están se
+// eliminan junto con los atletas que quedan sin resultados.
+func (s *athleteService) LinkEventResults(eventID primitive.ObjectID, data []domain.EventData) error {
+	existing, err := s.athleteRepository.FindEventAthleteResults(eventID)
+	if err != nil {
+		return fmt.Errorf("could not load athlete results: %w", err)
+	}
+	linksByKey := make(map[string]*domain.AthleteResult, len(existing))
+	var linkedAthleteIDs []primitive.ObjectID
+	for _, link := range existing {
+		linksByKey[link.ResultKey] = link
+		linkedAthleteIDs = append(linkedAthleteIDs, link.AthleteID)
+	}
+
+	keys := athleteResultKeys(data)
+	var nationalIDs, names, chips []string
+	for _, eventData := range data {
+		identity := identityOf(eventData.Result)
+		if identity.nationalID != "" {
+			nationalIDs = append(nationalIDs, identity.nationalID)
+		}
+		if identity.name != "" {
+			names = append(names, identity.name)
+		}
+		if identity.chip != "" {
+			chips = append(chips, identity.chip)
+		}
+	}
+
+	candidates, err := s.athleteRepository.FindAthletesByIdentity(nationalIDs, names, chips)
+	if err != nil {
+		return fmt.Errorf("could not find athletes: %w", err)
+	}
+	if len(linkedAthleteIDs) > 0 {
+		linked, err := s.athleteRepository.FindAthletesByIDs(linkedAthleteIDs)
+		if err != nil {
+			return fmt.Errorf("could not find linked athletes: %w", err)
+		}
+		candidates = append(linked, candidates...)
+	}
+	matcher := newAthleteMatcher(candidates)
+
+	// Primero se reservan los atletas de los vínculos que se conservan para que el
+	// emparejamiento de los demás participantes no los asigne a otro resultado
+	links := make([]*domain.AthleteResult, len(data))
+	var pending []int
+	for i, eventData := range data {
+		link, ok := linksByKey[keys[i]]
+		if !ok {
+			pending = append(pending, i)
+			continue
+		}
+		delete(linksByKey, keys[i])
+
+		athlete := matcher.byID[link.AthleteID]
+		if athlete == nil || !(link.Manual || sameAthlete(athlete, eventData.Result)) {
+			link.Manual = false
+			links[i] = link
+			pending = append(pending, i)
+			continue
+		}
+		matcher.keep(athlete, eventData.Result)
+		links[i] = link
+	}
+
+	for _, i := range pending {
+		athlete := matcher.match(data[i].Result)
+		if athlete == nil {
+			if links[i] != nil {
+				// El participante ya no se puede identificar; se elimina su vínculo
+				linksByKey[keys[i]] = links[i]
+				links[i] = nil
+			}
+			continue
+		}
+		if links[i] == nil {
+			links[i] = &domain.AthleteResult{EventID: eventID, ResultKey: keys[i]}
+		}
+		links[i].AthleteID = athlete.ID
+	}
+
+	var toSave []*domain.AthleteResult
+	for i, link := range links {
+		if link == nil {
+			continue
+		}
+		link.RaceNumber = data[i].RaceNumber
+		link.RaceName = data[i].RaceName
+		link.Result = data[i].Result
+		toSave = append(toSave, link)
+	}
+
+	for _, athlete := range matcher.created {
+		if err := s.athleteRepository.SaveAthlete(athlete); err != nil {
+			return fmt.Errorf("could not save athlete: %w", err)
+		}
+		delete(matcher.changed, athlete)
+	}
+	for athlete := range matcher.changed {
+		if err := s.athleteRepository.UpdateAthlete(athlete); err != nil {
+			return fmt.Errorf("could not update athlete: %w", err)
+		}
+	}
+	if err := s.athleteRepository.SaveAthleteResults(toSave); err != nil {
+		return err
+	}
+
+	var removed []primitive.ObjectID
+	for _, link := range linksByKey {
+		removed = append(removed, link.ID)
+	}
+	if err := s.athleteRepository.DeleteAthleteResults(removed); err != nil {
+		return fmt.Errorf("could not delete athlete results: %w", err)
+	}
+	if err := s.athleteRepository.DeleteOrphanAthletes(linkedAthleteIDs); err != nil {
+		return fmt.Errorf("could not delete athletes without results: %w", err)
+	}
+	return nil
+}
+
+// athleteResultKeys retorna el resultKey de cada participante. Si dos participantes tienen
+// la misma clave, p. ej. un dorsal repetido, los siguientes se numeran.
+func athleteResultKeys(data []domain.EventData) []string {
+	keys := make([]string, len(data))
+	seen := make(map[string]int, len(data))
+	for i := range data {
+		key := resultKey(&data[i])
+		seen[key]++
+		if seen[key] > 1 {
+			key += "#" + strconv.Itoa(seen[key])
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// sameAthlete indica si el resultado sigue correspondiendo al atleta vinculado
+func sameAthlete(athlete *domain.Athlete, result domain.Result) bool {
+	identity := identityOf(result)
+	if identity.nationalID != "" && athlete.NationalID != "" {
+		return identity.nationalID == athlete.NationalID
+	}
+	return containsString(athlete.NormalizedNames, identity.name)
+}
+
+// UnlinkEvent elimina los vínculos de un evento borrado y los atletas que quedan sin resultados
+func (s *athleteService) UnlinkEvent(eventID primitive.ObjectID) error {
+	athleteIDs, err := s.athleteRepository.DeleteEventAthleteResults(eventID)
+	if err != nil {
+		return fmt.Errorf("could not delete athlete results: %w", err)
+	}
+	if err := s.athleteRepository.DeleteOrphanAthletes(athleteIDs); err != nil {
+		return fmt.Errorf("could not delete athletes without results: %w", err)
+	}
+	return nil
+}
+
+// LinkExistingEvents vincula los eventos que tienen participantes pero aún no tienen
+// atletas, p. ej. los cargados antes de existir el registro. Retorna cuántos vinculó.
+func (s *athleteService) LinkExistingEvents() (int, error) {
+	eventIDs, err := s.eventRepository.FindEventIDsWithRecords()
+	if err != nil {
+		return 0, fmt.Errorf("could not get events: %w", err)
+	}
+
+	linked := 0
+	for _, eventID := range eventIDs {
+		count, err := s.athleteRepository.CountEventAthleteResults(eventID)
+		if err != nil {
+			return linked, fmt.Errorf("could not count athlete results: %w", err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		data, err := s.eventRepository.FindAllData(eventID)
+		if err != nil {
+			return linked, fmt.Errorf("could not load event data: %w", err)
+		}
+		eventData := make([]domain.EventData, len(data))
+		for i, item := range data {
+			eventData[i] = *item
+		}
+		if err := s.LinkEventResults(eventID, eventData); err != nil {
+			return linked, fmt.Errorf("could not link event %s: %w", eventID.Hex(), err)
+		}
+		linked++
+	}
+	return linked, nil
+}
+
+func (s *athleteService) GetAthletes(name *string, page int, limit int) (*ports.FindAthletesResult, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+
+	result, err := s.athleteRepository.FindAthletes(name, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("could not get athletes: %w", err)
+	}
+	return result, nil
+}
+
+func (s *athleteService) GetAthlete(id string) (*domain.Athlete, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidObjectID, err)
+	}
+
+	athlete, err := s.athleteRepository.FindAthleteByID(objID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get athlete: %w", err)
+	}
+	if athlete == nil {
+		return nil, ErrAthleteNotFound
+	}
+	return athlete, nil
+}
+
+// GetAthleteResults retorna el historial del atleta con sus mejores marcas por distancia.
+// Los eventos ocultos solo se incluyen con includeHidden.
+func (s *athleteService) GetAthleteResults(id string, includeHidden bool) (*ports.AthleteHistory, error) {
+	athlete, err := s.GetAthlete(id)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := s.athleteRepository.FindAthleteResults(athlete.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get athlete results: %w", err)
+	}
+
+	events := make(map[primitive.ObjectID]*domain.Event)
+	results := make([]*ports.AthleteEventResult, 0, len(links))
+	for _, link := range links {
+		event, ok := events[link.EventID]
+		if !ok {
+			event, err = s.eventRepository.FindByID(link.EventID)
+			if err != nil {
+				return nil, fmt.Errorf("could not get event: %w", err)
+			}
+			events[link.EventID] = event
+		}
+		if event == nil || (!includeHidden && event.Status == "HIDDEN") {
+			continue
+		}
+		results = append(results, newAthleteEventResult(event, link))
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if !results[i].EventDate.Equal(results[j].EventDate) {
+			return results[i].EventDate.After(results[j].EventDate)
+		}
+		return results[i].RaceNumber < results[j].RaceNumber
+	})
+
+	return &ports.AthleteHistory{
+		Athlete:       athlete,
+		Results:       results,
+		PersonalBests: computePersonalBests(results),
+	}, nil
+}
+
+// newAthleteEventResult arma un resultado del historial. La distancia se toma de la carrera
+// del evento, que un administrador puede haber corregido después de vincular el resultado.
+func newAthleteEventResult(event *domain.Event, link *domain.AthleteResult) *ports.AthleteEventResult {
+	result := &ports.AthleteEventResult{
+		ID:         link.ID.Hex(),
+		EventID:    event.ID.Hex(),
+		EventName:  event.Name,
+		EventSlug:  event.Slug,
+		EventDate:  event.Date,
+		RaceNumber: link.RaceNumber,
+		RaceName:   link.RaceName,
+		Result:     link.Result,
+		Manual:     link.Manual,
+	}
+
+	for _, race := range event.Races {
+		if race.Number == link.RaceNumber {
+			result.DistanceKm = race.DistanceKm
+			break
+		}
+	}
+	if result.DistanceKm == 0 {
+		if distance, ok := parseDistanceKm(link.Result.Modality); ok {
+			result.DistanceKm = distance
+		}
+	}
+
+	if result.DistanceKm > 0 && result.Result.FinishTimeMs > 0 {
+		result.Result.PaceMsPerKm = int64(float64(result.Result.FinishTimeMs) / result.DistanceKm)
+		result.Result.Pace = formatPace(result.Result.PaceMsPerKm)
+	}
+	return result
+}
+
+// UpdateAthlete corrige el nombre para mostrar y el documento del atleta. Un documento
+// vacío lo elimina.
+func (s *athleteService) UpdateAthlete(id string, req *ports.UpdateAthleteRequest) (*domain.Athlete, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidAthleteRequest)
+	}
+
+	athlete, err := s.GetAthlete(id)
+	if err != nil {
+		return nil, err
+	}
+
+	nationalID := normalizeNationalID(req.NationalID)
+	if nationalID != "" && nationalID != athlete.NationalID {
+		others, err := s.athleteRepository.FindAthletesByIdentity([]string{nationalID}, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not find athletes: %w", err)
+		}
+		for _, other := range others {
+			if other.ID != athlete.ID {
+				return nil, fmt.Errorf("%w: national id already belongs to athlete %s", ErrInvalidAthleteRequest, other.ID.Hex())
+			}
+		}
+	}
+
+	athlete.Name = name
+	athlete.NationalID = nationalID
+	if normalized := utils.NormalizeName(name); normalized != "" && !containsString(athlete.NormalizedNames, normalized) {
+		athlete.NormalizedNames = append(athlete.NormalizedNames, normalized)
+	}
+
+	if err := s.athleteRepository.UpdateAthlete(athlete); err != nil {
+		return nil, fmt.Errorf("could not update athlete: %w", err)
+	}
+	return athlete, nil
+}
+
+// MergeAthletes une los atletas indicados al atleta id: sus resultados pasan a este atleta
+// como vínculos manuales, se suman sus nombres y chips, y los atletas unidos se eliminan
+func (s *athleteService) MergeAthletes(id string, req *ports.MergeAthletesRequest) (*domain.Athlete, error) {
+	target, err := s.GetAthlete(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.AthleteIDs) == 0 {
+		return nil, fmt.Errorf("%w: athleteIds is required", ErrInvalidAthleteRequest)
+	}
+
+	sourceIDs := make([]primitive.ObjectID, 0, len(req.AthleteIDs))
+	for _, sourceID := range req.AthleteIDs {
+		objID, err := primitive.ObjectIDFromHex(sourceID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidObjectID, err)
+		}
+		if objID == target.ID {
+			return nil, fmt.Errorf("%w: an athlete cannot be merged into itself", ErrInvalidAthleteRequest)
+		}
+		sourceIDs = append(sourceIDs, objID)
+	}
+
+	sources, err := s.athleteRepository.FindAthletesByIDs(sourceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("could not find athletes: %w", err)
+	}
+	if len(sources) != len(uniqueObjectIDs(sourceIDs)) {
+		return nil, ErrAthleteNotFound
+	}
+
+	for _, source := range sources {
+		if source.NationalID != "" && target.NationalID != "" && source.NationalID != target.NationalID {
+			return nil, fmt.Errorf("%w: athlete %s has a different national id", ErrInvalidAthleteRequest, source.ID.Hex())
+		}
+	}
+
+	if err := s.athleteRepository.MoveAllAthleteResults(sourceIDs, target.ID); err != nil {
+		return nil, fmt.Errorf("could not move athlete results: %w", err)
+	}
+
+	for _, source := range sources {
+		for _, name := range source.NormalizedNames {
+			if !containsString(target.NormalizedNames, name) {
+				target.NormalizedNames = append(target.NormalizedNames, name)
+			}
+		}
+		for _, chip := range source.Chips {
+			if !containsString(target.Chips, chip) {
+				target.Chips = append(target.Chips, chip)
+			}
+		}
+		if target.NationalID == "" {
+			target.NationalID = source.NationalID
+		}
+		if target.Sex == "" {
+			target.Sex = source.Sex
+		}
+	}
+
+	if err := s.athleteRepository.UpdateAthlete(target); err != nil {
+		return nil, fmt.Errorf("could not update athlete: %w", err)
+	}
+	if err := s.athleteRepository.DeleteAthletes(sourceIDs); err != nil {
+		return nil, fmt.Errorf("could not delete merged athletes: %w", err)
+	}
+	return target, nil
+}
+
+// SplitAthlete separa los resultados indicados en un atleta nuevo, p. ej. cuando dos
+// homónimos quedaron unidos. Los resultados separados quedan como vínculos manuales y los
+// nombres y chips de ambos atletas se recalculan a partir de sus resultados.
+func (s *athleteService) SplitAthlete(id string, req *ports.SplitAthleteRequest) (*domain.Athlete, error) {
+	athlete, err := s.GetAthlete(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.ResultIDs) == 0 {
+		return nil, fmt.Errorf("%w: resultIds is required", ErrInvalidAthleteRequest)
+	}
+
+	links, err := s.athleteRepository.FindAthleteResults(athlete.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get athlete results: %w", err)
+	}
+
+	selected := make(map[primitive.ObjectID]bool, len(req.ResultIDs))
+	for _, resultID := range req.ResultIDs {
+		objID, err := primitive.ObjectIDFromHex(resultID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidObjectID, err)
+		}
+		selected[objID] = true
+	}
+
+	var moved, kept []*domain.AthleteResult
+	var movedIDs []primitive.ObjectID
+	for _, link := range links {
+		if selected[link.ID] {
+			moved = append(moved, link)
+			movedIDs = append(movedIDs, link.ID)
+		} else {
+			kept = append(kept, link)
+		}
+	}
+	if len(moved) != len(selected) {
+		return nil, fmt.Errorf("%w: some results do not belong to the athlete", ErrInvalidAthleteRequest)
+	}
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("%w: at least one result must remain with the athlete", ErrInvalidAthleteRequest)
+	}
+
+	split := &domain.Athlete{}
+	rebuildIdentity(split, moved)
+	rebuildIdentity(athlete, kept)
+	if split.NationalID == athlete.NationalID {
+		split.NationalID = ""
+	}
+
+	if err := s.athleteRepository.SaveAthlete(split); err != nil {
+		return nil, fmt.Errorf("could not save athlete: %w", err)
+	}
+	if err := s.athleteRepository.MoveAthleteResults(movedIDs, split.ID, true); err != nil {
+		return nil, fmt.Errorf("could not move athlete results: %w", err)
+	}
+	if err := s.athleteRepository.UpdateAthlete(athlete); err != nil {
+		return nil, fmt.Errorf("could not update athlete: %w", err)
+	}
+	return split, nil
+}
+
+func uniqueObjectIDs(ids []primitive.ObjectID) []primitive.ObjectID {
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	unique := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
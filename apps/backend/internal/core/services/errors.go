@@ -3,7 +3,24 @@ package services
 import "errors"
 
 var (
-	ErrFileHashMismatch      = errors.New("file hash mismatch")
-	ErrInvalidFileExtension  = errors.New("invalid file extension")
-	ErrInvalidObjectID       = errors.New("invalid object id")
+	ErrFileHashMismatch       = errors.New("file hash mismatch")
+	ErrInvalidFileExtension   = errors.New("invalid file extension")
+	ErrInvalidImportFile      = errors.New("could not import file")
+	ErrInvalidExportFormat    = errors.New("invalid export format")
+	ErrInvalidCertificate     = errors.New("invalid certificate template")
+	ErrParticipantNotFinished = errors.New("participant did not finish")
+	ErrParticipantNotFound    = errors.New("participant not found")
+	ErrInvalidResultStatus    = errors.New("invalid result status")
+	ErrInvalidCorrection      = errors.New("invalid result correction")
+	ErrCorrectionNotFound     = errors.New("result correction not found")
+	ErrStartListNotFound      = errors.New("start list not found")
+	ErrInvalidTeamScoring     = errors.New("invalid team scoring")
+	ErrInvalidRankingSource   = errors.New("invalid ranking source")
+	ErrInvalidObjectID        = errors.New("invalid object id")
+	ErrAthleteNotFound        = errors.New("athlete not found")
+	ErrInvalidAthleteRequest  = errors.New("invalid athlete request")
+	ErrSeriesNotFound         = errors.New("series not found")
+	ErrInvalidSeries          = errors.New("invalid series")
+	ErrUploadPreviewNotFound  = errors.New("upload preview not found or expired")
+	ErrUploadPreviewOutdated  = errors.New("event results changed after the preview was created")
 )
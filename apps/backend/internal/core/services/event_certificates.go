@@ -0,0 +1,271 @@
+package services
+
+import (
+	"backend/internal/certificate"
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"backend/internal/utils"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// maxBackgroundSize es el tamaño máximo de la imagen de fondo que se descarga
+const maxBackgroundSize = 10 << 20
+
+// maxBackgroundPixels limita la resolución de la imagen de fondo. Un archivo comprimido
+// pequeño puede declarar dimensiones enormes y ocupar gigabytes al decodificarse.
+const maxBackgroundPixels = 40_000_000
+
+// cloudinaryHost sirve las imágenes subidas a Cloudinary. Las imágenes de fondo se descargan
+// desde el servidor, por lo que solo se aceptan de este host y nunca de direcciones internas.
+const cloudinaryHost = "res.cloudinary.com"
+
+// isCloudinaryURL indica si la URL es una imagen https de Cloudinary y, si la cuenta está
+// configurada, de la cuenta del sistema
+func isCloudinaryURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host != cloudinaryHost || u.User != nil {
+		return false
+	}
+	if cloudName := os.Getenv("CLOUDINARY_CLOUD_NAME"); cloudName != "" {
+		return strings.HasPrefix(u.Path, "/"+cloudName+"/")
+	}
+	return true
+}
+
+// UpdateCertificateTemplate guarda la plantilla del certificado de finalización del evento
+func (s *eventService) UpdateCertificateTemplate(eventID string, req *ports.UpdateCertificateTemplateRequest) (*domain.Event, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &domain.CertificateTemplate{
+		Title:         strings.TrimSpace(req.Title),
+		BackgroundURL: strings.TrimSpace(req.BackgroundURL),
+		NoBackground:  req.NoBackground,
+		TextColor:     strings.TrimSpace(req.TextColor),
+	}
+	if template.TextColor != "" && !hexColorPattern.MatchString(template.TextColor) {
+		return nil, fmt.Errorf("%w: text color must be in #RRGGBB format", ErrInvalidCertificate)
+	}
+	if template.BackgroundURL != "" && !isCloudinaryURL(template.BackgroundURL) {
+		return nil, fmt.Errorf("%w: background must be an https URL of an image uploaded to Cloudinary", ErrInvalidCertificate)
+	}
+
+	if err := s.eventRepository.UpdateCertificateTemplate(event.ID, template); err != nil {
+		return nil, fmt.Errorf("could not update certificate template: %w", err)
+	}
+	event.Certificate = template
+	return event, nil
+}
+
+// GetParticipantCertificate genera el certificado de finalización del participante con el
+// dorsal indicado, en formato pdf o png
+func (s *eventService) GetParticipantCertificate(eventID string, bib string, format string) (*ports.ExportFile, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != certificate.FormatPDF && format != certificate.FormatPNG {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidExportFormat, format)
+	}
+
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	bib = strings.TrimSpace(bib)
+	found, err := s.eventRepository.FindData(event.ID, nil, nil, &bib, nil, nil, nil, nil, nil, 1, 1)
+	if err != nil {
+		return nil, fmt.Errorf("could not get participant: %w", err)
+	}
+	if len(found.Participants) == 0 {
+		return nil, ErrParticipantNotFound
+	}
+	result := found.Participants[0].Result
+	if result.Status != "" || (result.FinishTimeMs == 0 && result.Position == 0) {
+		return nil, ErrParticipantNotFinished
+	}
+
+	template := domain.CertificateTemplate{}
+	if event.Certificate != nil {
+		template = *event.Certificate
+	}
+
+	cert := certificate.Certificate{
+		Title:            template.Title,
+		EventName:        event.Name,
+		EventDate:        event.Date,
+		ParticipantName:  result.Name,
+		Bib:              result.Bib,
+		Modality:         result.Modality,
+		Category:         result.Category,
+		FinishTime:       result.FinishTimeText,
+		Position:         result.Position,
+		CategoryPosition: result.CategoryPosition,
+		TextColor:        parseHexColor(template.TextColor),
+	}
+
+	// Un fondo que no se puede descargar no impide generar el certificado
+	backgroundURL := template.BackgroundURL
+	if backgroundURL == "" {
+		backgroundURL = event.ImageURL
+	}
+	if backgroundURL != "" && !template.NoBackground {
+		background, err := s.backgrounds.get(backgroundURL)
+		if err != nil {
+			fmt.Printf("[WARNING] Could not load certificate background %s: %v\n", backgroundURL, err)
+		} else {
+			cert.Background = background
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := certificate.Render(&buf, format, cert); err != nil {
+		return nil, fmt.Errorf("could not render certificate: %w", err)
+	}
+
+	fileName := fmt.Sprintf("certificado-%s-%s.%s", utils.GenerateSlug(event.Name), utils.GenerateSlug(result.Bib), format)
+	return &ports.ExportFile{
+		FileName:    fileName,
+		ContentType: certificate.ContentType(format),
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+// parseHexColor convierte un color "#RRGGBB". Retorna el color vacío si no es válido,
+// con lo que se usa el color por defecto.
+func parseHexColor(value string) color.RGBA {
+	if !hexColorPattern.MatchString(value) {
+		return color.RGBA{}
+	}
+	var r, g, b uint8
+	fmt.Sscanf(value, "#%02x%02x%02x", &r, &g, &b)
+	return color.RGBA{R: r, G: g, B: b, A: 0xFF}
+}
+
+// imageCache guarda las imágenes de fondo descargadas, ya que todos los certificados de
+// un evento usan la misma imagen
+type imageCache struct {
+	mu     sync.Mutex
+	images map[string]cachedImage
+	bytes  int64
+	client *http.Client
+}
+
+type cachedImage struct {
+	image image.Image
+	bytes int64
+}
+
+// maxCachedImages y maxCachedImageBytes limitan la memoria usada por el cache de imágenes
+// de fondo, medida según el tamaño de las imágenes decodificadas
+const (
+	maxCachedImages     = 16
+	maxCachedImageBytes = 256 << 20
+)
+
+func newImageCache() *imageCache {
+	return &imageCache{
+		images: make(map[string]cachedImage),
+		client: &http.Client{
+			Timeout: 15 * time.Second,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if !isCloudinaryURL(req.URL.String()) {
+					return fmt.Errorf("redirect to %s is not allowed", req.URL.Host)
+				}
+				if len(via) >= 5 {
+					return errors.New("too many redirects")
+				}
+				return nil
+			},
+		},
+	}
+}
+
+func (c *imageCache) get(url string) (image.Image, error) {
+	c.mu.Lock()
+	cached, ok := c.images[url]
+	c.mu.Unlock()
+	if ok {
+		return cached.image, nil
+	}
+
+	// La imagen del evento también puede usarse de fondo y no se valida al guardarla
+	if !isCloudinaryURL(url) {
+		return nil, fmt.Errorf("background %s is not a Cloudinary image", url)
+	}
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBackgroundSize))
+	if err != nil {
+		return nil, fmt.Errorf("could not download image: %w", err)
+	}
+	img, size, err := decodeBackground(data)
+	if err != nil {
+		return nil, err
+	}
+
+	c.put(url, cachedImage{image: img, bytes: size})
+	return img, nil
+}
+
+// put guarda la imagen descartando otras hasta que el cache quede dentro de sus límites
+func (c *imageCache) put(url string, img cachedImage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if previous, ok := c.images[url]; ok {
+		c.bytes -= previous.bytes
+		delete(c.images, url)
+	}
+	for cachedURL, cached := range c.images {
+		if len(c.images) < maxCachedImages && c.bytes+img.bytes <= maxCachedImageBytes {
+			break
+		}
+		c.bytes -= cached.bytes
+		delete(c.images, cachedURL)
+	}
+	c.images[url] = img
+	c.bytes += img.bytes
+}
+
+// decodeBackground decodifica la imagen de fondo después de revisar sus dimensiones, y
+// retorna también el tamaño aproximado que ocupa en memoria
+func decodeBackground(data []byte) (image.Image, int64, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not decode image: %w", err)
+	}
+	pixels := int64(config.Width) * int64(config.Height)
+	if pixels > maxBackgroundPixels {
+		return nil, 0, fmt.Errorf("image of %dx%d pixels exceeds the maximum resolution", config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not decode image: %w", err)
+	}
+	// Se estiman 4 bytes por pixel, el tamaño de una imagen RGBA
+	return img, pixels * 4, nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// pngWithSize codifica una imagen de 1x1 y reescribe sus dimensiones en la cabecera
+func pngWithSize(t *testing.T, width, height uint32) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	// Firma (8 bytes), largo (4) y tipo (4) preceden los 13 bytes de datos del IHDR
+	binary.BigEndian.PutUint32(data[16:], width)
+	binary.BigEndian.PutUint32(data[20:], height)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestDecodeBackground(t *testing.T) {
+	img, size, err := decodeBackground(pngWithSize(t, 1, 1))
+	if err != nil || img.Bounds().Dx() != 1 || size != 4 {
+		t.Errorf("decodeBackground = %v, %d, %v", img, size, err)
+	}
+
+	if _, _, err := decodeBackground(pngWithSize(t, 50_000, 50_000)); err == nil {
+		t.Error("expected an error for an image above the pixel limit")
+	}
+}
+
+func TestImageCacheLimits(t *testing.T) {
+	cache := newImageCache()
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+
+	for i := 0; i < maxCachedImages+4; i++ {
+		cache.put(fmt.Sprintf("img-%d", i), cachedImage{image: img, bytes: 1})
+	}
+	if len(cache.images) != maxCachedImages || cache.bytes != maxCachedImages {
+		t.Errorf("cache holds %d images and %d bytes, expected %d", len(cache.images), cache.bytes, maxCachedImages)
+	}
+
+	cache.put("large", cachedImage{image: img, bytes: maxCachedImageBytes - 2})
+	if _, ok := cache.images["large"]; !ok || cache.bytes > maxCachedImageBytes {
+		t.Errorf("cache holds %d bytes, expected at most %d", cache.bytes, maxCachedImageBytes)
+	}
+}
+
+func TestIsCloudinaryURL(t *testing.T) {
+	t.Setenv("CLOUDINARY_CLOUD_NAME", "racecheck")
+	tests := map[string]bool{
+		"https://res.cloudinary.com/racecheck/image/upload/v1/fondo.png": true,
+		"http://res.cloudinary.com/racecheck/image/upload/v1/fondo.png":  false,
+		"https://res.cloudinary.com/otra/image/upload/v1/fondo.png":      false,
+		"https://res.cloudinary.com.evil.com/racecheck/fondo.png":        false,
+		"https://user@res.cloudinary.com/racecheck/fondo.png":            false,
+		"https://res.cloudinary.com:8443/racecheck/fondo.png":            false,
+		"http://169.254.169.254/latest/meta-data/":                       false,
+		"https://localhost/racecheck/fondo.png":                          false,
+		"not a url":                                                      false,
+	}
+	for raw, expected := range tests {
+		if got := isCloudinaryURL(raw); got != expected {
+			t.Errorf("isCloudinaryURL(%q) = %v, expected %v", raw, got, expected)
+		}
+	}
+}
+
+func TestImageCacheRejectsOtherHosts(t *testing.T) {
+	if _, err := newImageCache().get("http://169.254.169.254/latest/meta-data/"); err == nil {
+		t.Error("expected an error for an image outside Cloudinary")
+	}
+}
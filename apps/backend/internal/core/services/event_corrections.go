@@ -0,0 +1,238 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetCorrections obtiene las correcciones de datos del evento
+func (s *eventService) GetCorrections(eventID string) ([]*domain.ResultCorrection, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	corrections, err := s.eventRepository.FindCorrections(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get result corrections: %w", err)
+	}
+	return corrections, nil
+}
+
+// GetCorrection obtiene la corrección del participante con el dorsal indicado
+func (s *eventService) GetCorrection(eventID string, bib string) (*domain.ResultCorrection, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	correction, err := s.eventRepository.FindCorrection(event.ID, normalizeBib(bib))
+	if err != nil {
+		return nil, fmt.Errorf("could not get result correction: %w", err)
+	}
+	if correction == nil {
+		return nil, ErrCorrectionNotFound
+	}
+	return correction, nil
+}
+
+// SaveCorrection crea o reemplaza la corrección del participante con el dorsal indicado.
+// La corrección se guarda aparte de los participantes para aplicarla también sobre las
+// próximas cargas del archivo.
+func (s *eventService) SaveCorrection(eventID string, bib string, req *ports.CorrectionRequest, user string) (*domain.ResultCorrection, error) {
+	fields, err := correctionFields(req)
+	if err != nil {
+		return nil, err
+	}
+
+	bib = normalizeBib(bib)
+	if bib == "" {
+		return nil, ErrParticipantNotFound
+	}
+
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.eventRepository.FindAllData(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load event data: %w", err)
+	}
+	if findParticipant(data, bib) == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	correction, err := s.eventRepository.FindCorrection(event.ID, bib)
+	if err != nil {
+		return nil, fmt.Errorf("could not get result correction: %w", err)
+	}
+
+	entry := &domain.CorrectionAuditEntry{
+		EventID: event.ID,
+		Bib:     bib,
+		Action:  domain.CorrectionActionCreated,
+		After:   &fields,
+		Note:    strings.TrimSpace(req.Note),
+		User:    user,
+	}
+	var previous *domain.ResultCorrection
+	if correction == nil {
+		correction = &domain.ResultCorrection{
+			EventID:   event.ID,
+			Bib:       bib,
+			CreatedBy: user,
+		}
+	} else {
+		saved := *correction
+		previous = &saved
+		entry.Action = domain.CorrectionActionUpdated
+		entry.Before = &saved.Fields
+	}
+	correction.Fields = fields
+	correction.Note = entry.Note
+	correction.UpdatedBy = user
+
+	if err := s.eventRepository.SaveCorrection(correction); err != nil {
+		return nil, fmt.Errorf("could not save result correction: %w", err)
+	}
+	entry.CorrectionID = correction.ID
+	if err := s.recordCorrectionAudit(entry); err != nil {
+		s.revertCorrection(correction.ID, previous)
+		return nil, err
+	}
+
+	if err := s.reapplyAdjustments(event, data); err != nil {
+		return nil, err
+	}
+	return correction, nil
+}
+
+// DeleteCorrection elimina la corrección del participante, que vuelve a los datos del archivo
+func (s *eventService) DeleteCorrection(eventID string, bib string, user string) error {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return err
+	}
+
+	bib = normalizeBib(bib)
+	correction, err := s.eventRepository.FindCorrection(event.ID, bib)
+	if err != nil {
+		return fmt.Errorf("could not get result correction: %w", err)
+	}
+	if correction == nil {
+		return ErrCorrectionNotFound
+	}
+
+	if err := s.eventRepository.DeleteCorrection(correction.ID); err != nil {
+		return fmt.Errorf("could not delete result correction: %w", err)
+	}
+	before := correction.Fields
+	err = s.recordCorrectionAudit(&domain.CorrectionAuditEntry{
+		EventID:      event.ID,
+		CorrectionID: correction.ID,
+		Bib:          bib,
+		Action:       domain.CorrectionActionDeleted,
+		Before:       &before,
+		User:         user,
+	})
+	if err != nil {
+		s.revertCorrection(correction.ID, correction)
+		return err
+	}
+
+	data, err := s.eventRepository.FindAllData(event.ID)
+	if err != nil {
+		return fmt.Errorf("could not load event data: %w", err)
+	}
+	return s.reapplyAdjustments(event, data)
+}
+
+// GetCorrectionAudit obtiene la auditoría de correcciones del evento, de la más reciente a
+// la más antigua. Con bib, solo la del participante con ese dorsal.
+func (s *eventService) GetCorrectionAudit(eventID string, bib *string) ([]*domain.CorrectionAuditEntry, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if bib != nil {
+		normalized := normalizeBib(*bib)
+		bib = &normalized
+	}
+	entries, err := s.eventRepository.FindCorrectionAudit(event.ID, bib)
+	if err != nil {
+		return nil, fmt.Errorf("could not get correction audit: %w", err)
+	}
+	return entries, nil
+}
+
+// correctionFields valida los campos de la corrección. Se debe corregir al menos un campo
+// y el tiempo, si se indica, debe tener formato HH:MM:SS.
+func correctionFields(req *ports.CorrectionRequest) (domain.CorrectionFields, error) {
+	fields := domain.CorrectionFields{
+		Name:       strings.TrimSpace(req.Name),
+		Sex:        strings.TrimSpace(req.Sex),
+		Category:   strings.TrimSpace(req.Category),
+		Modality:   strings.TrimSpace(req.Modality),
+		Club:       strings.TrimSpace(req.Club),
+		FinishTime: strings.TrimSpace(req.FinishTime),
+	}
+	if fields.IsEmpty() {
+		return fields, fmt.Errorf("%w: at least one field must be corrected", ErrInvalidCorrection)
+	}
+	if fields.FinishTime != "" {
+		if _, ok := parseRaceTime(fields.FinishTime); !ok {
+			return fields, fmt.Errorf("%w: finish time must use the HH:MM:SS format", ErrInvalidCorrection)
+		}
+	}
+	return fields, nil
+}
+
+// findParticipant busca el participante con el dorsal normalizado indicado
+func findParticipant(data []*domain.EventData, bib string) *domain.EventData {
+	for _, item := range data {
+		if normalizeBib(item.Result.Bib) == bib {
+			return item
+		}
+	}
+	return nil
+}
+
+// recordCorrectionAudit guarda la entrada de auditoría. Una corrección sin auditoría no se
+// acepta, por lo que quien llama debe revertirla si esto falla.
+func (s *eventService) recordCorrectionAudit(entry *domain.CorrectionAuditEntry) error {
+	if err := s.eventRepository.SaveCorrectionAudit(entry); err != nil {
+		return fmt.Errorf("could not record correction audit: %w", err)
+	}
+	return nil
+}
+
+// revertCorrection deja la corrección como estaba antes del cambio: la restaura si existía
+// o la elimina si se acababa de crear
+func (s *eventService) revertCorrection(id primitive.ObjectID, previous *domain.ResultCorrection) {
+	var err error
+	if previous == nil {
+		err = s.eventRepository.DeleteCorrection(id)
+	} else {
+		err = s.eventRepository.SaveCorrection(previous)
+	}
+	if err != nil {
+		fmt.Printf("[WARNING] could not revert correction %s: %v\n", id.Hex(), err)
+	}
+}
+
+// reapplyAdjustments vuelve a aplicar los cambios de los administradores sobre los
+// participantes publicados y recalcula sus posiciones
+func (s *eventService) reapplyAdjustments(event *domain.Event, data []*domain.EventData) error {
+	adjustments, err := s.loadAdjustments(event.ID)
+	if err != nil {
+		return err
+	}
+	adjustments.apply(data)
+	return s.refreshResults(event, data)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"backend/internal/core/ports"
+	"backend/internal/exporter"
+	"backend/internal/utils"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ExportEvent genera el archivo de resultados del evento en el formato indicado (csv,
+// xlsx, pdf o racecheck), con los participantes en el mismo orden que el listado
+func (s *eventService) ExportEvent(eventID string, format string, filter ports.ExportFilter) (*ports.ExportFile, error) {
+	exp, err := exporter.ForFormat(format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidExportFormat, format)
+	}
+
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sin paginación: FindData retorna todos los participantes con limit 0
+	result, err := s.eventRepository.FindData(event.ID, nil, nil, nil, optionalFilter(filter.Category), nil, optionalFilter(filter.Sex), nil, optionalFilter(filter.Race), 1, 0)
+	if err != nil {
+		return nil, fmt.Errorf("could not get participants: %w", err)
+	}
+
+	doc := exporter.Document{
+		Event:  event,
+		Races:  exporter.GroupByRace(event, result.Participants),
+		Filter: describeExportFilter(filter),
+	}
+
+	var buf bytes.Buffer
+	if err := exp.Export(&buf, doc); err != nil {
+		return nil, fmt.Errorf("could not export event: %w", err)
+	}
+
+	fmt.Printf("[INFO] Exported event %s as %s: %d participants, %d bytes\n", event.ID.Hex(), exp.Format(), len(result.Participants), buf.Len())
+	return &ports.ExportFile{
+		FileName:    exportFileName(event.Slug, event.Name, filter) + exp.Extension(),
+		ContentType: exp.ContentType(),
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+// optionalFilter convierte un filtro vacío en nil, como los parámetros de GetParticipants
+func optionalFilter(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// describeExportFilter describe los filtros aplicados para mostrarlos en el PDF
+func describeExportFilter(filter ports.ExportFilter) string {
+	var parts []string
+	if filter.Race != "" {
+		parts = append(parts, "Carrera: "+filter.Race)
+	}
+	if filter.Category != "" {
+		parts = append(parts, "Categoría: "+filter.Category)
+	}
+	if filter.Sex != "" {
+		parts = append(parts, "Sexo: "+filter.Sex)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// exportFileName genera el nombre del archivo a partir del slug del evento y los filtros
+func exportFileName(slug, name string, filter ports.ExportFilter) string {
+	if slug == "" {
+		slug = utils.GenerateSlug(name)
+	}
+	parts := []string{slug, "resultados"}
+	for _, value := range []string{filter.Race, filter.Category, filter.Sex} {
+		if part := utils.GenerateSlug(value); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"fmt"
+	"strings"
+)
+
+// UpdateRankingSource configura si el evento publica las posiciones del archivo o las
+// calculadas a partir de los tiempos, y actualiza las posiciones de sus participantes
+func (s *eventService) UpdateRankingSource(eventID string, req *ports.UpdateRankingSourceRequest) (*domain.Event, error) {
+	source := strings.ToLower(strings.TrimSpace(req.Source))
+	if source != domain.RankingSourceFile && source != domain.RankingSourceComputed {
+		return nil, fmt.Errorf("%w: source must be %q or %q", ErrInvalidRankingSource, domain.RankingSourceFile, domain.RankingSourceComputed)
+	}
+
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.eventRepository.FindAllData(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load event data: %w", err)
+	}
+
+	if event.RankingSource != domain.RankingSourceComputed {
+		for _, item := range data {
+			// Los participantes publicados antes de existir la clasificación calculada solo
+			// tienen las posiciones del archivo
+			if item.Result.FilePosition == 0 && item.Result.FileCategoryPosition == 0 {
+				item.Result.FilePosition = item.Result.Position
+				item.Result.FileCategoryPosition = item.Result.CategoryPosition
+			}
+		}
+	}
+
+	event.RankingSource = source
+	if err := s.refreshResults(event, data); err != nil {
+		return nil, err
+	}
+	if err := s.eventRepository.UpdateRankingSource(event.ID, source); err != nil {
+		return nil, fmt.Errorf("could not update ranking source: %w", err)
+	}
+	return event, nil
+}
+
+// refreshResults recalcula el ritmo, las diferencias y las posiciones de los participantes
+// publicados después de un cambio hecho por un administrador, y los guarda. Como una
+// corrección puede cambiar el nombre, se vuelven a asociar los resultados con los atletas
+// antes de recalcular los campeonatos, igual que al publicar una carga.
+func (s *eventService) refreshResults(event *domain.Event, data []*domain.EventData) error {
+	applyDerivedStats(data, event.Races)
+	applyRankings(data, event.RankingSource)
+
+	if err := s.eventRepository.UpdateDataResults(data); err != nil {
+		return fmt.Errorf("could not update participant results: %w", err)
+	}
+
+	published := make([]domain.EventData, len(data))
+	for i, item := range data {
+		published[i] = *item
+	}
+	s.onResultsPublished(event.ID, published)
+	return nil
+}
@@ -3,8 +3,10 @@ package services
 import (
 	"backend/internal/core/domain"
 	"backend/internal/core/ports"
+	"backend/internal/importer"
+	"backend/internal/racecheck"
 	"backend/internal/utils"
-	"bufio"
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
@@ -24,11 +26,24 @@ import (
 
 type eventService struct {
 	eventRepository ports.EventRepository
+	previews        *previewStore
+	importers       *importer.Registry
+	backgrounds     *imageCache
+	athletes        ports.AthleteService
+	series          ports.SeriesService
 }
 
-func NewEventService(eventRepository ports.EventRepository) ports.EventService {
+// previewSampleSize es la cantidad de filas de ejemplo que se muestran en la vista previa de una carga
+const previewSampleSize = 10
+
+func NewEventService(eventRepository ports.EventRepository, athleteService ports.AthleteService, seriesService ports.SeriesService) ports.EventService {
 	return &eventService{
 		eventRepository: eventRepository,
+		previews:        newPreviewStore(),
+		importers:       importer.DefaultRegistry(),
+		backgrounds:     newImageCache(),
+		athletes:        athleteService,
+		series:          seriesService,
 	}
 }
 
@@ -49,6 +64,8 @@ func parseDataValue(value string, fieldName string) interface{} {
 		"MODALIDAD": true,
 		"CATEGORIA": true,
 		"RITMO":     true,
+		"ESTADO":    true,
+		"MOTIVO":    true,
 	}
 
 	// Si es un campo categórico, mantener como string
@@ -185,11 +202,11 @@ func (s *eventService) CreateEvent(req *ports.CreateEventRequest) (*domain.Event
 	return event, nil
 }
 
-func (s *eventService) Upload(fileHeader *multipart.FileHeader, clientHash string) (*ports.UploadResult, error) {
-	// 1. Validate file extension
-	expectedExt := os.Getenv("RACECHECK_EXTENSION")
-	if filepath.Ext(fileHeader.Filename) != expectedExt {
-		return nil, ErrInvalidFileExtension
+func (s *eventService) Upload(fileHeader *multipart.FileHeader, clientHash string, options ports.UploadOptions) (*ports.UploadResult, error) {
+	// 1. Validate file extension or requested format
+	imp, err := s.resolveFormat(fileHeader.Filename, options)
+	if err != nil {
+		return nil, err
 	}
 
 	file, err := fileHeader.Open()
@@ -236,11 +253,16 @@ func (s *eventService) Upload(fileHeader *multipart.FileHeader, clientHash strin
 	if _, err := file.Seek(0, io.SeekStart); err != nil {
 		return nil, fmt.Errorf("could not rewind file: %w", err)
 	}
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read file: %w", err)
+	}
 
 	// 6. Parse file with new format
 	// Remove extension from filename for storage
 	fileNameWithoutExt := strings.TrimSuffix(fileHeader.Filename, filepath.Ext(fileHeader.Filename))
-	result, err := s.parseRaceCheckFile(file, calculatedHash, existingEventByFileName, fileNameWithoutExt, filepath.Ext(fileHeader.Filename))
+	upload := newUploadedFile(fileHeader.Filename, calculatedHash, content, imp, options)
+	result, err := s.parseRaceCheckFile(upload, existingEventByFileName, fileNameWithoutExt, filepath.Ext(fileHeader.Filename))
 	if err != nil {
 		return nil, err
 	}
@@ -279,7 +301,7 @@ func (s *eventService) GetEventsWithFilter(name *string, date *time.Time, page i
 	return result, nil
 }
 
-func (s *eventService) GetParticipants(eventID string, name, chip, dorsal, category, distance, sex, position *string, page int, limit int) (*ports.FindParticipantsResult, error) {
+func (s *eventService) GetParticipants(eventID string, name, chip, dorsal, category, distance, sex, position, race *string, page int, limit int) (*ports.FindParticipantsResult, error) {
 	if page <= 0 {
 		page = 1
 	}
@@ -292,7 +314,7 @@ func (s *eventService) GetParticipants(eventID string, name, chip, dorsal, categ
 		return nil, fmt.Errorf("%w: %v", ErrInvalidObjectID, err)
 	}
 
-	result, err := s.eventRepository.FindData(objID, name, chip, dorsal, category, distance, sex, position, page, limit)
+	result, err := s.eventRepository.FindData(objID, name, chip, dorsal, category, distance, sex, position, race, page, limit)
 	if err != nil {
 		return nil, fmt.Errorf("could not get participants: %w", err)
 	}
@@ -300,6 +322,82 @@ func (s *eventService) GetParticipants(eventID string, name, chip, dorsal, categ
 	return result, nil
 }
 
+// GetEventRaces obtiene las carreras del evento en el orden del archivo de resultados
+func (s *eventService) GetEventRaces(eventID string) ([]domain.Race, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Races == nil {
+		return []domain.Race{}, nil
+	}
+	return event.Races, nil
+}
+
+// UpdateRaceDistance configura manualmente la distancia de una carrera y recalcula
+// el ritmo de sus participantes
+func (s *eventService) UpdateRaceDistance(eventID string, raceNumber int, distanceKm float64) (*domain.Race, error) {
+	if distanceKm < 0 {
+		return nil, errors.New("distance must not be negative")
+	}
+
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	raceIndex := -1
+	for i, race := range event.Races {
+		if race.Number == raceNumber {
+			raceIndex = i
+			break
+		}
+	}
+	if raceIndex == -1 {
+		return nil, errors.New("race not found")
+	}
+
+	data, err := s.eventRepository.FindRaceData(event.ID, raceNumber)
+	if err != nil {
+		return nil, fmt.Errorf("could not load race data: %w", err)
+	}
+
+	race := &event.Races[raceIndex]
+	if distanceKm > 0 {
+		race.DistanceKm = distanceKm
+		race.DistanceSource = domain.DistanceSourceManual
+	} else {
+		// Volver a la distancia indicada en el nombre de la carrera o en la modalidad
+		race.DistanceKm = 0
+		race.DistanceSource = ""
+		if distance, ok := parseDistanceKm(race.Name); ok {
+			race.DistanceKm = distance
+			race.DistanceSource = domain.DistanceSourceModality
+		} else if len(data) > 0 {
+			if distance, ok := parseDistanceKm(data[0].Result.Modality); ok {
+				race.DistanceKm = distance
+				race.DistanceSource = domain.DistanceSourceModality
+			}
+		}
+	}
+
+	results := make([]*domain.Result, len(data))
+	for i := range data {
+		results[i] = &data[i].Result
+	}
+	computeRaceStats(results, race.DistanceKm)
+
+	if err := s.eventRepository.UpdateDataResults(data); err != nil {
+		return nil, fmt.Errorf("could not update race results: %w", err)
+	}
+	if err := s.eventRepository.UpdateRaces(event.ID, event.Races); err != nil {
+		return nil, fmt.Errorf("could not update event races: %w", err)
+	}
+
+	return race, nil
+}
+
 func (s *eventService) GetEvent(id string) (*domain.Event, error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -378,6 +476,7 @@ func (s *eventService) UpdateEvent(id string, req *ports.UpdateEventRequest) (*d
 		UniqueModalities: existingEvent.UniqueModalities, // Keep existing modalities
 		UniqueCategories: existingEvent.UniqueCategories, // Keep existing categories
 		RecordsCount:     existingEvent.RecordsCount,     // Keep existing records count
+		Races:            existingEvent.Races,            // Keep existing races
 		Slug:             existingEvent.Slug,             // Keep existing slug
 	}
 
@@ -440,6 +539,17 @@ func (s *eventService) DeleteEvent(id string) error {
 		return fmt.Errorf("could not delete event: %w", err)
 	}
 
+	if s.athletes != nil {
+		if err := s.athletes.UnlinkEvent(objID); err != nil {
+			fmt.Printf("[WARNING] could not unlink athletes of event %s: %v\n", objID.Hex(), err)
+		}
+	}
+	if s.series != nil {
+		if err := s.series.RemoveEvent(objID); err != nil {
+			fmt.Printf("[WARNING] could not remove event %s from its series: %v\n", objID.Hex(), err)
+		}
+	}
+
 	return nil
 }
 
@@ -481,135 +591,173 @@ func (s *eventService) UpdateEventStatus(id string, status string) (*domain.Even
 	return event, nil
 }
 
-func (s *eventService) parseRaceCheckFile(file io.ReadSeeker, fileHash string, existingEventByFileName *domain.Event, fileName string, fileExtension string) (*ports.UploadResult, error) {
-	scanner := bufio.NewScanner(file)
-	var event *domain.Event
-	var allEventData []domain.EventData
-	var headers []string
-	var reprocessed bool
-	var lineNum int = 0
-	var recordsSkipped int = 0
+// parsedEventData contiene los registros extraídos de un archivo de resultados
+type parsedEventData struct {
+	data       []domain.EventData
+	races      []domain.Race
+	modalities []string
+	categories []string
+}
+
+// buildEventData convierte las filas del archivo en registros de participantes y
+// recopila las carreras, modalidades y categorías únicas para los filtros
+func buildEventData(parsed *racecheck.File) *parsedEventData {
+	result := &parsedEventData{races: []domain.Race{}}
 
 	// Maps para recopilar valores únicos
 	uniqueModalities := make(map[string]bool)
 	uniqueCategories := make(map[string]bool)
 
-	// First line: event name
-	if scanner.Scan() {
-		lineNum++
-		eventName := scanner.Text()
-		if eventName == "" {
-			return nil, errors.New("event name in header cannot be empty")
-		}
+	for _, race := range parsed.Races {
+		for _, row := range race.Rows {
+			dataMap := race.RowMap(row)
 
-		// Remove race prefix if present (format: "1|EVENT_NAME" -> "EVENT_NAME")
-		if idx := strings.Index(eventName, "|"); idx != -1 {
-			eventName = strings.TrimSpace(eventName[idx+1:])
+			// Recopilar valores únicos para filtros
+			if modalidad, ok := dataMap["MODALIDAD"]; ok && modalidad != "" {
+				uniqueModalities[modalidad] = true
+			}
+			if categoria, ok := dataMap["CATEGORIA"]; ok && categoria != "" {
+				uniqueCategories[categoria] = true
+			}
+
+			// Add event data with type validation and conversion
+			convertedData := validateAndConvertData(dataMap)
+			result.data = append(result.data, domain.EventData{
+				RaceNumber: race.Number,
+				RaceName:   race.Name,
+				Result:     mapResult(dataMap),
+				Data:       convertedData,
+			})
 		}
 
-		if eventName == "" {
-			return nil, errors.New("event name in header cannot be empty")
+		if len(race.Rows) > 0 || race.Name != "" {
+			result.races = append(result.races, summarizeRace(race))
 		}
+	}
 
-		// Generate slug from event name
-		slug := utils.GenerateSlug(eventName)
+	// Convertir mapas de valores únicos a slices
+	result.modalities = make([]string, 0, len(uniqueModalities))
+	for modality := range uniqueModalities {
+		result.modalities = append(result.modalities, modality)
+	}
+	sort.Strings(result.modalities)
 
-		event = &domain.Event{
-			Name:          eventName,
-			Slug:          slug,
-			FileHash:      fileHash,
-			FileName:      fileName,
-			FileExtension: fileExtension,
-			Date:          time.Now(),
-			Status:        "PUBLISHED",
-		}
+	result.categories = make([]string, 0, len(uniqueCategories))
+	for category := range uniqueCategories {
+		result.categories = append(result.categories, category)
 	}
+	sort.Strings(result.categories)
 
-	// Process remaining lines
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
+	return result
+}
 
-		// Check if it's a race line (format: ;1|CAD 3G) - skip it
-		if strings.HasPrefix(line, ";") && strings.Contains(line, "|") && !strings.HasPrefix(line, ";SEXO") {
-			parts := strings.Split(strings.TrimPrefix(line, ";"), "|")
-			if len(parts) >= 2 {
-				headers = nil // Reset headers for new race
-				continue
-			}
-		}
+// summarizeRace obtiene la cantidad de participantes y el tiempo del ganador de una carrera.
+// El ganador es el participante con la menor POSICION numérica.
+func summarizeRace(race *racecheck.Race) domain.Race {
+	summary := domain.Race{
+		Number:           race.Number,
+		Name:             race.Name,
+		ParticipantCount: len(race.Rows),
+	}
 
-		// Check if it's a header line (format: ;SEXO|NOMBRE|CHIP|...)
-		if strings.HasPrefix(line, ";SEXO|") {
-			headers = strings.Split(strings.TrimPrefix(line, ";"), "|")
+	bestPosition := 0
+	for _, row := range race.Rows {
+		posValue, _ := race.Value(row, "POSICION")
+		position, err := strconv.Atoi(strings.TrimSpace(posValue))
+		if err != nil || position <= 0 {
 			continue
 		}
-
-		// Skip empty lines or lines without headers
-		if len(headers) == 0 {
-			continue
+		if bestPosition == 0 || position < bestPosition {
+			bestPosition = position
+			summary.WinnerTime, _ = race.Value(row, "TIEMPO")
 		}
+	}
+	if d, ok := parseRaceTime(summary.WinnerTime); ok {
+		summary.WinnerTimeMs = d.Milliseconds()
+	}
 
-		// Parse participant data
-		values := strings.Split(line, "|")
-		if len(values) != len(headers) {
-			recordsSkipped++
-			continue // Skip malformed data lines
+	// La distancia se obtiene del nombre de la carrera o de la modalidad de sus participantes
+	if distance, ok := parseDistanceKm(race.Name); ok {
+		summary.DistanceKm = distance
+		summary.DistanceSource = domain.DistanceSourceModality
+	} else if len(race.Rows) > 0 {
+		modality, _ := race.Value(race.Rows[0], "MODALIDAD")
+		if distance, ok := parseDistanceKm(modality); ok {
+			summary.DistanceKm = distance
+			summary.DistanceSource = domain.DistanceSourceModality
 		}
+	}
 
-		dataMap := make(map[string]string)
-		for i, h := range headers {
-			dataMap[h] = values[i]
-		}
+	return summary
+}
 
-		// Recopilar valores únicos para filtros
-		if modalidad, ok := dataMap["MODALIDAD"]; ok && modalidad != "" {
-			uniqueModalities[modalidad] = true
-		}
-		if categoria, ok := dataMap["CATEGORIA"]; ok && categoria != "" {
-			uniqueCategories[categoria] = true
-		}
+func (s *eventService) parseRaceCheckFile(file uploadedFile, existingEventByFileName *domain.Event, fileName string, fileExtension string) (*ports.UploadResult, error) {
+	var reprocessed bool
+	fileHash := file.hash
 
-		// Add event data with type validation and conversion
-		convertedData := validateAndConvertData(dataMap)
-		eventData := domain.EventData{Data: convertedData}
-		allEventData = append(allEventData, eventData)
+	parsed, err := file.parse()
+	if err != nil {
+		if errors.Is(err, racecheck.ErrNoHeader) {
+			return nil, errors.New("could not parse event information from file")
+		}
+		return nil, err
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
+	eventName := parsed.Header.Name
+	if eventName == "" {
+		return nil, errors.New("event name in header cannot be empty")
 	}
 
-	if event == nil {
-		return nil, errors.New("could not parse event information from file")
+	// Generate slug from event name
+	slug := utils.GenerateSlug(eventName)
+
+	event := &domain.Event{
+		Name:          eventName,
+		Slug:          slug,
+		FileHash:      fileHash,
+		FileName:      fileName,
+		FileExtension: fileExtension,
+		Date:          time.Now(),
+		Status:        "PUBLISHED",
 	}
 
+	parsedData := buildEventData(parsed)
+	allEventData, modalitiesSlice, categoriesSlice := parsedData.data, parsedData.modalities, parsedData.categories
+
 	// Log parsing information
-	fmt.Printf("[DEBUG] Parsed file: %d total lines, %d records extracted, %d records skipped\n", lineNum, len(allEventData), recordsSkipped)
+	fmt.Printf("[DEBUG] Parsed file: %d total lines, %d records extracted, %d records skipped, encoding %s\n", parsed.Lines, len(allEventData), len(parsed.Skipped), parsed.Encoding)
 	if len(allEventData) == 0 {
 		fmt.Printf("[DEBUG] Warning: No records extracted. Event name: %s\n", event.Name)
 	}
 
-	// Convertir mapas de valores únicos a slices
-	modalitiesSlice := make([]string, 0, len(uniqueModalities))
-	for modality := range uniqueModalities {
-		modalitiesSlice = append(modalitiesSlice, modality)
-	}
-	sort.Strings(modalitiesSlice)
-
-	categoriesSlice := make([]string, 0, len(uniqueCategories))
-	for category := range uniqueCategories {
-		categoriesSlice = append(categoriesSlice, category)
+	// Validar las filas antes de modificar el evento
+	report := validateIngest(parsed)
+	if exceedsErrorThreshold(report) {
+		var eventID primitive.ObjectID
+		if existingEventByFileName != nil {
+			eventID = existingEventByFileName.ID
+		}
+		return s.rejectUpload(eventID, fileName+fileExtension, fileHash, len(allEventData), report), nil
 	}
-	sort.Strings(categoriesSlice)
 
 	// Asignar valores únicos y cantidad de registros al evento
 	event.UniqueModalities = modalitiesSlice
 	event.UniqueCategories = categoriesSlice
 	event.RecordsCount = len(allEventData)
+	event.Races = parsedData.races
+
+	// Participantes publicados antes de esta carga, para registrar los cambios
+	var previousData []*domain.EventData
+	created := false
 
 	// Priority 1: Use existing event if it was passed by fileName
 	if existingEventByFileName != nil {
+		adjustments, err := s.loadAdjustments(existingEventByFileName.ID)
+		if err != nil {
+			return nil, err
+		}
+		parsedData.finalize(existingEventByFileName, adjustments)
+		s.checkEventStartList(existingEventByFileName, parsed, &report)
 		reprocessed = true
 		event.ID = existingEventByFileName.ID
 		event.CreatedAt = existingEventByFileName.CreatedAt
@@ -620,12 +768,9 @@ func (s *eventService) parseRaceCheckFile(file io.ReadSeeker, fileHash string, e
 		event.ImageURL = existingEventByFileName.ImageURL
 		event.Slug = existingEventByFileName.Slug
 
-		// Delete old data
-		if err := s.eventRepository.DeleteEventData(existingEventByFileName.ID); err != nil {
-			return nil, fmt.Errorf("could not delete old event data: %w", err)
-		}
-		if err := s.eventRepository.UpdateFileStats(existingEventByFileName.ID, fileHash, modalitiesSlice, categoriesSlice, len(allEventData)); err != nil {
-			return nil, fmt.Errorf("could not update event stats: %w", err)
+		previousData, err = s.eventRepository.FindAllData(existingEventByFileName.ID)
+		if err != nil {
+			return nil, fmt.Errorf("could not load current event data: %w", err)
 		}
 	} else {
 		// Priority 2: Check for existing event by name (backward compatibility)
@@ -644,37 +789,52 @@ func (s *eventService) parseRaceCheckFile(file io.ReadSeeker, fileHash string, e
 				}, nil
 			}
 
+			adjustments, err := s.loadAdjustments(existingEvent.ID)
+			if err != nil {
+				return nil, err
+			}
+			parsedData.finalize(existingEvent, adjustments)
+			s.checkEventStartList(existingEvent, parsed, &report)
 			reprocessed = true
 			event.ID = existingEvent.ID
 
-			// Delete old data
-			if err := s.eventRepository.DeleteEventData(existingEvent.ID); err != nil {
-				return nil, fmt.Errorf("could not delete old event data: %w", err)
-			}
-			if err := s.eventRepository.UpdateFileStats(existingEvent.ID, fileHash, modalitiesSlice, categoriesSlice, len(allEventData)); err != nil {
-				return nil, fmt.Errorf("could not update event stats: %w", err)
+			previousData, err = s.eventRepository.FindAllData(existingEvent.ID)
+			if err != nil {
+				return nil, fmt.Errorf("could not load current event data: %w", err)
 			}
 		} else {
 			// Priority 3: Create new event
+			parsedData.finalize(nil, resultAdjustments{})
 			event.ID = primitive.NewObjectID()
-			if err := s.eventRepository.Save(event); err != nil {
+			// El hash se guarda recién al publicar los participantes, para que volver a
+			// cargar el archivo después de una publicación fallida no lo dé por cargado
+			pending := *event
+			pending.FileHash = ""
+			if err := s.eventRepository.Save(&pending); err != nil {
 				return nil, fmt.Errorf("could not save new event: %w", err)
 			}
+			event.CreatedAt = pending.CreatedAt
+			created = true
 		}
 	}
 
-	// Process and save event data
-	for i := range allEventData {
-		allEventData[i].EventID = event.ID
+	version, err := s.saveVersion(event.ID, file, parsedData)
+	if err != nil {
+		if created {
+			s.discardNewEvent(event.ID)
+		}
+		return nil, err
 	}
+	event.ActiveVersionID = version.ID
 
-	var recordsInserted int
-	if len(allEventData) > 0 {
-		var err error
-		recordsInserted, err = s.eventRepository.SaveAllData(allEventData)
-		if err != nil {
-			return nil, fmt.Errorf("could not save event data: %w", err)
+	// Publicar los nuevos participantes reemplazando los anteriores en una sola operación
+	recordsInserted, err := s.eventRepository.ReplaceEventData(event, allEventData)
+	if err != nil {
+		s.discardVersion(version)
+		if created {
+			s.discardNewEvent(event.ID)
 		}
+		return nil, err
 	}
 
 	message := ""
@@ -688,222 +848,480 @@ func (s *eventService) parseRaceCheckFile(file io.ReadSeeker, fileHash string, e
 		message = "Archivo cargado pero no contiene registros de participantes."
 	}
 
+	uploadID := s.recordUpload(event.ID, fileName+fileExtension, fileHash, recordsInserted, false, report)
+	if reprocessed {
+		s.recordChanges(changeSource(event.ID, uploadID, version), previousData, allEventData)
+	}
+	s.onResultsPublished(event.ID, allEventData)
+
 	return &ports.UploadResult{
 		EventID:         event.ID.Hex(),
 		RecordsInserted: recordsInserted,
 		Reprocessed:     reprocessed,
 		Message:         message,
+		UploadID:        uploadID,
+		Report:          &report,
+	}, nil
+}
+
+func (s *eventService) UploadToEvent(fileHeader *multipart.FileHeader, clientHash string, eventID string, options ports.UploadOptions) (*ports.UploadResult, error) {
+	existingEvent, upload, err := s.readEventUpload(fileHeader, clientHash, eventID, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if file is the same as already uploaded
+	fmt.Printf("[DEBUG] Existing event FileHash: %s\n", existingEvent.FileHash)
+	if existingEvent.FileHash == upload.hash {
+		fmt.Printf("[INFO] File hash matches existing event hash. No update needed.\n")
+		return identicalUploadResult(existingEvent), nil
+	}
+
+	// 6. Parse file with existing event
+	fmt.Printf("[DEBUG] Starting to parse file for existing event: %s (ID: %s)\n", existingEvent.Name, existingEvent.ID.Hex())
+	result, err := s.parseRaceCheckFileForEvent(upload, existingEvent)
+	if err != nil {
+		fmt.Printf("[ERROR] Failed to parse file: %v\n", err)
+		return nil, err
+	}
+
+	fmt.Printf("[DEBUG] Parse result: EventID=%s, RecordsInserted=%d, Reprocessed=%v\n", result.EventID, result.RecordsInserted, result.Reprocessed)
+	return result, nil
+}
+
+// PreviewUploadToEvent interpreta y valida el archivo sin modificar el evento, y retorna
+// lo que cambiaría junto con un token para confirmar la carga con CommitUploadPreview
+func (s *eventService) PreviewUploadToEvent(fileHeader *multipart.FileHeader, clientHash string, eventID string, options ports.UploadOptions) (*ports.UploadPreview, error) {
+	existingEvent, upload, err := s.readEventUpload(fileHeader, clientHash, eventID, options)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments, err := s.loadAdjustments(existingEvent.ID)
+	if err != nil {
+		return nil, err
+	}
+	parsed, parsedData, report, err := parseUpload(upload, existingEvent, adjustments)
+	if err != nil {
+		return nil, err
+	}
+	s.checkEventStartList(existingEvent, parsed, &report)
+
+	current, err := s.eventRepository.FindAllData(existingEvent.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load current event data: %w", err)
+	}
+
+	expiresAt := time.Now().Add(uploadPreviewTTL)
+	token, err := s.previews.put(&uploadPreview{
+		eventID:   existingEvent.ID,
+		file:      upload,
+		baseHash:  existingEvent.FileHash,
+		expiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create upload preview: %w", err)
+	}
+
+	sampleSize := previewSampleSize
+	if len(parsedData.data) < sampleSize {
+		sampleSize = len(parsedData.data)
+	}
+
+	return &ports.UploadPreview{
+		Token:               token,
+		ExpiresAt:           expiresAt,
+		EventID:             existingEvent.ID.Hex(),
+		EventName:           existingEvent.Name,
+		FileEventName:       parsed.Header.Name,
+		FileName:            upload.name,
+		FileHash:            upload.hash,
+		Identical:           existingEvent.FileHash == upload.hash,
+		Races:               parsedData.races,
+		RecordsCount:        len(parsedData.data),
+		CurrentRecordsCount: len(current),
+		SampleRows:          parsedData.data[:sampleSize],
+		Report:              report,
+		WouldReject:         exceedsErrorThreshold(report),
+		Diff:                diffResults(current, parsedData.data),
 	}, nil
 }
 
-func (s *eventService) UploadToEvent(fileHeader *multipart.FileHeader, clientHash string, eventID string) (*ports.UploadResult, error) {
+// CommitUploadPreview publica el archivo de una vista previa generada con PreviewUploadToEvent
+func (s *eventService) CommitUploadPreview(eventID string, token string) (*ports.UploadResult, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, ok := s.previews.take(token, event.ID)
+	if !ok {
+		return nil, ErrUploadPreviewNotFound
+	}
+	if event.FileHash != preview.baseHash {
+		return nil, ErrUploadPreviewOutdated
+	}
+	if event.FileHash == preview.file.hash {
+		return identicalUploadResult(event), nil
+	}
+
+	fmt.Printf("[DEBUG] Committing upload preview for event: %s (ID: %s)\n", event.Name, event.ID.Hex())
+	return s.parseRaceCheckFileForEvent(preview.file, event)
+}
+
+// uploadedFile es un archivo de resultados ya verificado y leído en memoria. Los archivos
+// que no están en formato racecheck se convierten con su importador al interpretarlos.
+type uploadedFile struct {
+	name          string
+	hash          string
+	content       []byte
+	uploadedBy    string
+	format        string
+	importer      importer.Importer
+	importOptions importer.Options
+}
+
+func newUploadedFile(name, hash string, content []byte, imp importer.Importer, options ports.UploadOptions) uploadedFile {
+	file := uploadedFile{
+		name:       name,
+		hash:       hash,
+		content:    content,
+		uploadedBy: options.UploadedBy,
+		format:     importer.FormatRaceCheck,
+		importer:   imp,
+	}
+	if imp != nil {
+		file.format = imp.Format()
+		file.importOptions = importer.Options{
+			ColumnMapping: options.ColumnMapping,
+			EventName:     options.EventName,
+		}
+		if delimiter := []rune(options.Delimiter); len(delimiter) > 0 {
+			file.importOptions.Delimiter = delimiter[0]
+		}
+		// Sin nombre de evento en el archivo, usar el nombre del archivo
+		if file.importOptions.EventName == "" {
+			file.importOptions.EventName = strings.TrimSuffix(name, filepath.Ext(name))
+		}
+	}
+	return file
+}
+
+// parse interpreta el archivo con el parser racecheck o con el importador de su formato
+func (f uploadedFile) parse() (*racecheck.File, error) {
+	if f.importer == nil {
+		return racecheck.Parse(bytes.NewReader(f.content))
+	}
+	parsed, err := f.importer.Import(bytes.NewReader(f.content), f.importOptions)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+	return parsed, nil
+}
+
+// resolveFormat obtiene el importador para el formato indicado o, si no se indicó, para la
+// extensión del archivo. Retorna nil para los archivos racecheck, que se leen directamente.
+func (s *eventService) resolveFormat(fileName string, options ports.UploadOptions) (importer.Importer, error) {
+	ext := filepath.Ext(fileName)
+	format := strings.ToLower(strings.TrimSpace(options.Format))
+
+	if format == importer.FormatRaceCheck || (format == "" && ext == os.Getenv("RACECHECK_EXTENSION")) {
+		return nil, nil
+	}
+
+	var imp importer.Importer
+	var err error
+	if format != "" {
+		imp, err = s.importers.ForFormat(format)
+	} else {
+		imp, err = s.importers.ForExtension(ext)
+	}
+	if err != nil {
+		fmt.Printf("[ERROR] Unsupported upload format: %v\n", err)
+		return nil, ErrInvalidFileExtension
+	}
+	return imp, nil
+}
+
+// readEventUpload verifica el evento, la extensión y el hash del archivo, y retorna
+// el contenido del archivo
+func (s *eventService) readEventUpload(fileHeader *multipart.FileHeader, clientHash string, eventID string, options ports.UploadOptions) (*domain.Event, uploadedFile, error) {
 	// 1. Validate eventID
 	objID, err := primitive.ObjectIDFromHex(eventID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid event ID: %w", err)
+		return nil, uploadedFile{}, fmt.Errorf("invalid event ID: %w", err)
 	}
 
 	// 2. Check if event exists
 	existingEvent, err := s.eventRepository.FindByID(objID)
 	if err != nil {
-		return nil, fmt.Errorf("could not find event: %w", err)
+		return nil, uploadedFile{}, fmt.Errorf("could not find event: %w", err)
 	}
 	if existingEvent == nil {
-		return nil, fmt.Errorf("event not found")
+		return nil, uploadedFile{}, fmt.Errorf("event not found")
 	}
 
-	// 3. Validate file extension
-	expectedExt := os.Getenv("RACECHECK_EXTENSION")
-	fmt.Printf("[DEBUG] Expected extension: %s, File extension: %s\n", expectedExt, filepath.Ext(fileHeader.Filename))
-	if filepath.Ext(fileHeader.Filename) != expectedExt {
+	// 3. Validate file extension or requested format
+	fmt.Printf("[DEBUG] Expected extension: %s, File extension: %s, Format: %s\n", os.Getenv("RACECHECK_EXTENSION"), filepath.Ext(fileHeader.Filename), options.Format)
+	imp, err := s.resolveFormat(fileHeader.Filename, options)
+	if err != nil {
 		fmt.Printf("[ERROR] Invalid file extension: %s\n", filepath.Ext(fileHeader.Filename))
-		return nil, ErrInvalidFileExtension
+		return nil, uploadedFile{}, err
 	}
 
 	file, err := fileHeader.Open()
 	if err != nil {
 		fmt.Printf("[ERROR] Failed to open file: %v\n", err)
-		return nil, fmt.Errorf("could not open file: %w", err)
+		return nil, uploadedFile{}, fmt.Errorf("could not open file: %w", err)
 	}
 	defer file.Close()
 
-	// 4. Calculate file hash
-	hash := sha256.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		fmt.Printf("[ERROR] Failed to calculate hash: %v\n", err)
-		return nil, fmt.Errorf("could not calculate file hash: %w", err)
+	content, err := io.ReadAll(file)
+	if err != nil {
+		fmt.Printf("[ERROR] Failed to read file: %v\n", err)
+		return nil, uploadedFile{}, fmt.Errorf("could not read file: %w", err)
 	}
-	calculatedHash := hex.EncodeToString(hash.Sum(nil))
+
+	// 4. Calculate file hash
+	sum := sha256.Sum256(content)
+	calculatedHash := hex.EncodeToString(sum[:])
 	fmt.Printf("[DEBUG] Calculated hash: %s\n", calculatedHash)
 
 	// 5. Compare hashes
 	if calculatedHash != clientHash {
 		fmt.Printf("[ERROR] Hash mismatch. Expected: %s, Got: %s\n", calculatedHash, clientHash)
-		return nil, ErrFileHashMismatch
+		return nil, uploadedFile{}, ErrFileHashMismatch
 	}
 
-	// Check if file is the same as already uploaded
-	fmt.Printf("[DEBUG] Existing event FileHash: %s\n", existingEvent.FileHash)
-	if existingEvent.FileHash == calculatedHash {
-		fmt.Printf("[INFO] File hash matches existing event hash. No update needed.\n")
-		return &ports.UploadResult{
-			EventID:         existingEvent.ID.Hex(),
-			RecordsInserted: 0,
-			Reprocessed:     false,
-			Message:         "El archivo es idéntico al archivo cargado anteriormente. No se realizaron cambios.",
-		}, nil
+	return existingEvent, newUploadedFile(fileHeader.Filename, calculatedHash, content, imp, options), nil
+}
+
+func identicalUploadResult(event *domain.Event) *ports.UploadResult {
+	return &ports.UploadResult{
+		EventID:         event.ID.Hex(),
+		RecordsInserted: 0,
+		Reprocessed:     false,
+		Message:         "El archivo es idéntico al archivo cargado anteriormente. No se realizaron cambios.",
 	}
+}
 
-	// Rewind file for parsing
-	if _, err := file.Seek(0, io.SeekStart); err != nil {
-		fmt.Printf("[ERROR] Failed to rewind file: %v\n", err)
-		return nil, fmt.Errorf("could not rewind file: %w", err)
+// parseUpload interpreta el archivo para un evento existente, aplica los cambios de los
+// administradores y valida sus filas, sin modificar el evento. La línea con el nombre del
+// evento es opcional.
+func parseUpload(file uploadedFile, event *domain.Event, adjustments resultAdjustments) (*racecheck.File, *parsedEventData, domain.IngestReport, error) {
+	parsed, err := file.parse()
+	if err != nil && !errors.Is(err, racecheck.ErrNoHeader) {
+		return nil, nil, domain.IngestReport{}, err
+	}
+	if parsed == nil {
+		parsed = &racecheck.File{}
 	}
 
-	// 6. Parse file with existing event
-	fmt.Printf("[DEBUG] Starting to parse file for existing event: %s (ID: %s)\n", existingEvent.Name, existingEvent.ID.Hex())
-	result, err := s.parseRaceCheckFileForEvent(file, calculatedHash, existingEvent)
+	parsedData := buildEventData(parsed)
+	parsedData.finalize(event, adjustments)
+	return parsed, parsedData, validateIngest(parsed), nil
+}
+
+func (s *eventService) parseRaceCheckFileForEvent(file uploadedFile, event *domain.Event) (*ports.UploadResult, error) {
+	fileHash, fileName := file.hash, file.name
+
+	adjustments, err := s.loadAdjustments(event.ID)
 	if err != nil {
-		fmt.Printf("[ERROR] Failed to parse file: %v\n", err)
 		return nil, err
 	}
 
-	fmt.Printf("[DEBUG] Parse result: EventID=%s, RecordsInserted=%d, Reprocessed=%v\n", result.EventID, result.RecordsInserted, result.Reprocessed)
-	return result, nil
-}
-
-func (s *eventService) parseRaceCheckFileForEvent(file io.ReadSeeker, fileHash string, event *domain.Event) (*ports.UploadResult, error) {
-	scanner := bufio.NewScanner(file)
-	var allEventData []domain.EventData
-	var headers []string
-	var lineNum int = 0
-	var recordsSkipped int = 0
+	// The event name line is ignored since we're using the existing event
+	parsed, parsedData, report, err := parseUpload(file, event, adjustments)
+	if err != nil {
+		return nil, err
+	}
+	s.checkEventStartList(event, parsed, &report)
+	allEventData, modalitiesSlice, categoriesSlice := parsedData.data, parsedData.modalities, parsedData.categories
 
-	// Maps para recopilar valores únicos
-	uniqueModalities := make(map[string]bool)
-	uniqueCategories := make(map[string]bool)
+	// Log parsing information
+	fmt.Printf("[DEBUG] Parsed file for event '%s': %d total lines, %d records extracted, %d records skipped, encoding %s\n", event.Name, parsed.Lines, len(allEventData), len(parsed.Skipped), parsed.Encoding)
+	if len(allEventData) == 0 {
+		fmt.Printf("[DEBUG] Warning: No records extracted for event '%s'\n", event.Name)
+	}
 
-	// Skip first line (event name) since we're using existing event
-	if scanner.Scan() {
-		lineNum++
-		// Just skip the event name line
+	// No reemplazar los datos del evento si el archivo tiene demasiados errores
+	if exceedsErrorThreshold(report) {
+		return s.rejectUpload(event.ID, fileName, fileHash, len(allEventData), report), nil
 	}
 
-	// Process remaining lines
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
+	// Asignar valores únicos y cantidad de registros al evento
+	event.UniqueModalities = modalitiesSlice
+	event.UniqueCategories = categoriesSlice
+	event.RecordsCount = len(allEventData)
+	event.Races = parsedData.races
 
-		// Check if it's a race line (format: ;1|CAD 3G) - skip it
-		if strings.HasPrefix(line, ";") && strings.Contains(line, "|") && !strings.HasPrefix(line, ";SEXO") {
-			parts := strings.Split(strings.TrimPrefix(line, ";"), "|")
-			if len(parts) >= 2 {
-				headers = nil // Reset headers for new race
-				continue
-			}
-		}
+	// Participantes publicados antes de esta carga, para registrar los cambios
+	previousData, err := s.eventRepository.FindAllData(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load current event data: %w", err)
+	}
 
-		// Check if it's a header line (format: ;SEXO|NOMBRE|CHIP|...)
-		if strings.HasPrefix(line, ";SEXO|") {
-			headers = strings.Split(strings.TrimPrefix(line, ";"), "|")
-			continue
-		}
+	version, err := s.saveVersion(event.ID, file, parsedData)
+	if err != nil {
+		return nil, err
+	}
+	event.FileHash = fileHash
+	event.ActiveVersionID = version.ID
 
-		// Skip empty lines or lines without headers
-		if len(headers) == 0 {
-			continue
-		}
+	// Publicar los nuevos participantes reemplazando los anteriores en una sola operación
+	recordsInserted, err := s.eventRepository.ReplaceEventData(event, allEventData)
+	if err != nil {
+		s.discardVersion(version)
+		return nil, err
+	}
 
-		// Parse participant data
-		values := strings.Split(line, "|")
-		if len(values) != len(headers) {
-			recordsSkipped++
-			continue // Skip malformed data lines
-		}
+	uploadID := s.recordUpload(event.ID, fileName, fileHash, recordsInserted, false, report)
+	s.recordChanges(changeSource(event.ID, uploadID, version), previousData, allEventData)
+	s.onResultsPublished(event.ID, allEventData)
 
-		dataMap := make(map[string]string)
-		for i, h := range headers {
-			dataMap[h] = values[i]
-		}
+	message := "Archivo cargado pero no contiene registros de participantes."
+	if recordsInserted > 0 {
+		message = fmt.Sprintf("Archivo procesado exitosamente. %d registros insertados (actualización).", recordsInserted)
+	}
+	return &ports.UploadResult{
+		EventID:         event.ID.Hex(),
+		RecordsInserted: recordsInserted,
+		Reprocessed:     true,
+		Message:         message,
+		UploadID:        uploadID,
+		Report:          &report,
+	}, nil
+}
 
-		// Recopilar valores únicos para filtros
-		if modalidad, ok := dataMap["MODALIDAD"]; ok && modalidad != "" {
-			uniqueModalities[modalidad] = true
+// onResultsPublished actualiza el registro de atletas con los participantes publicados y luego
+// las clasificaciones de los campeonatos del evento, que reconocen a los corredores por su
+// atleta. Un error no invalida la publicación; se rehace en la siguiente carga del evento.
+func (s *eventService) onResultsPublished(eventID primitive.ObjectID, data []domain.EventData) {
+	if s.athletes != nil {
+		if err := s.athletes.LinkEventResults(eventID, data); err != nil {
+			fmt.Printf("[WARNING] could not link athletes of event %s: %v\n", eventID.Hex(), err)
 		}
-		if categoria, ok := dataMap["CATEGORIA"]; ok && categoria != "" {
-			uniqueCategories[categoria] = true
+	}
+	if s.series != nil {
+		if err := s.series.RecalculateEventSeries(eventID); err != nil {
+			fmt.Printf("[WARNING] could not recalculate series standings of event %s: %v\n", eventID.Hex(), err)
 		}
+	}
+}
 
-		// Add event data with type validation and conversion
-		convertedData := validateAndConvertData(dataMap)
-		eventData := domain.EventData{Data: convertedData}
-		allEventData = append(allEventData, eventData)
+// recordUpload guarda el registro de la carga con su reporte de validación y retorna su ID.
+// Un error al guardarlo no invalida la carga, que ya fue procesada.
+func (s *eventService) recordUpload(eventID primitive.ObjectID, fileName, fileHash string, recordsCount int, rejected bool, report domain.IngestReport) string {
+	upload := &domain.Upload{
+		ID:           primitive.NewObjectID(),
+		EventID:      eventID,
+		FileName:     fileName,
+		FileHash:     fileHash,
+		Encoding:     report.Encoding,
+		RecordsCount: recordsCount,
+		Rejected:     rejected,
+		Report:       report,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.eventRepository.SaveUpload(upload); err != nil {
+		fmt.Printf("[ERROR] Failed to save upload record: %v\n", err)
+		return ""
 	}
+	return upload.ID.Hex()
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
+// recordChanges guarda en el historial del evento las diferencias entre los participantes
+// publicados antes de la carga y los nuevos. entry indica el origen de los cambios.
+// Un error al guardarlo no invalida la carga.
+func (s *eventService) recordChanges(entry domain.ChangeLogEntry, previous []*domain.EventData, incoming []domain.EventData) {
+	entry.Diff = diffResults(previous, incoming)
+	if !entry.Diff.HasChanges() {
+		return
 	}
 
-	// Convertir mapas de valores únicos a slices
-	modalitiesSlice := make([]string, 0, len(uniqueModalities))
-	for modality := range uniqueModalities {
-		modalitiesSlice = append(modalitiesSlice, modality)
+	entry.ID = primitive.NewObjectID()
+	entry.CreatedAt = time.Now()
+	if err := s.eventRepository.SaveChangeLogEntry(&entry); err != nil {
+		fmt.Printf("[ERROR] Failed to save change log entry: %v\n", err)
 	}
-	sort.Strings(modalitiesSlice)
+}
 
-	categoriesSlice := make([]string, 0, len(uniqueCategories))
-	for category := range uniqueCategories {
-		categoriesSlice = append(categoriesSlice, category)
+// GetEventChanges obtiene el historial de cambios de resultados del evento, del más reciente
+// al más antiguo. Con bib, solo incluye los cambios de ese participante.
+func (s *eventService) GetEventChanges(eventID string, bib *string) ([]*domain.ChangeLogEntry, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
 	}
-	sort.Strings(categoriesSlice)
 
-	// Log parsing information
-	fmt.Printf("[DEBUG] Parsed file for event '%s': %d total lines, %d records extracted, %d records skipped\n", event.Name, lineNum, len(allEventData), recordsSkipped)
-	if len(allEventData) == 0 {
-		fmt.Printf("[DEBUG] Warning: No records extracted for event '%s'\n", event.Name)
+	entries, err := s.eventRepository.FindChangeLog(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get event changes: %w", err)
+	}
+	if bib == nil {
+		return entries, nil
 	}
 
-	// Asignar valores únicos y cantidad de registros al evento
-	event.UniqueModalities = modalitiesSlice
-	event.UniqueCategories = categoriesSlice
-	event.RecordsCount = len(allEventData)
+	filtered := []*domain.ChangeLogEntry{}
+	for _, entry := range entries {
+		if entry.Diff.FilterBib(*bib) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered, nil
+}
 
-	// Delete old data
-	if err := s.eventRepository.DeleteEventData(event.ID); err != nil {
-		return nil, fmt.Errorf("could not delete old event data: %w", err)
+// rejectUpload registra una carga rechazada por superar el umbral de errores de validación
+func (s *eventService) rejectUpload(eventID primitive.ObjectID, fileName, fileHash string, recordsCount int, report domain.IngestReport) *ports.UploadResult {
+	limit, _ := maxUploadErrors()
+	result := &ports.UploadResult{
+		RecordsInserted: 0,
+		Reprocessed:     false,
+		Message:         fmt.Sprintf("El archivo tiene %d errores de validación (máximo permitido: %d). No se realizaron cambios.", report.ErrorCount, limit),
+		UploadID:        s.recordUpload(eventID, fileName, fileHash, recordsCount, true, report),
+		Rejected:        true,
+		Report:          &report,
 	}
-	if err := s.eventRepository.UpdateFileStats(event.ID, fileHash, modalitiesSlice, categoriesSlice, len(allEventData)); err != nil {
-		return nil, fmt.Errorf("could not update event stats: %w", err)
+	if !eventID.IsZero() {
+		result.EventID = eventID.Hex()
 	}
+	return result
+}
 
-	// Process and save event data
-	for i := range allEventData {
-		allEventData[i].EventID = event.ID
+// GetEventUploads obtiene las cargas de archivos del evento, de la más reciente a la más antigua
+func (s *eventService) GetEventUploads(eventID string) ([]*domain.Upload, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
 	}
 
-	var recordsInserted int
-	if len(allEventData) > 0 {
-		var err error
-		recordsInserted, err = s.eventRepository.SaveAllData(allEventData)
+	uploads, err := s.eventRepository.FindUploads(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get uploads: %w", err)
+	}
+	return uploads, nil
+}
+
+// MigrateLegacyResults completa el resultado tipado de los participantes guardados
+// antes de que existiera, a partir de sus columnas originales
+func (s *eventService) MigrateLegacyResults() (int, error) {
+	migrated := 0
+	for {
+		batch, err := s.eventRepository.FindDataWithoutResult(500)
 		if err != nil {
-			return nil, fmt.Errorf("could not save event data: %w", err)
+			return migrated, fmt.Errorf("could not find legacy participants: %w", err)
+		}
+		if len(batch) == 0 {
+			return migrated, nil
 		}
 
-		message := fmt.Sprintf("Archivo procesado exitosamente. %d registros insertados (actualización).", recordsInserted)
-		return &ports.UploadResult{
-			EventID:         event.ID.Hex(),
-			RecordsInserted: recordsInserted,
-			Reprocessed:     true,
-			Message:         message,
-		}, nil
+		for _, eventData := range batch {
+			if err := s.eventRepository.UpdateDataResult(eventData.ID, mapResultFromData(eventData.Data)); err != nil {
+				return migrated, fmt.Errorf("could not migrate participant %s: %w", eventData.ID.Hex(), err)
+			}
+			migrated++
+		}
 	}
-
-	return &ports.UploadResult{
-		EventID:         event.ID.Hex(),
-		RecordsInserted: 0,
-		Reprocessed:     true,
-		Message:         "Archivo cargado pero no contiene registros de participantes.",
-	}, nil
 }
 
 // GetParticipantComparison obtiene el 1er lugar y los 5 participantes anteriores
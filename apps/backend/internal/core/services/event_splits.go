@@ -0,0 +1,72 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"errors"
+	"fmt"
+)
+
+// GetRaceSplits obtiene la clasificación de una carrera en cada punto de control o vuelta
+func (s *eventService) GetRaceSplits(eventID string, raceNumber int) (*ports.RaceSplits, error) {
+	race, results, err := s.loadRaceSplits(eventID, raceNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &ports.RaceSplits{
+		RaceNumber: race.Number,
+		RaceName:   race.Name,
+		Splits:     computeSplitRankings(results),
+	}, nil
+}
+
+// GetRaceLapChart obtiene la posición de cada participante de una carrera en cada punto de
+// control o vuelta
+func (s *eventService) GetRaceLapChart(eventID string, raceNumber int) (*ports.LapChart, error) {
+	race, results, err := s.loadRaceSplits(eventID, raceNumber)
+	if err != nil {
+		return nil, err
+	}
+	columns, participants := buildLapChart(results)
+	return &ports.LapChart{
+		RaceNumber:   race.Number,
+		RaceName:     race.Name,
+		Columns:      columns,
+		Participants: participants,
+	}, nil
+}
+
+// loadRaceSplits obtiene la carrera y los resultados de sus participantes. Los publicados
+// antes de guardar los tiempos parciales los obtienen de las columnas originales.
+func (s *eventService) loadRaceSplits(eventID string, raceNumber int) (*domain.Race, []*domain.Result, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var race *domain.Race
+	for i := range event.Races {
+		if event.Races[i].Number == raceNumber {
+			race = &event.Races[i]
+			break
+		}
+	}
+	if race == nil {
+		return nil, nil, errors.New("race not found")
+	}
+
+	data, err := s.eventRepository.FindRaceData(event.ID, raceNumber)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load race data: %w", err)
+	}
+
+	results := make([]*domain.Result, len(data))
+	for i := range data {
+		result := &data[i].Result
+		if len(result.Splits) == 0 {
+			result.Splits = mapResultFromData(data[i].Data).Splits
+		}
+		results[i] = result
+	}
+	return race, results, nil
+}
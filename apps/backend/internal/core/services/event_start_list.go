@@ -0,0 +1,169 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"backend/internal/racecheck"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"time"
+)
+
+// ImportStartList reemplaza la lista de inscritos del evento con la del archivo. El archivo
+// se lee con los mismos importadores que los resultados, por lo que basta con las columnas
+// de dorsal y nombre; chip, sexo, categoría, modalidad y club son opcionales.
+func (s *eventService) ImportStartList(fileHeader *multipart.FileHeader, eventID string, options ports.UploadOptions) (*ports.StartListImportResult, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	imp, err := s.resolveFormat(fileHeader.Filename, options)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, fmt.Errorf("could not open file: %w", err)
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read file: %w", err)
+	}
+
+	// La línea con el nombre del evento es opcional
+	parsed, err := newUploadedFile(fileHeader.Filename, "", content, imp, options).parse()
+	if err != nil && !errors.Is(err, racecheck.ErrNoHeader) {
+		return nil, err
+	}
+	if parsed == nil {
+		parsed = &racecheck.File{}
+	}
+
+	entries, report := startListEntries(parsed)
+	if exceedsErrorThreshold(report) {
+		return &ports.StartListImportResult{
+			EventID:      event.ID.Hex(),
+			EntriesCount: len(entries),
+			Message:      fmt.Sprintf("La lista de inscritos tiene %d errores de validación y no se guardó.", report.ErrorCount),
+			Rejected:     true,
+			Report:       report,
+		}, nil
+	}
+
+	startList := &domain.StartList{
+		FileName:     fileHeader.Filename,
+		EntriesCount: len(entries),
+		UploadedBy:   options.UploadedBy,
+		UploadedAt:   time.Now(),
+	}
+	// Una nueva carga de la lista conserva su visibilidad
+	if event.StartList != nil {
+		startList.Published = event.StartList.Published
+	}
+	if err := s.eventRepository.ReplaceStartList(event.ID, startList, entries); err != nil {
+		return nil, fmt.Errorf("could not save start list: %w", err)
+	}
+
+	return &ports.StartListImportResult{
+		EventID:      event.ID.Hex(),
+		EntriesCount: len(entries),
+		Message:      fmt.Sprintf("Lista de inscritos cargada exitosamente. %d inscritos.", len(entries)),
+		Report:       report,
+	}, nil
+}
+
+// GetStartList obtiene la lista de inscritos del evento. Sin includeHidden, solo se retorna
+// si está publicada.
+func (s *eventService) GetStartList(eventID string, includeHidden bool) (*ports.EventStartList, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.StartList == nil || (!event.StartList.Published && !includeHidden) {
+		return nil, ErrStartListNotFound
+	}
+
+	entries, err := s.eventRepository.FindStartList(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get start list: %w", err)
+	}
+	return &ports.EventStartList{
+		StartList: event.StartList,
+		Entries:   entries,
+	}, nil
+}
+
+// PublishStartList muestra u oculta al público la lista de inscritos del evento
+func (s *eventService) PublishStartList(eventID string, req *ports.PublishStartListRequest) (*domain.Event, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.StartList == nil {
+		return nil, ErrStartListNotFound
+	}
+
+	event.StartList.Published = req.Published
+	if err := s.eventRepository.UpdateStartList(event.ID, event.StartList); err != nil {
+		return nil, fmt.Errorf("could not update start list: %w", err)
+	}
+	return event, nil
+}
+
+// DeleteStartList elimina la lista de inscritos del evento
+func (s *eventService) DeleteStartList(eventID string) error {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return err
+	}
+	if event.StartList == nil {
+		return ErrStartListNotFound
+	}
+
+	if err := s.eventRepository.DeleteStartList(event.ID); err != nil {
+		return fmt.Errorf("could not delete start list: %w", err)
+	}
+	return nil
+}
+
+// ReconcileStartList compara los inscritos con los resultados publicados: quiénes no
+// partieron, qué dorsales no están inscritos y qué categorías no coinciden
+func (s *eventService) ReconcileStartList(eventID string) (*ports.StartListReconciliation, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.StartList == nil {
+		return nil, ErrStartListNotFound
+	}
+
+	entries, err := s.eventRepository.FindStartList(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get start list: %w", err)
+	}
+	data, err := s.eventRepository.FindAllData(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load event data: %w", err)
+	}
+	return reconcileStartList(entries, data), nil
+}
+
+// checkEventStartList compara el archivo de resultados con la lista de inscritos del evento,
+// si tiene una. Un error al leer la lista no invalida la carga.
+func (s *eventService) checkEventStartList(event *domain.Event, parsed *racecheck.File, report *domain.IngestReport) {
+	if event == nil || event.StartList == nil {
+		return
+	}
+
+	entries, err := s.eventRepository.FindStartList(event.ID)
+	if err != nil {
+		fmt.Printf("[WARNING] could not load start list of event %s: %v\n", event.ID.Hex(), err)
+		return
+	}
+	checkStartList(report, parsed, entries)
+}
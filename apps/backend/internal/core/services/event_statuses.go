@@ -0,0 +1,72 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"fmt"
+	"strings"
+)
+
+// GetParticipantStatuses obtiene los estados fijados por los administradores del evento
+func (s *eventService) GetParticipantStatuses(eventID string) ([]*domain.ParticipantStatus, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := s.eventRepository.FindParticipantStatuses(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get participant statuses: %w", err)
+	}
+	return statuses, nil
+}
+
+// UpdateParticipantStatus fija el estado del participante con el dorsal indicado. El estado
+// se guarda aparte de los participantes para aplicarlo también sobre las próximas cargas.
+func (s *eventService) UpdateParticipantStatus(eventID string, bib string, req *ports.UpdateParticipantStatusRequest) (*domain.EventData, error) {
+	status := strings.ToUpper(strings.TrimSpace(req.Status))
+	if status != "" && !domain.IsResultStatus(status) {
+		return nil, fmt.Errorf("%w: status must be one of %s, %s, %s or %s", ErrInvalidResultStatus,
+			domain.ResultStatusDNF, domain.ResultStatusDNS, domain.ResultStatusDSQ, domain.ResultStatusOTL)
+	}
+
+	bib = normalizeBib(bib)
+	if bib == "" {
+		return nil, ErrParticipantNotFound
+	}
+
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.eventRepository.FindAllData(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load event data: %w", err)
+	}
+	participant := findParticipant(data, bib)
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	if status == "" {
+		if err := s.eventRepository.DeleteParticipantStatus(event.ID, bib); err != nil {
+			return nil, fmt.Errorf("could not delete participant status: %w", err)
+		}
+	} else {
+		participantStatus := &domain.ParticipantStatus{
+			EventID: event.ID,
+			Bib:     bib,
+			Status:  status,
+			Reason:  strings.TrimSpace(req.Reason),
+		}
+		if err := s.eventRepository.SaveParticipantStatus(participantStatus); err != nil {
+			return nil, fmt.Errorf("could not save participant status: %w", err)
+		}
+	}
+
+	if err := s.reapplyAdjustments(event, data); err != nil {
+		return nil, err
+	}
+	return participant, nil
+}
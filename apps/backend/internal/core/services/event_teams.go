@@ -0,0 +1,60 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"fmt"
+	"strings"
+)
+
+// UpdateTeamScoring guarda la configuración de la clasificación por equipos del evento
+func (s *eventService) UpdateTeamScoring(eventID string, req *ports.UpdateTeamScoringRequest) (*domain.Event, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	scoring := &domain.TeamScoring{
+		Method:            strings.ToLower(strings.TrimSpace(req.Method)),
+		Scorers:           req.Scorers,
+		BySex:             req.BySex,
+		IncludeIncomplete: req.IncludeIncomplete,
+	}
+	if scoring.Method == "" {
+		scoring.Method = domain.TeamScoringPositions
+	}
+	if scoring.Method != domain.TeamScoringPositions && scoring.Method != domain.TeamScoringTimes {
+		return nil, fmt.Errorf("%w: method must be %q or %q", ErrInvalidTeamScoring, domain.TeamScoringPositions, domain.TeamScoringTimes)
+	}
+	if scoring.Scorers < 0 {
+		return nil, fmt.Errorf("%w: scorers must not be negative", ErrInvalidTeamScoring)
+	}
+	if scoring.Scorers == 0 {
+		scoring.Scorers = defaultTeamScorers
+	}
+
+	if err := s.eventRepository.UpdateTeamScoring(event.ID, scoring); err != nil {
+		return nil, fmt.Errorf("could not update team scoring: %w", err)
+	}
+	event.TeamScoring = scoring
+	return event, nil
+}
+
+// GetEventTeams calcula la clasificación por equipos a partir de los resultados publicados
+func (s *eventService) GetEventTeams(eventID string) (*ports.EventTeams, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.eventRepository.FindAllData(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load event data: %w", err)
+	}
+
+	scoring := effectiveTeamScoring(event.TeamScoring)
+	return &ports.EventTeams{
+		Scoring:   scoring,
+		Standings: computeTeamStandings(event.Races, data, scoring),
+	}, nil
+}
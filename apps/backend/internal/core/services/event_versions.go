@@ -0,0 +1,200 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// saveVersion guarda el archivo y sus participantes como una nueva versión del evento
+func (s *eventService) saveVersion(eventID primitive.ObjectID, file uploadedFile, parsedData *parsedEventData) (*domain.EventVersion, error) {
+	number, err := s.eventRepository.NextVersionNumber(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("could not allocate event version number: %w", err)
+	}
+
+	version := &domain.EventVersion{
+		ID:               primitive.NewObjectID(),
+		EventID:          eventID,
+		Number:           number,
+		FileName:         file.name,
+		FileHash:         file.hash,
+		Format:           file.format,
+		Content:          file.content,
+		RecordsCount:     len(parsedData.data),
+		UniqueModalities: parsedData.modalities,
+		UniqueCategories: parsedData.categories,
+		Races:            parsedData.races,
+		UploadedBy:       file.uploadedBy,
+		CreatedAt:        time.Now(),
+	}
+	if err := s.eventRepository.SaveVersion(version, parsedData.data); err != nil {
+		return nil, fmt.Errorf("could not save event version: %w", err)
+	}
+	return version, nil
+}
+
+// discardVersion elimina la versión guardada para una carga que no llegó a publicarse
+func (s *eventService) discardVersion(version *domain.EventVersion) {
+	if err := s.eventRepository.DeleteVersion(version.ID); err != nil {
+		fmt.Printf("[WARNING] Could not discard unpublished version %d: %v\n", version.Number, err)
+	}
+}
+
+// discardNewEvent elimina el evento creado por una carga que no llegó a publicarse, para
+// que no quede visible sin participantes
+func (s *eventService) discardNewEvent(eventID primitive.ObjectID) {
+	if err := s.eventRepository.Delete(eventID); err != nil {
+		fmt.Printf("[WARNING] Could not discard unpublished event %s: %v\n", eventID.Hex(), err)
+	}
+}
+
+// changeSource describe en el historial de cambios la carga que los originó
+func changeSource(eventID primitive.ObjectID, uploadID string, version *domain.EventVersion) domain.ChangeLogEntry {
+	entry := domain.ChangeLogEntry{
+		EventID:       eventID,
+		VersionID:     version.ID,
+		VersionNumber: version.Number,
+		FileName:      version.FileName,
+		FileHash:      version.FileHash,
+	}
+	if uploadID != "" {
+		entry.UploadID, _ = primitive.ObjectIDFromHex(uploadID)
+	}
+	return entry
+}
+
+// GetEventVersions obtiene las versiones del archivo de resultados del evento, de la más
+// reciente a la más antigua, indicando cuál está publicada
+func (s *eventService) GetEventVersions(eventID string) ([]*domain.EventVersion, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.eventRepository.FindVersions(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get event versions: %w", err)
+	}
+	for _, version := range versions {
+		version.Active = version.ID == event.ActiveVersionID
+	}
+	return versions, nil
+}
+
+// GetEventVersion obtiene una versión del evento con sus participantes
+func (s *eventService) GetEventVersion(eventID string, versionID string) (*ports.EventVersionDetail, error) {
+	event, version, err := s.findEventVersion(eventID, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.eventRepository.FindVersionData(version.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get version data: %w", err)
+	}
+
+	version.Active = version.ID == event.ActiveVersionID
+	return &ports.EventVersionDetail{
+		Version:      version,
+		Participants: data,
+	}, nil
+}
+
+// GetEventVersionFile obtiene una versión del evento con el contenido del archivo original
+func (s *eventService) GetEventVersionFile(eventID string, versionID string) (*domain.EventVersion, error) {
+	_, version, err := s.findEventVersion(eventID, versionID)
+	return version, err
+}
+
+// RollbackEventVersion vuelve a publicar los participantes de una versión anterior. Los
+// participantes se reemplazan con ReplaceEventData, igual que al subir un archivo, y el
+// cambio queda registrado en el historial del evento.
+func (s *eventService) RollbackEventVersion(eventID string, versionID string) (*ports.UploadResult, error) {
+	event, version, err := s.findEventVersion(eventID, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if version.ID == event.ActiveVersionID {
+		return &ports.UploadResult{
+			EventID:     event.ID.Hex(),
+			Reprocessed: false,
+			Message:     fmt.Sprintf("La versión %d ya está publicada. No se realizaron cambios.", version.Number),
+		}, nil
+	}
+
+	data, err := s.eventRepository.FindVersionData(version.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get version data: %w", err)
+	}
+
+	previousData, err := s.eventRepository.FindAllData(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load current event data: %w", err)
+	}
+
+	adjustments, err := s.loadAdjustments(event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Conservar las distancias configuradas manualmente y los cambios de los administradores
+	// hechos después de publicar la versión
+	parsedData := &parsedEventData{
+		data:       data,
+		races:      version.Races,
+		modalities: version.UniqueModalities,
+		categories: version.UniqueCategories,
+	}
+	parsedData.finalize(event, adjustments)
+
+	event.FileHash = version.FileHash
+	event.UniqueModalities = parsedData.modalities
+	event.UniqueCategories = parsedData.categories
+	event.RecordsCount = len(parsedData.data)
+	event.Races = parsedData.races
+	event.ActiveVersionID = version.ID
+
+	recordsInserted, err := s.eventRepository.ReplaceEventData(event, parsedData.data)
+	if err != nil {
+		return nil, fmt.Errorf("could not restore version %d: %w", version.Number, err)
+	}
+
+	entry := changeSource(event.ID, "", version)
+	entry.Rollback = true
+	s.recordChanges(entry, previousData, parsedData.data)
+	s.onResultsPublished(event.ID, parsedData.data)
+
+	return &ports.UploadResult{
+		EventID:         event.ID.Hex(),
+		RecordsInserted: recordsInserted,
+		Reprocessed:     true,
+		Message:         fmt.Sprintf("Resultados restaurados a la versión %d (%s). %d registros publicados.", version.Number, version.FileName, recordsInserted),
+	}, nil
+}
+
+// findEventVersion obtiene el evento y una de sus versiones
+func (s *eventService) findEventVersion(eventID string, versionID string) (*domain.Event, *domain.EventVersion, error) {
+	event, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	objID, err := primitive.ObjectIDFromHex(versionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidObjectID, err)
+	}
+
+	version, err := s.eventRepository.FindVersion(objID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get event version: %w", err)
+	}
+	if version == nil || version.EventID != event.ID {
+		return nil, nil, errors.New("version not found")
+	}
+	return event, version, nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/racecheck"
+	"backend/internal/utils"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// validateIngest revisa las filas del archivo antes de guardarlas y genera un reporte
+// con los problemas encontrados. Los errores indican datos que no se pueden publicar
+// tal como vienen; las advertencias, datos sospechosos que conviene revisar.
+func validateIngest(parsed *racecheck.File) domain.IngestReport {
+	report := domain.IngestReport{Encoding: parsed.Encoding, Issues: []domain.IngestIssue{}}
+
+	for _, skipped := range parsed.Skipped {
+		report.Add(domain.IngestIssue{
+			Line:     skipped.Line,
+			Code:     domain.IssueSkippedRow,
+			Severity: domain.IssueSeverityError,
+			Reason:   skipped.Reason,
+			Raw:      skipped.Raw,
+		})
+	}
+
+	// El dorsal y el chip deben ser únicos en todo el evento
+	bibLines := make(map[string]int)
+	chipLines := make(map[string]int)
+
+	for _, race := range parsed.Races {
+		previousPosition := 0
+		previousLine := 0
+		rows := make([]rankedRow, 0, len(race.Rows))
+
+		for _, row := range race.Rows {
+			result := mapResult(race.RowMap(row))
+			raw := strings.Join(row.Values, "|")
+			rows = append(rows, rankedRow{result: result, line: row.Line, raw: raw})
+			issue := func(code, severity, reason string) {
+				report.Add(domain.IngestIssue{
+					Line:       row.Line,
+					RaceNumber: race.Number,
+					Code:       code,
+					Severity:   severity,
+					Reason:     reason,
+					Raw:        raw,
+				})
+			}
+
+			if result.Name == "" {
+				issue(domain.IssueBlankName, domain.IssueSeverityError, "participant name is blank")
+			}
+
+			if result.Bib != "" {
+				if line, ok := bibLines[result.Bib]; ok {
+					issue(domain.IssueDuplicateBib, domain.IssueSeverityError, fmt.Sprintf("bib %s already used on line %d", result.Bib, line))
+				} else {
+					bibLines[result.Bib] = row.Line
+				}
+			}
+
+			if result.Chip != "" {
+				if line, ok := chipLines[result.Chip]; ok {
+					issue(domain.IssueDuplicateChip, domain.IssueSeverityWarning, fmt.Sprintf("chip %s already used on line %d", result.Chip, line))
+				} else {
+					chipLines[result.Chip] = row.Line
+				}
+			}
+
+			// Los participantes sin posición (DNF, DNS) pueden no tener tiempo
+			if result.Position > 0 && result.FinishTimeMs == 0 {
+				issue(domain.IssueInvalidTime, domain.IssueSeverityError, fmt.Sprintf("invalid finish time %q", result.FinishTimeText))
+			}
+
+			if result.Position > 0 {
+				if result.Position <= previousPosition {
+					issue(domain.IssueNonMonotonicPosition, domain.IssueSeverityWarning, fmt.Sprintf("position %d comes after position %d on line %d", result.Position, previousPosition, previousLine))
+				}
+				previousPosition = result.Position
+				previousLine = row.Line
+			}
+		}
+
+		checkRankings(&report, race.Number, rows)
+	}
+
+	return report
+}
+
+// rankedRow es una fila del archivo con su resultado, para comparar sus posiciones
+type rankedRow struct {
+	result domain.Result
+	line   int
+	raw    string
+}
+
+// checkRankings advierte las posiciones del archivo que no coinciden con las calculadas a
+// partir de los tiempos de la carrera. Los participantes sin tiempo válido ya se informan
+// como errores de tiempo.
+func checkRankings(report *domain.IngestReport, raceNumber int, rows []rankedRow) {
+	results := make([]*domain.Result, len(rows))
+	for i := range rows {
+		results[i] = &rows[i].result
+	}
+	computeRaceRankings(results)
+
+	// Cantidad de participantes que comparten cada posición calculada
+	tied := make(map[int]int)
+	tiedInCategory := make(map[string]int)
+	categoryKey := func(result *domain.Result) string {
+		return utils.NormalizeKey(result.Category) + "|" + strconv.Itoa(result.ComputedCategoryPosition)
+	}
+	for _, result := range results {
+		if result.ComputedPosition > 0 {
+			tied[result.ComputedPosition]++
+		}
+		if result.ComputedCategoryPosition > 0 {
+			tiedInCategory[categoryKey(result)]++
+		}
+	}
+
+	for _, row := range rows {
+		result := &row.result
+		warn := func(code, reason string) {
+			report.Add(domain.IngestIssue{
+				Line:       row.line,
+				RaceNumber: raceNumber,
+				Code:       code,
+				Severity:   domain.IssueSeverityWarning,
+				Reason:     reason,
+				Raw:        row.raw,
+			})
+		}
+
+		if result.FilePosition > 0 && result.ComputedPosition > 0 &&
+			!positionMatches(result.FilePosition, result.ComputedPosition, tied[result.ComputedPosition]) {
+			warn(domain.IssuePositionMismatch, fmt.Sprintf("position %d does not match position %d computed from the finish time", result.FilePosition, result.ComputedPosition))
+		}
+		if result.FileCategoryPosition > 0 && result.ComputedCategoryPosition > 0 &&
+			!positionMatches(result.FileCategoryPosition, result.ComputedCategoryPosition, tiedInCategory[categoryKey(result)]) {
+			warn(domain.IssueCategoryPositionMismatch, fmt.Sprintf("category position %d does not match category position %d computed from the finish time", result.FileCategoryPosition, result.ComputedCategoryPosition))
+		}
+	}
+}
+
+// maxUploadErrors obtiene la cantidad máxima de errores de validación permitidos en
+// una carga desde UPLOAD_MAX_ERRORS. Si no está configurada, no se rechazan cargas.
+func maxUploadErrors() (int, bool) {
+	value := strings.TrimSpace(os.Getenv("UPLOAD_MAX_ERRORS"))
+	if value == "" {
+		return 0, false
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		fmt.Printf("[WARNING] Ignoring invalid UPLOAD_MAX_ERRORS value: %q\n", value)
+		return 0, false
+	}
+	return limit, true
+}
+
+// exceedsErrorThreshold indica si el reporte supera el umbral de errores configurado
+func exceedsErrorThreshold(report domain.IngestReport) bool {
+	limit, ok := maxUploadErrors()
+	return ok && report.ErrorCount > limit
+}
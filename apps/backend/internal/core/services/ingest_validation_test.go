@@ -0,0 +1,77 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/racecheck"
+	"strings"
+	"testing"
+)
+
+func TestValidateIngest(t *testing.T) {
+	content := strings.Join([]string{
+		"1|CORRIDA DE PRUEBA",
+		";1|10K",
+		";SEXO|NOMBRE|CHIP|DORSAL|MODALIDAD|CATEGORIA|TIEMPO|POSICION|POS.CAT.|RITMO",
+		"M|Ana|QT001|1|10K|Damas|00:40:00|1|1|00:00 min/Km",
+		"M||QT002|2|10K|Damas|00:41:00|2|2|00:00 min/Km",
+		"M|Luis|QT002|1|10K|Varones|00:39:00|2|1|00:00 min/Km",
+		"M|Pedro|QT004|4|10K|Varones|--:--|3|2|00:00 min/Km",
+		"M|Juan|QT005|5|10K|Varones|DNF|DNF|-|00:00 min/Km",
+		"M|Corto|QT006",
+	}, "\r\n")
+
+	parsed, err := racecheck.Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	report := validateIngest(parsed)
+
+	expected := map[string]int{
+		domain.IssueSkippedRow:           1,
+		domain.IssueBlankName:            1,
+		domain.IssueDuplicateBib:         1,
+		domain.IssueDuplicateChip:        1,
+		domain.IssueNonMonotonicPosition: 1,
+		domain.IssueInvalidTime:          1,
+		// Luis tiene el mejor tiempo, por lo que Ana y la fila sin nombre quedan desplazadas
+		domain.IssuePositionMismatch: 3,
+	}
+	got := make(map[string]int)
+	for _, issue := range report.Issues {
+		got[issue.Code]++
+	}
+	for code, count := range expected {
+		if got[code] != count {
+			t.Errorf("%s issues = %d, expected %d (%+v)", code, got[code], count, report.Issues)
+		}
+	}
+	if report.ErrorCount != 4 || report.WarningCount != 5 {
+		t.Errorf("ErrorCount = %d, WarningCount = %d, expected 4 and 5", report.ErrorCount, report.WarningCount)
+	}
+
+	for _, issue := range report.Issues {
+		if issue.Code == domain.IssueDuplicateBib && (issue.Line != 6 || issue.Raw == "") {
+			t.Errorf("duplicate bib issue = %+v, expected line 6 with raw content", issue)
+		}
+	}
+}
+
+func TestExceedsErrorThreshold(t *testing.T) {
+	report := domain.IngestReport{ErrorCount: 3}
+
+	t.Setenv("UPLOAD_MAX_ERRORS", "")
+	if exceedsErrorThreshold(report) {
+		t.Error("expected no rejection when UPLOAD_MAX_ERRORS is not set")
+	}
+
+	t.Setenv("UPLOAD_MAX_ERRORS", "3")
+	if exceedsErrorThreshold(report) {
+		t.Error("expected no rejection when errors equal the threshold")
+	}
+
+	t.Setenv("UPLOAD_MAX_ERRORS", "2")
+	if !exceedsErrorThreshold(report) {
+		t.Error("expected rejection when errors exceed the threshold")
+	}
+}
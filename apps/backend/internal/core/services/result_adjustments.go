@@ -0,0 +1,125 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// resultAdjustments son los cambios de los administradores que se guardan aparte de los
+// participantes y se vuelven a aplicar sobre cada carga del archivo del evento
+type resultAdjustments struct {
+	// corrections son las correcciones de datos, por dorsal normalizado con normalizeBib
+	corrections map[string]*domain.ResultCorrection
+	// statuses son los estados fijados, por dorsal normalizado con normalizeBib
+	statuses map[string]*domain.ParticipantStatus
+}
+
+// normalizeBib unifica el dorsal con el que se identifican los cambios de un participante
+func normalizeBib(bib string) string {
+	return strings.ToUpper(strings.TrimSpace(bib))
+}
+
+// loadAdjustments obtiene los cambios de los administradores del evento
+func (s *eventService) loadAdjustments(eventID primitive.ObjectID) (resultAdjustments, error) {
+	adjustments := resultAdjustments{
+		corrections: make(map[string]*domain.ResultCorrection),
+		statuses:    make(map[string]*domain.ParticipantStatus),
+	}
+
+	corrections, err := s.eventRepository.FindCorrections(eventID)
+	if err != nil {
+		return adjustments, fmt.Errorf("could not load result corrections: %w", err)
+	}
+	for _, correction := range corrections {
+		adjustments.corrections[normalizeBib(correction.Bib)] = correction
+	}
+
+	statuses, err := s.eventRepository.FindParticipantStatuses(eventID)
+	if err != nil {
+		return adjustments, fmt.Errorf("could not load participant statuses: %w", err)
+	}
+	for _, status := range statuses {
+		adjustments.statuses[normalizeBib(status.Bib)] = status
+	}
+	return adjustments, nil
+}
+
+// apply aplica los cambios sobre los participantes. Los datos corregidos o el estado fijado
+// antes y ya eliminados vuelven a los valores que indica el archivo.
+func (a resultAdjustments) apply(data []*domain.EventData) {
+	for _, item := range data {
+		result := &item.Result
+		if result.Corrected || result.StatusManual {
+			restoreFileValues(item)
+		}
+		if result.Bib == "" {
+			continue
+		}
+
+		bib := normalizeBib(result.Bib)
+		if correction, ok := a.corrections[bib]; ok {
+			applyCorrection(result, correction.Fields)
+		}
+		if status, ok := a.statuses[bib]; ok {
+			result.Status = status.Status
+			result.StatusReason = status.Reason
+			result.StatusManual = true
+		}
+	}
+}
+
+// restoreFileValues vuelve a los valores de las columnas originales del archivo los campos
+// que un administrador puede cambiar
+func restoreFileValues(item *domain.EventData) {
+	result := &item.Result
+	fileResult := mapResultFromData(item.Data)
+
+	result.Name = fileResult.Name
+	result.Sex = fileResult.Sex
+	result.Category = fileResult.Category
+	result.Modality = fileResult.Modality
+	result.Club = fileResult.Club
+	result.FinishTimeText = fileResult.FinishTimeText
+	result.FinishTimeMs = fileResult.FinishTimeMs
+	result.Status = fileResult.Status
+	result.StatusReason = fileResult.StatusReason
+	result.Corrected = false
+	result.StatusManual = false
+}
+
+// applyCorrection reemplaza los campos corregidos. Un tiempo corregido indica que el
+// participante terminó, por lo que deja sin efecto un DNF o DNS del archivo.
+func applyCorrection(result *domain.Result, fields domain.CorrectionFields) {
+	if fields.IsEmpty() {
+		return
+	}
+	if fields.Name != "" {
+		result.Name = fields.Name
+	}
+	if fields.Sex != "" {
+		result.Sex = fields.Sex
+	}
+	if fields.Category != "" {
+		result.Category = fields.Category
+	}
+	if fields.Modality != "" {
+		result.Modality = fields.Modality
+	}
+	if fields.Club != "" {
+		result.Club = fields.Club
+	}
+	if fields.FinishTime != "" {
+		if d, ok := parseRaceTime(fields.FinishTime); ok {
+			result.FinishTimeText = fields.FinishTime
+			result.FinishTimeMs = d.Milliseconds()
+			if result.Status == domain.ResultStatusDNF || result.Status == domain.ResultStatusDNS {
+				result.Status = ""
+				result.StatusReason = ""
+			}
+		}
+	}
+	result.Corrected = true
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"errors"
+	"testing"
+)
+
+func TestResultAdjustmentsApply(t *testing.T) {
+	data := []*domain.EventData{
+		{RaceNumber: 1, Data: map[string]interface{}{"DORSAL": "1", "TIEMPO": "00:40:00", "POSICION": "1"}},
+		{RaceNumber: 1, Data: map[string]interface{}{"DORSAL": "2", "TIEMPO": "00:41:00", "POSICION": "2"}},
+		{RaceNumber: 1, Data: map[string]interface{}{"DORSAL": "3", "TIEMPO": "DNS", "POSICION": "-"}},
+	}
+	for _, item := range data {
+		item.Result = mapResultFromData(item.Data)
+	}
+
+	adjustments := resultAdjustments{statuses: map[string]*domain.ParticipantStatus{
+		"1": {Bib: "1", Status: domain.ResultStatusDSQ, Reason: "atajo"},
+	}}
+	adjustments.apply(data)
+	applyRankings(data, domain.RankingSourceComputed)
+
+	leader := data[0].Result
+	if leader.Status != domain.ResultStatusDSQ || !leader.StatusManual || leader.StatusReason != "atajo" || leader.Position != 0 {
+		t.Errorf("expected the admin status to apply, got %+v", leader)
+	}
+	if data[1].Result.Position != 1 {
+		t.Errorf("expected the next finisher to move up, got position %d", data[1].Result.Position)
+	}
+
+	// Al eliminar el estado fijado se vuelve al del archivo
+	resultAdjustments{}.apply(data)
+	applyRankings(data, domain.RankingSourceComputed)
+	if data[0].Result.Status != "" || data[0].Result.StatusManual || data[0].Result.Position != 1 {
+		t.Errorf("expected the file status to be restored, got %+v", data[0].Result)
+	}
+	if data[2].Result.Status != domain.ResultStatusDNS {
+		t.Errorf("expected the DNS from the file to remain, got %+v", data[2].Result)
+	}
+}
+
+func TestResultAdjustmentsApplyCorrection(t *testing.T) {
+	data := []*domain.EventData{
+		{RaceNumber: 1, Data: map[string]interface{}{"DORSAL": "a1", "NOMBRE": "Jon Perez", "TIEMPO": "00:40:00", "POSICION": "1"}},
+		{RaceNumber: 1, Data: map[string]interface{}{"DORSAL": "A2", "NOMBRE": "Ana Soto", "TIEMPO": "DNF", "POSICION": "-"}},
+	}
+	for _, item := range data {
+		item.Result = mapResultFromData(item.Data)
+	}
+
+	adjustments := resultAdjustments{corrections: map[string]*domain.ResultCorrection{
+		"A1": {Bib: "A1", Fields: domain.CorrectionFields{Name: "Jon Pérez", Club: "Club Norte"}},
+		"A2": {Bib: "A2", Fields: domain.CorrectionFields{FinishTime: "00:39:30"}},
+	}}
+	adjustments.apply(data)
+	applyRankings(data, domain.RankingSourceComputed)
+
+	first := data[0].Result
+	if first.Name != "Jon Pérez" || first.Club != "Club Norte" || !first.Corrected {
+		t.Errorf("expected the correction to apply, got %+v", first)
+	}
+	second := data[1].Result
+	if second.Status != "" || second.FinishTimeMs != 2370000 || second.ComputedPosition != 1 {
+		t.Errorf("expected the corrected time to clear the DNF, got %+v", second)
+	}
+
+	// Al eliminar la corrección se vuelve a los datos del archivo
+	resultAdjustments{}.apply(data)
+	if data[0].Result.Name != "Jon Perez" || data[0].Result.Club != "" || data[0].Result.Corrected {
+		t.Errorf("expected the file values to be restored, got %+v", data[0].Result)
+	}
+	if data[1].Result.Status != domain.ResultStatusDNF || data[1].Result.FinishTimeMs != 0 {
+		t.Errorf("expected the DNF from the file to be restored, got %+v", data[1].Result)
+	}
+}
+
+func TestCorrectionFields(t *testing.T) {
+	if _, err := correctionFields(&ports.CorrectionRequest{Note: "sin cambios"}); !errors.Is(err, ErrInvalidCorrection) {
+		t.Errorf("expected an empty correction to be rejected, got %v", err)
+	}
+	if _, err := correctionFields(&ports.CorrectionRequest{FinishTime: "40 min"}); !errors.Is(err, ErrInvalidCorrection) {
+		t.Errorf("expected an invalid time to be rejected, got %v", err)
+	}
+	fields, err := correctionFields(&ports.CorrectionRequest{Name: "  Ana Soto "})
+	if err != nil || fields.Name != "Ana Soto" {
+		t.Errorf("expected a trimmed name, got %+v, %v", fields, err)
+	}
+}
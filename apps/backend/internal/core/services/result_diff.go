@@ -0,0 +1,109 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// resultKey identifica a un participante entre dos archivos: por dorsal, por chip si no
+// tiene dorsal, y por nombre en último caso
+func resultKey(eventData *domain.EventData) string {
+	result := eventData.Result
+	switch {
+	case result.Bib != "":
+		return "bib:" + strings.ToUpper(result.Bib)
+	case result.Chip != "":
+		return "chip:" + strings.ToUpper(result.Chip)
+	default:
+		return "name:" + strconv.Itoa(eventData.RaceNumber) + ":" + strings.ToUpper(result.Name)
+	}
+}
+
+func newResultChange(key string, eventData *domain.EventData) domain.ResultChange {
+	return domain.ResultChange{
+		Key:        key,
+		Bib:        eventData.Result.Bib,
+		Chip:       eventData.Result.Chip,
+		Name:       eventData.Result.Name,
+		RaceNumber: eventData.RaceNumber,
+	}
+}
+
+// compareResults lista los campos publicados que difieren entre dos versiones de un participante
+func compareResults(before, after *domain.EventData) []domain.FieldChange {
+	var changes []domain.FieldChange
+	add := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, domain.FieldChange{Field: field, Before: oldValue, After: newValue})
+		}
+	}
+
+	add("race", before.RaceName, after.RaceName)
+	add("name", before.Result.Name, after.Result.Name)
+	add("modality", before.Result.Modality, after.Result.Modality)
+	add("category", before.Result.Category, after.Result.Category)
+	add("time", before.Result.FinishTimeText, after.Result.FinishTimeText)
+	add("status", before.Result.Status, after.Result.Status)
+	add("position", strconv.Itoa(before.Result.Position), strconv.Itoa(after.Result.Position))
+	add("categoryPosition", strconv.Itoa(before.Result.CategoryPosition), strconv.Itoa(after.Result.CategoryPosition))
+	return changes
+}
+
+// diffResults compara los participantes publicados con los de un nuevo archivo
+func diffResults(current []*domain.EventData, incoming []domain.EventData) domain.ResultDiff {
+	diff := domain.ResultDiff{
+		Added:   []domain.ResultChange{},
+		Removed: []domain.ResultChange{},
+		Changed: []domain.ResultChange{},
+	}
+
+	currentByKey := make(map[string]*domain.EventData, len(current))
+	for _, eventData := range current {
+		currentByKey[resultKey(eventData)] = eventData
+	}
+
+	seen := make(map[string]bool, len(incoming))
+	for i := range incoming {
+		eventData := &incoming[i]
+		key := resultKey(eventData)
+		if seen[key] {
+			// Claves repetidas ya se informan en el reporte de validación
+			continue
+		}
+		seen[key] = true
+
+		previous, ok := currentByKey[key]
+		if !ok {
+			diff.Added = append(diff.Added, newResultChange(key, eventData))
+			continue
+		}
+
+		if fields := compareResults(previous, eventData); len(fields) > 0 {
+			change := newResultChange(key, eventData)
+			change.Fields = fields
+			diff.Changed = append(diff.Changed, change)
+		} else {
+			diff.UnchangedCount++
+		}
+	}
+
+	for key, eventData := range currentByKey {
+		if !seen[key] {
+			diff.Removed = append(diff.Removed, newResultChange(key, eventData))
+		}
+	}
+	// El recorrido del mapa no tiene orden; ordenar para que el resultado sea estable
+	sort.Slice(diff.Removed, func(i, j int) bool {
+		if diff.Removed[i].RaceNumber != diff.Removed[j].RaceNumber {
+			return diff.Removed[i].RaceNumber < diff.Removed[j].RaceNumber
+		}
+		return diff.Removed[i].Key < diff.Removed[j].Key
+	})
+
+	diff.AddedCount = len(diff.Added)
+	diff.RemovedCount = len(diff.Removed)
+	diff.ChangedCount = len(diff.Changed)
+	return diff
+}
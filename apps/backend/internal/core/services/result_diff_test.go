@@ -0,0 +1,46 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"testing"
+)
+
+func TestDiffResults(t *testing.T) {
+	current := []*domain.EventData{
+		{RaceNumber: 1, Result: domain.Result{Bib: "1", Name: "Ana", FinishTimeText: "00:40:00", Position: 1}},
+		{RaceNumber: 1, Result: domain.Result{Bib: "2", Name: "Luis", FinishTimeText: "00:41:00", Position: 2}},
+		{RaceNumber: 1, Result: domain.Result{Chip: "QT003", Name: "Pedro", FinishTimeText: "00:42:00", Position: 3}},
+	}
+	incoming := []domain.EventData{
+		{RaceNumber: 1, Result: domain.Result{Bib: "2", Name: "Luis", FinishTimeText: "00:39:30", Position: 1}},
+		{RaceNumber: 1, Result: domain.Result{Bib: "1", Name: "Ana", FinishTimeText: "00:40:00", Position: 1}},
+		{RaceNumber: 1, Result: domain.Result{Bib: "4", Name: "Juan", FinishTimeText: "00:45:00", Position: 3}},
+	}
+
+	diff := diffResults(current, incoming)
+
+	if diff.AddedCount != 1 || diff.RemovedCount != 1 || diff.ChangedCount != 1 || diff.UnchangedCount != 1 {
+		t.Fatalf("counts = +%d -%d ~%d =%d, expected +1 -1 ~1 =1", diff.AddedCount, diff.RemovedCount, diff.ChangedCount, diff.UnchangedCount)
+	}
+	if diff.Added[0].Key != "bib:4" || diff.Removed[0].Key != "chip:QT003" {
+		t.Errorf("added = %+v, removed = %+v", diff.Added, diff.Removed)
+	}
+
+	changed := diff.Changed[0]
+	if changed.Key != "bib:2" || len(changed.Fields) != 2 {
+		t.Fatalf("changed = %+v, expected time and position changes for bib 2", changed)
+	}
+	if changed.Fields[0] != (domain.FieldChange{Field: "time", Before: "00:41:00", After: "00:39:30"}) {
+		t.Errorf("first field change = %+v", changed.Fields[0])
+	}
+	if changed.Fields[1] != (domain.FieldChange{Field: "position", Before: "2", After: "1"}) {
+		t.Errorf("second field change = %+v", changed.Fields[1])
+	}
+
+	if !diff.FilterBib("2") || diff.ChangedCount != 1 || diff.AddedCount != 0 || diff.RemovedCount != 0 {
+		t.Errorf("FilterBib(\"2\") = %+v, expected only the change for bib 2", diff)
+	}
+	if diff.FilterBib("99") {
+		t.Error("FilterBib(\"99\") should report no changes")
+	}
+}
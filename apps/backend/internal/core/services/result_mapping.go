@@ -0,0 +1,334 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/importer"
+	"backend/internal/utils"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Campos del resultado tipado a los que se puede asociar una columna
+const (
+	resultFieldSex              = "sex"
+	resultFieldName             = "name"
+	resultFieldChip             = "chip"
+	resultFieldBib              = "bib"
+	resultFieldModality         = "modality"
+	resultFieldCategory         = "category"
+	resultFieldTime             = "time"
+	resultFieldPosition         = "position"
+	resultFieldCategoryPosition = "categoryPosition"
+	resultFieldPace             = "pace"
+	resultFieldClub             = "club"
+	resultFieldStatus           = "status"
+	resultFieldStatusReason     = "statusReason"
+)
+
+// resultColumnAliases asocia los nombres de columna normalizados con utils.NormalizeKey
+// a cada campo del resultado. Incluye las variantes que se han visto en los archivos
+// y en los datos antiguos guardados en event_data.
+var resultColumnAliases = map[string]string{
+	"SEXO":   resultFieldSex,
+	"SEX":    resultFieldSex,
+	"GENERO": resultFieldSex,
+
+	"NOMBRE":       resultFieldName,
+	"NAME":         resultFieldName,
+	"PARTICIPANTE": resultFieldName,
+
+	"CHIP": resultFieldChip,
+
+	"DORSAL": resultFieldBib,
+	"BIB":    resultFieldBib,
+
+	"MODALIDAD": resultFieldModality,
+	"DISTANCIA": resultFieldModality,
+	"DISTANCE":  resultFieldModality,
+
+	"CATEGORIA": resultFieldCategory,
+	"CATEGORY":  resultFieldCategory,
+
+	"TIEMPO": resultFieldTime,
+	"TIME":   resultFieldTime,
+
+	"POSICION": resultFieldPosition,
+	"POSITION": resultFieldPosition,
+	"POS":      resultFieldPosition,
+
+	"POSCAT":            resultFieldCategoryPosition,
+	"POSICIONCATEGORIA": resultFieldCategoryPosition,
+	"CATEGORYPOSITION":  resultFieldCategoryPosition,
+
+	"RITMO": resultFieldPace,
+	"PACE":  resultFieldPace,
+
+	"CLUB":       resultFieldClub,
+	"EQUIPO":     resultFieldClub,
+	"TEAM":       resultFieldClub,
+	"CLUBEQUIPO": resultFieldClub,
+
+	"ESTADO": resultFieldStatus,
+	"STATUS": resultFieldStatus,
+
+	"MOTIVO": resultFieldStatusReason,
+	"REASON": resultFieldStatusReason,
+}
+
+// resultStatusMarkers asocia los textos normalizados con utils.NormalizeKey con los que los
+// archivos marcan, en el tiempo o la posición, a quienes no terminaron la carrera
+var resultStatusMarkers = map[string]string{
+	"DNF":       domain.ResultStatusDNF,
+	"RET":       domain.ResultStatusDNF,
+	"RETIRADO":  domain.ResultStatusDNF,
+	"ABANDONO":  domain.ResultStatusDNF,
+	"NOTERMINO": domain.ResultStatusDNF,
+
+	"DNS":      domain.ResultStatusDNS,
+	"NOPARTIO": domain.ResultStatusDNS,
+	"NOLARGO":  domain.ResultStatusDNS,
+	"AUSENTE":  domain.ResultStatusDNS,
+
+	"DSQ":           domain.ResultStatusDSQ,
+	"DQ":            domain.ResultStatusDSQ,
+	"DESC":          domain.ResultStatusDSQ,
+	"DESCALIFICADO": domain.ResultStatusDSQ,
+
+	"OTL":           domain.ResultStatusOTL,
+	"FUERADETIEMPO": domain.ResultStatusOTL,
+}
+
+var raceTimePattern = regexp.MustCompile(`^(?:(\d+):)?(\d{1,2}):(\d{2})(?:[.,](\d{1,3}))?$`)
+
+// mapResult construye el resultado tipado a partir de las columnas de una fila.
+// Las columnas que no corresponden a ningún campo se guardan en Extras; las de tiempos
+// parciales, además, en Splits.
+func mapResult(row map[string]string) domain.Result {
+	var result domain.Result
+	var splits []domain.Split
+	assigned := make(map[string]bool)
+
+	// Recorrer en orden para que, si dos columnas son alias del mismo campo, gane siempre la misma
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	for _, column := range columns {
+		value := strings.TrimSpace(row[column])
+		field, ok := resultColumnAliases[utils.NormalizeKey(column)]
+		if !ok || assigned[field] {
+			if result.Extras == nil {
+				result.Extras = make(map[string]string)
+			}
+			result.Extras[column] = value
+			if split, ok := parseSplit(column, value); ok {
+				splits = append(splits, split)
+			}
+			continue
+		}
+		assigned[field] = true
+
+		switch field {
+		case resultFieldSex:
+			result.Sex = value
+		case resultFieldName:
+			result.Name = value
+		case resultFieldChip:
+			result.Chip = value
+		case resultFieldBib:
+			result.Bib = value
+		case resultFieldModality:
+			result.Modality = value
+		case resultFieldCategory:
+			result.Category = value
+		case resultFieldTime:
+			result.FinishTimeText = value
+			if d, ok := parseRaceTime(value); ok {
+				result.FinishTimeMs = d.Milliseconds()
+			} else {
+				setStatusMarker(&result, value)
+			}
+		case resultFieldPosition:
+			result.FilePosition = parsePosition(value)
+			result.Position = result.FilePosition
+			if result.FilePosition == 0 {
+				setStatusMarker(&result, value)
+			}
+		case resultFieldCategoryPosition:
+			result.FileCategoryPosition = parsePosition(value)
+			result.CategoryPosition = result.FileCategoryPosition
+		case resultFieldPace:
+			result.Pace = value
+		case resultFieldClub:
+			result.Club = value
+		case resultFieldStatus:
+			setStatusMarker(&result, value)
+		case resultFieldStatusReason:
+			result.StatusReason = value
+		}
+	}
+
+	// El motivo solo tiene sentido para quienes no terminaron
+	if result.Status == "" {
+		result.StatusReason = ""
+	}
+	result.Splits = completeSplits(splits)
+	return result
+}
+
+// parseSplit interpreta una columna de tiempo parcial. Los valores vacíos o que no son
+// tiempos, p. ej. un punto de control no registrado, se ignoran.
+func parseSplit(column, value string) (domain.Split, bool) {
+	lap, number, ok := importer.ParseSplitColumn(column)
+	if !ok {
+		return domain.Split{}, false
+	}
+	d, ok := parseRaceTime(value)
+	if !ok || d <= 0 {
+		return domain.Split{}, false
+	}
+
+	split := domain.Split{Number: number, Column: column, Text: value}
+	if lap {
+		split.Kind = domain.SplitKindLap
+		split.LapMs = d.Milliseconds()
+	} else {
+		split.Kind = domain.SplitKindCheckpoint
+		split.ElapsedMs = d.Milliseconds()
+	}
+	return split, true
+}
+
+// completeSplits ordena los tiempos parciales, primero los puntos de control y luego las
+// vueltas, y calcula el tiempo de cada tramo a partir del acumulado y viceversa. Solo se
+// calculan con el parcial inmediatamente anterior.
+func completeSplits(splits []domain.Split) []domain.Split {
+	if len(splits) == 0 {
+		return nil
+	}
+	sort.Slice(splits, func(i, j int) bool {
+		if splits[i].Kind != splits[j].Kind {
+			return splits[i].Kind == domain.SplitKindCheckpoint
+		}
+		return splits[i].Number < splits[j].Number
+	})
+
+	for i := range splits {
+		split := &splits[i]
+		var previous *domain.Split
+		if i > 0 && splits[i-1].Kind == split.Kind && splits[i-1].Number == split.Number-1 {
+			previous = &splits[i-1]
+		}
+
+		if split.Kind == domain.SplitKindCheckpoint {
+			if split.Number == 1 {
+				split.LapMs = split.ElapsedMs
+			} else if previous != nil && split.ElapsedMs > previous.ElapsedMs {
+				split.LapMs = split.ElapsedMs - previous.ElapsedMs
+			}
+		} else {
+			if split.Number == 1 {
+				split.ElapsedMs = split.LapMs
+			} else if previous != nil && previous.ElapsedMs > 0 {
+				split.ElapsedMs = previous.ElapsedMs + split.LapMs
+			}
+		}
+	}
+	return splits
+}
+
+// setStatusMarker asigna el estado indicado por un marcador del archivo, si el resultado
+// aún no tiene uno. El motivo puede venir después del marcador: "DSQ - cortó camino".
+func setStatusMarker(result *domain.Result, value string) {
+	if result.Status != "" {
+		return
+	}
+	status, reason, ok := parseResultStatus(value)
+	if !ok {
+		return
+	}
+	result.Status = status
+	if reason != "" && result.StatusReason == "" {
+		result.StatusReason = reason
+	}
+}
+
+// parseResultStatus reconoce un marcador de estado como "DNF", "No partió" o "DSQ (atajo)"
+// y retorna el estado y el motivo que lo acompaña
+func parseResultStatus(value string) (string, string, bool) {
+	value = strings.TrimSpace(value)
+	marker, reason := value, ""
+	if i := strings.IndexAny(value, "-:("); i >= 0 {
+		marker = value[:i]
+		reason = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(value[i+1:]), ")"))
+	}
+
+	status, ok := resultStatusMarkers[utils.NormalizeKey(marker)]
+	if !ok {
+		return "", "", false
+	}
+	return status, reason, true
+}
+
+// mapResultFromData construye el resultado tipado a partir de los datos ya
+// convertidos que se guardaban en event_data antes de existir el modelo tipado
+func mapResultFromData(data map[string]interface{}) domain.Result {
+	row := make(map[string]string, len(data))
+	for key, value := range data {
+		switch v := value.(type) {
+		case string:
+			row[key] = v
+		case float64:
+			row[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		case time.Time:
+			row[key] = v.Format("2006-01-02")
+		case nil:
+			row[key] = ""
+		default:
+			row[key] = fmt.Sprint(v)
+		}
+	}
+	return mapResult(row)
+}
+
+// parseRaceTime convierte un tiempo en formato HH:MM:SS, MM:SS o HH:MM:SS.mmm a duración
+func parseRaceTime(value string) (time.Duration, bool) {
+	matches := raceTimePattern.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return 0, false
+	}
+
+	hours, _ := strconv.Atoi(matches[1])
+	minutes, _ := strconv.Atoi(matches[2])
+	seconds, _ := strconv.Atoi(matches[3])
+	if seconds >= 60 || (matches[1] != "" && minutes >= 60) {
+		return 0, false
+	}
+
+	millis := 0
+	if matches[4] != "" {
+		// Completar a milisegundos: ".5" son 500 ms
+		millis, _ = strconv.Atoi((matches[4] + "00")[:3])
+	}
+
+	d := time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second +
+		time.Duration(millis)*time.Millisecond
+	return d, true
+}
+
+// parsePosition convierte una posición a entero. Retorna 0 si no es numérica.
+func parsePosition(value string) int {
+	position, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || position < 0 {
+		return 0
+	}
+	return position
+}
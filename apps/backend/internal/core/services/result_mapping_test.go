@@ -0,0 +1,165 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"testing"
+	"time"
+)
+
+func TestMapResult(t *testing.T) {
+	row := map[string]string{
+		"SEXO":      "M",
+		"NOMBRE":    "Cristobal Inostroza",
+		"CHIP":      "QT00121",
+		"DORSAL":    "121",
+		"MODALIDAD": "10K",
+		"CATEGORIA": "Senior A Varones",
+		"TIEMPO":    "00:33:37",
+		"POSICION":  "1",
+		"POS.CAT.":  "2",
+		"RITMO":     "00:00 min/Km",
+		"CLUB":      "Runners",
+		"EQUIPO":    "Relevo A",
+	}
+
+	result := mapResult(row)
+
+	if result.Sex != "M" || result.Name != "Cristobal Inostroza" || result.Chip != "QT00121" || result.Bib != "121" {
+		t.Errorf("unexpected identity fields: %+v", result)
+	}
+	if result.Modality != "10K" || result.Category != "Senior A Varones" {
+		t.Errorf("unexpected modality/category: %+v", result)
+	}
+	if result.FinishTimeMs != (33*time.Minute+37*time.Second).Milliseconds() || result.FinishTimeText != "00:33:37" {
+		t.Errorf("FinishTimeMs = %d, FinishTimeText = %q, expected 33m37s", result.FinishTimeMs, result.FinishTimeText)
+	}
+	if result.Position != 1 || result.CategoryPosition != 2 {
+		t.Errorf("Position = %d, CategoryPosition = %d, expected 1 and 2", result.Position, result.CategoryPosition)
+	}
+	if result.Club != "Runners" {
+		t.Errorf("Club = %q, expected Runners", result.Club)
+	}
+	// Una segunda columna de club no reemplaza a la primera y se conserva en Extras
+	if result.Extras["EQUIPO"] != "Relevo A" || len(result.Extras) != 1 {
+		t.Errorf("Extras = %v, expected only EQUIPO", result.Extras)
+	}
+}
+
+func TestMapResultFromLegacyData(t *testing.T) {
+	data := map[string]interface{}{
+		"Nombre":    "Ana",
+		"dorsal":    int32(45),
+		"Categoría": "Damas",
+		"POSICION":  int32(3),
+		"TIEMPO":    "45:10",
+	}
+
+	result := mapResultFromData(data)
+
+	if result.Name != "Ana" || result.Bib != "45" || result.Category != "Damas" || result.Position != 3 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if result.FinishTimeMs != (45*time.Minute + 10*time.Second).Milliseconds() {
+		t.Errorf("FinishTimeMs = %d, expected 45m10s", result.FinishTimeMs)
+	}
+}
+
+func TestParseResultStatus(t *testing.T) {
+	tests := []struct {
+		input  string
+		status string
+		reason string
+		ok     bool
+	}{
+		{"DNF", domain.ResultStatusDNF, "", true},
+		{"dns", domain.ResultStatusDNS, "", true},
+		{"No partió", domain.ResultStatusDNS, "", true},
+		{"DSQ - cortó camino", domain.ResultStatusDSQ, "cortó camino", true},
+		{"DQ (atajo)", domain.ResultStatusDSQ, "atajo", true},
+		{"Fuera de tiempo", domain.ResultStatusOTL, "", true},
+		{"--:--", "", "", false},
+		{"12", "", "", false},
+		{"", "", "", false},
+	}
+
+	for _, tt := range tests {
+		status, reason, ok := parseResultStatus(tt.input)
+		if status != tt.status || reason != tt.reason || ok != tt.ok {
+			t.Errorf("parseResultStatus(%q) = %q, %q, %v, expected %q, %q, %v", tt.input, status, reason, ok, tt.status, tt.reason, tt.ok)
+		}
+	}
+}
+
+func TestMapResultStatus(t *testing.T) {
+	dnf := mapResult(map[string]string{"TIEMPO": "DNF", "POSICION": "-"})
+	if dnf.Status != domain.ResultStatusDNF || dnf.Position != 0 {
+		t.Errorf("expected DNF from the time column, got %+v", dnf)
+	}
+
+	// El descalificado tiene tiempo, pero la posición lo marca
+	dsq := mapResult(map[string]string{"TIEMPO": "00:41:00", "POSICION": "DSQ", "MOTIVO": "Sin chip"})
+	if dsq.Status != domain.ResultStatusDSQ || dsq.StatusReason != "Sin chip" || dsq.FinishTimeMs == 0 {
+		t.Errorf("expected DSQ with reason, got %+v", dsq)
+	}
+
+	finisher := mapResult(map[string]string{"TIEMPO": "00:41:00", "POSICION": "3", "MOTIVO": "Sin chip"})
+	if finisher.Status != "" || finisher.StatusReason != "" {
+		t.Errorf("expected no status for a finisher, got %+v", finisher)
+	}
+}
+
+func TestMapResultSplits(t *testing.T) {
+	result := mapResult(map[string]string{
+		"NOMBRE":   "Ana",
+		"TIEMPO":   "01:30:00",
+		"SPLIT_2":  "00:50:00",
+		"SPLIT_1":  "00:20:00",
+		"SPLIT_4":  "01:20:00",
+		"SPLIT_3":  "-",
+		"VUELTA_1": "00:25:00",
+		"VUELTA_2": "00:30:00",
+	})
+
+	expected := []domain.Split{
+		{Kind: domain.SplitKindCheckpoint, Number: 1, Column: "SPLIT_1", Text: "00:20:00", ElapsedMs: 1200000, LapMs: 1200000},
+		{Kind: domain.SplitKindCheckpoint, Number: 2, Column: "SPLIT_2", Text: "00:50:00", ElapsedMs: 3000000, LapMs: 1800000},
+		// Sin el punto de control 3 no se conoce el tiempo del tramo
+		{Kind: domain.SplitKindCheckpoint, Number: 4, Column: "SPLIT_4", Text: "01:20:00", ElapsedMs: 4800000},
+		{Kind: domain.SplitKindLap, Number: 1, Column: "VUELTA_1", Text: "00:25:00", ElapsedMs: 1500000, LapMs: 1500000},
+		{Kind: domain.SplitKindLap, Number: 2, Column: "VUELTA_2", Text: "00:30:00", ElapsedMs: 3300000, LapMs: 1800000},
+	}
+	if len(result.Splits) != len(expected) {
+		t.Fatalf("expected %d splits, got %+v", len(expected), result.Splits)
+	}
+	for i, split := range result.Splits {
+		if split != expected[i] {
+			t.Errorf("split %d = %+v, expected %+v", i, split, expected[i])
+		}
+	}
+	if result.Extras["SPLIT_1"] != "00:20:00" {
+		t.Errorf("expected split columns to stay in Extras, got %v", result.Extras)
+	}
+}
+
+func TestParseRaceTime(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"00:33:37", 33*time.Minute + 37*time.Second, true},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second, true},
+		{"12:34", 12*time.Minute + 34*time.Second, true},
+		{"00:40:32.5", 40*time.Minute + 32*time.Second + 500*time.Millisecond, true},
+		{"00:61:00", 0, false},
+		{"DNF", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRaceTime(tt.input)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("parseRaceTime(%q) = %v, %v, expected %v, %v", tt.input, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/utils"
+	"sort"
+)
+
+// rankCounter asigna posiciones a corredores ordenados por tiempo. Los tiempos iguales
+// comparten la posición y la siguiente se salta: 1, 2, 2, 4.
+type rankCounter struct {
+	count    int
+	position int
+	lastMs   int64
+}
+
+func (c *rankCounter) next(finishTimeMs int64) int {
+	c.count++
+	if c.count == 1 || finishTimeMs != c.lastMs {
+		c.position = c.count
+		c.lastMs = finishTimeMs
+	}
+	return c.position
+}
+
+// rankedGroup retorna el contador del grupo, creándolo si no existe
+func rankedGroup(counters map[string]*rankCounter, key string) *rankCounter {
+	counter, ok := counters[key]
+	if !ok {
+		counter = &rankCounter{}
+		counters[key] = counter
+	}
+	return counter
+}
+
+// isRankable indica si el participante terminó la carrera. Se necesita un tiempo válido,
+// no tener un estado (DNF, DNS, DSQ, OTL) y, si el archivo trae posiciones para la carrera,
+// también una posición: hay archivos que dejan la posición vacía a los descalificados. Un
+// tiempo corregido por un administrador cuenta aunque el archivo no traiga la posición.
+func isRankable(result *domain.Result, filePositions bool) bool {
+	return result.FinishTimeMs > 0 && result.Status == "" && (!filePositions || result.FilePosition > 0 || result.Corrected)
+}
+
+// computeRaceRankings calcula las posiciones general, por sexo y por categoría de los
+// participantes de una carrera a partir de sus tiempos
+func computeRaceRankings(results []*domain.Result) {
+	filePositions := false
+	for _, result := range results {
+		if result.FilePosition > 0 {
+			filePositions = true
+			break
+		}
+	}
+
+	finishers := make([]*domain.Result, 0, len(results))
+	for _, result := range results {
+		result.ComputedPosition = 0
+		result.ComputedCategoryPosition = 0
+		result.SexPosition = 0
+		if isRankable(result, filePositions) {
+			finishers = append(finishers, result)
+		}
+	}
+	sort.SliceStable(finishers, func(i, j int) bool {
+		return finishers[i].FinishTimeMs < finishers[j].FinishTimeMs
+	})
+
+	overall := &rankCounter{}
+	bySex := make(map[string]*rankCounter)
+	byCategory := make(map[string]*rankCounter)
+	for _, result := range finishers {
+		result.ComputedPosition = overall.next(result.FinishTimeMs)
+		if sex := normalizeSex(result.Sex); sex != "" {
+			result.SexPosition = rankedGroup(bySex, sex).next(result.FinishTimeMs)
+		}
+		if category := utils.NormalizeKey(result.Category); category != "" {
+			result.ComputedCategoryPosition = rankedGroup(byCategory, category).next(result.FinishTimeMs)
+		}
+	}
+}
+
+// applyRankingSource publica las posiciones del archivo o las calculadas según el origen
+// configurado en el evento. Quienes tienen un estado no tienen posición publicada.
+func applyRankingSource(result *domain.Result, source string) {
+	if result.Status != "" {
+		result.Position = 0
+		result.CategoryPosition = 0
+	} else if source == domain.RankingSourceComputed {
+		result.Position = result.ComputedPosition
+		result.CategoryPosition = result.ComputedCategoryPosition
+	} else {
+		result.Position = result.FilePosition
+		result.CategoryPosition = result.FileCategoryPosition
+	}
+}
+
+// applyRankings calcula las posiciones de cada carrera del evento y publica las del origen
+// indicado
+func applyRankings(data []*domain.EventData, source string) {
+	byRace := make(map[int][]*domain.Result)
+	var raceOrder []int
+	for _, item := range data {
+		if _, seen := byRace[item.RaceNumber]; !seen {
+			raceOrder = append(raceOrder, item.RaceNumber)
+		}
+		byRace[item.RaceNumber] = append(byRace[item.RaceNumber], &item.Result)
+	}
+
+	for _, raceNumber := range raceOrder {
+		results := byRace[raceNumber]
+		computeRaceRankings(results)
+		for _, result := range results {
+			applyRankingSource(result, source)
+		}
+	}
+}
+
+// positionMatches indica si la posición del archivo es consistente con la calculada. Con
+// tiempos empatados el archivo puede usar cualquiera de las posiciones que ocupan.
+func positionMatches(filePosition, computed, tied int) bool {
+	return filePosition >= computed && filePosition < computed+tied
+}
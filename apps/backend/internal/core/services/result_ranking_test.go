@@ -0,0 +1,86 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/racecheck"
+	"strings"
+	"testing"
+)
+
+func TestComputeRaceRankings(t *testing.T) {
+	ana := &domain.Result{Name: "Ana", Sex: "F", Category: "Damas", FilePosition: 3, FinishTimeMs: 2_400_000}
+	luis := &domain.Result{Name: "Luis", Sex: "M", Category: "Varones", FilePosition: 1, FinishTimeMs: 2_300_000}
+	pedro := &domain.Result{Name: "Pedro", Sex: "M", Category: "Varones", FilePosition: 2, FinishTimeMs: 2_400_000}
+	dsq := &domain.Result{Name: "Juan", Sex: "M", Category: "Varones", FinishTimeMs: 2_000_000}
+	dnf := &domain.Result{Name: "Diego", Sex: "M", Category: "Varones"}
+
+	computeRaceRankings([]*domain.Result{ana, luis, pedro, dsq, dnf})
+
+	tests := []struct {
+		result                  *domain.Result
+		position, sex, category int
+	}{
+		{luis, 1, 1, 1},
+		// Ana y Pedro empatan en tiempo y comparten la posición general
+		{ana, 2, 1, 1},
+		{pedro, 2, 2, 2},
+		// Sin posición en un archivo que trae posiciones: descalificado aunque tenga tiempo
+		{dsq, 0, 0, 0},
+		{dnf, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		got := tt.result
+		if got.ComputedPosition != tt.position || got.SexPosition != tt.sex || got.ComputedCategoryPosition != tt.category {
+			t.Errorf("%s: positions = %d/%d/%d, expected %d/%d/%d", got.Name,
+				got.ComputedPosition, got.SexPosition, got.ComputedCategoryPosition, tt.position, tt.sex, tt.category)
+		}
+	}
+
+	// Sin posiciones en el archivo se clasifica a todos los que tienen tiempo
+	noPositions := []*domain.Result{{FinishTimeMs: 2_000_000}, {FinishTimeMs: 1_900_000}}
+	computeRaceRankings(noPositions)
+	if noPositions[0].ComputedPosition != 2 || noPositions[1].ComputedPosition != 1 {
+		t.Errorf("positions = %d and %d, expected 2 and 1", noPositions[0].ComputedPosition, noPositions[1].ComputedPosition)
+	}
+}
+
+func TestApplyRankingSource(t *testing.T) {
+	data := []*domain.EventData{
+		{RaceNumber: 1, Result: domain.Result{FilePosition: 1, FileCategoryPosition: 1, FinishTimeMs: 2_000_000}},
+		{RaceNumber: 1, Result: domain.Result{FilePosition: 2, FileCategoryPosition: 2, FinishTimeMs: 1_900_000}},
+	}
+
+	applyRankings(data, domain.RankingSourceComputed)
+	if data[0].Result.Position != 2 || data[1].Result.Position != 1 {
+		t.Errorf("computed positions = %d and %d, expected 2 and 1", data[0].Result.Position, data[1].Result.Position)
+	}
+
+	applyRankings(data, "")
+	if data[0].Result.Position != 1 || data[0].Result.CategoryPosition != 1 || data[0].Result.ComputedPosition != 2 {
+		t.Errorf("expected the file positions to be published, got %+v", data[0].Result)
+	}
+}
+
+func TestValidateIngestRankings(t *testing.T) {
+	content := strings.Join([]string{
+		"1|CORRIDA DE PRUEBA",
+		";1|10K",
+		";SEXO|NOMBRE|CHIP|DORSAL|MODALIDAD|CATEGORIA|TIEMPO|POSICION|POS.CAT.|RITMO",
+		"F|Ana|QT001|1|10K|Damas|00:40:00|1|1|",
+		"M|Luis|QT002|2|10K|Varones|00:40:00|2|1|",
+		"M|Pedro|QT003|3|10K|Varones|00:41:00|3|1|",
+		"M|Juan|QT004|4|10K|Varones|00:38:00|DSQ|-|",
+	}, "\r\n")
+
+	parsed, err := racecheck.Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	// Ana y Luis empatan, por lo que cualquiera puede ser primero. Pedro es el segundo de
+	// su categoría y Juan, descalificado, no desplaza a nadie.
+	report := validateIngest(parsed)
+	if len(report.Issues) != 1 || report.Issues[0].Code != domain.IssueCategoryPositionMismatch || report.Issues[0].Line != 6 {
+		t.Errorf("issues = %+v, expected one category position mismatch on line 6", report.Issues)
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"sort"
+)
+
+// splitKey identifica un punto de control o vuelta dentro de una carrera
+type splitKey struct {
+	kind   string
+	number int
+}
+
+// raceSplitKeys obtiene los puntos de control y vueltas que registró algún participante, en
+// el mismo orden que Result.Splits
+func raceSplitKeys(results []*domain.Result) ([]splitKey, map[splitKey]string) {
+	columns := make(map[splitKey]string)
+	var keys []splitKey
+	for _, result := range results {
+		for _, split := range result.Splits {
+			key := splitKey{kind: split.Kind, number: split.Number}
+			if _, seen := columns[key]; !seen {
+				columns[key] = split.Column
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].kind != keys[j].kind {
+			return keys[i].kind == domain.SplitKindCheckpoint
+		}
+		return keys[i].number < keys[j].number
+	})
+	return keys, columns
+}
+
+// findSplit retorna el parcial del participante en el punto de control o vuelta indicado
+func findSplit(result *domain.Result, key splitKey) (domain.Split, bool) {
+	for _, split := range result.Splits {
+		if split.Kind == key.kind && split.Number == key.number {
+			return split, true
+		}
+	}
+	return domain.Split{}, false
+}
+
+// computeSplitRankings clasifica a los participantes de una carrera por su tiempo acumulado
+// en cada punto de control o vuelta. Quienes no tienen el acumulado, porque les falta una
+// vuelta anterior, no tienen posición en ese parcial.
+func computeSplitRankings(results []*domain.Result) []ports.SplitRanking {
+	keys, columns := raceSplitKeys(results)
+	rankings := make([]ports.SplitRanking, 0, len(keys))
+
+	for _, key := range keys {
+		ranking := ports.SplitRanking{
+			Kind:    key.kind,
+			Number:  key.number,
+			Column:  columns[key],
+			Entries: []ports.SplitRankingEntry{},
+		}
+		for _, result := range results {
+			split, ok := findSplit(result, key)
+			if !ok || split.ElapsedMs == 0 {
+				continue
+			}
+			ranking.Entries = append(ranking.Entries, ports.SplitRankingEntry{
+				Bib:       result.Bib,
+				Name:      result.Name,
+				Category:  result.Category,
+				Text:      split.Text,
+				ElapsedMs: split.ElapsedMs,
+				LapMs:     split.LapMs,
+			})
+		}
+
+		entries := ranking.Entries
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].ElapsedMs < entries[j].ElapsedMs
+		})
+		counter := &rankCounter{}
+		for i := range entries {
+			entries[i].Position = counter.next(entries[i].ElapsedMs)
+			entries[i].GapToLeaderMs = entries[i].ElapsedMs - entries[0].ElapsedMs
+		}
+
+		// Posición según el tiempo del tramo
+		byLap := make([]*ports.SplitRankingEntry, 0, len(entries))
+		for i := range entries {
+			if entries[i].LapMs > 0 {
+				byLap = append(byLap, &entries[i])
+			}
+		}
+		sort.SliceStable(byLap, func(i, j int) bool {
+			return byLap[i].LapMs < byLap[j].LapMs
+		})
+		lapCounter := &rankCounter{}
+		for _, entry := range byLap {
+			entry.LapPosition = lapCounter.next(entry.LapMs)
+		}
+
+		rankings = append(rankings, ranking)
+	}
+	return rankings
+}
+
+// buildLapChart obtiene la posición de cada participante en cada punto de control o vuelta.
+// Los participantes se ordenan por su posición publicada y luego por lo que alcanzaron a
+// recorrer, para que quienes abandonaron queden después de quienes terminaron.
+func buildLapChart(results []*domain.Result) ([]string, []ports.LapChartRow) {
+	keys, columns := raceSplitKeys(results)
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = columns[key]
+	}
+
+	// Posición de cada participante en cada parcial, por tiempo acumulado
+	positions := make(map[splitKey]map[*domain.Result]int, len(keys))
+	for _, key := range keys {
+		type reading struct {
+			result    *domain.Result
+			elapsedMs int64
+		}
+		var readings []reading
+		for _, result := range results {
+			if split, ok := findSplit(result, key); ok && split.ElapsedMs > 0 {
+				readings = append(readings, reading{result: result, elapsedMs: split.ElapsedMs})
+			}
+		}
+		sort.SliceStable(readings, func(i, j int) bool {
+			return readings[i].elapsedMs < readings[j].elapsedMs
+		})
+		counter := &rankCounter{}
+		byResult := make(map[*domain.Result]int, len(readings))
+		for _, r := range readings {
+			byResult[r.result] = counter.next(r.elapsedMs)
+		}
+		positions[key] = byResult
+	}
+
+	type chartRow struct {
+		row      ports.LapChartRow
+		reached  int
+		lastMs   int64
+		position int
+	}
+	rows := make([]chartRow, 0, len(results))
+	for _, result := range results {
+		if len(result.Splits) == 0 {
+			continue
+		}
+		current := chartRow{
+			row: ports.LapChartRow{
+				Bib:       result.Bib,
+				Name:      result.Name,
+				Position:  result.Position,
+				Status:    result.Status,
+				Positions: make([]int, len(keys)),
+			},
+			position: result.Position,
+		}
+		for i, key := range keys {
+			current.row.Positions[i] = positions[key][result]
+			if split, ok := findSplit(result, key); ok && split.ElapsedMs > 0 {
+				current.reached = i + 1
+				current.lastMs = split.ElapsedMs
+			}
+		}
+		rows = append(rows, current)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if (a.position > 0) != (b.position > 0) {
+			return a.position > 0
+		}
+		if a.position != b.position {
+			return a.position < b.position
+		}
+		if a.reached != b.reached {
+			return a.reached > b.reached
+		}
+		return a.lastMs < b.lastMs
+	})
+
+	chart := make([]ports.LapChartRow, len(rows))
+	for i, row := range rows {
+		chart[i] = row.row
+	}
+	return names, chart
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"testing"
+)
+
+func splitResults() []*domain.Result {
+	rows := []map[string]string{
+		{"DORSAL": "1", "NOMBRE": "Ana", "POSICION": "1", "VUELTA_1": "00:21:00", "VUELTA_2": "00:19:00", "VUELTA_3": "00:18:00"},
+		{"DORSAL": "2", "NOMBRE": "Luis", "POSICION": "2", "VUELTA_1": "00:20:00", "VUELTA_2": "00:21:00", "VUELTA_3": "00:19:00"},
+		{"DORSAL": "3", "NOMBRE": "Pedro", "POSICION": "DNF", "VUELTA_1": "00:20:00", "VUELTA_2": "00:25:00"},
+	}
+	results := make([]*domain.Result, len(rows))
+	for i, row := range rows {
+		result := mapResult(row)
+		results[i] = &result
+	}
+	return results
+}
+
+func TestComputeSplitRankings(t *testing.T) {
+	rankings := computeSplitRankings(splitResults())
+	if len(rankings) != 3 {
+		t.Fatalf("expected 3 laps, got %d", len(rankings))
+	}
+
+	// Luis y Pedro empatan en la primera vuelta
+	first := rankings[0]
+	if first.Column != "VUELTA_1" || first.Entries[0].Bib != "2" || first.Entries[1].Bib != "3" ||
+		first.Entries[0].Position != 1 || first.Entries[1].Position != 1 || first.Entries[2].Position != 3 {
+		t.Errorf("unexpected first lap ranking: %+v", first.Entries)
+	}
+	if first.Entries[2].GapToLeaderMs != 60000 {
+		t.Errorf("expected Ana one minute behind, got %+v", first.Entries[2])
+	}
+
+	// Ana pasa adelante en la segunda vuelta, que además es la más rápida
+	second := rankings[1]
+	if second.Entries[0].Bib != "1" || second.Entries[0].ElapsedMs != 2400000 || second.Entries[0].LapPosition != 1 {
+		t.Errorf("unexpected second lap ranking: %+v", second.Entries)
+	}
+	if len(rankings[2].Entries) != 2 {
+		t.Errorf("expected only the finishers in the last lap, got %+v", rankings[2].Entries)
+	}
+}
+
+func TestBuildLapChart(t *testing.T) {
+	results := splitResults()
+	results[0].Position = 1
+	results[1].Position = 2
+
+	columns, rows := buildLapChart(results)
+	if len(columns) != 3 || columns[0] != "VUELTA_1" || columns[2] != "VUELTA_3" {
+		t.Fatalf("unexpected columns: %v", columns)
+	}
+	if len(rows) != 3 || rows[0].Bib != "1" || rows[1].Bib != "2" || rows[2].Bib != "3" {
+		t.Fatalf("unexpected row order: %+v", rows)
+	}
+
+	expected := [][]int{{3, 1, 1}, {1, 2, 2}, {1, 3, 0}}
+	for i, row := range rows {
+		for j, position := range row.Positions {
+			if position != expected[i][j] {
+				t.Errorf("bib %s positions = %v, expected %v", row.Bib, row.Positions, expected[i])
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// distancePattern reconoce modalidades como "10K", "21.1K", "42 KM" o "800M"
+var distancePattern = regexp.MustCompile(`(?i)^\s*(\d+(?:[.,]\d+)?)\s*(KMS?|K|MTS?|M)\s*$`)
+
+// parseDistanceKm obtiene la distancia en kilómetros a partir del nombre de una modalidad
+func parseDistanceKm(modality string) (float64, bool) {
+	matches := distancePattern.FindStringSubmatch(modality)
+	if matches == nil {
+		return 0, false
+	}
+
+	value, err := strconv.ParseFloat(strings.Replace(matches[1], ",", ".", 1), 64)
+	if err != nil || value <= 0 {
+		return 0, false
+	}
+
+	if strings.HasPrefix(strings.ToUpper(matches[2]), "M") {
+		value /= 1000
+	}
+	return value, true
+}
+
+// formatPace formatea un ritmo en milisegundos por kilómetro como "MM:SS min/Km"
+func formatPace(msPerKm int64) string {
+	totalSeconds := (msPerKm + 500) / 1000
+	return fmt.Sprintf("%02d:%02d min/Km", totalSeconds/60, totalSeconds%60)
+}
+
+// finalize conserva las distancias configuradas manualmente en la versión anterior
+// del evento, aplica los cambios de los administradores y calcula el ritmo, las diferencias
+// de tiempo y las posiciones de cada participante. previous es nil para un evento nuevo.
+func (p *parsedEventData) finalize(previous *domain.Event, adjustments resultAdjustments) {
+	manualDistances := make(map[int]float64)
+	rankingSource := ""
+	if previous != nil {
+		for _, race := range previous.Races {
+			if race.DistanceSource == domain.DistanceSourceManual {
+				manualDistances[race.Number] = race.DistanceKm
+			}
+		}
+		rankingSource = previous.RankingSource
+	}
+
+	for i := range p.races {
+		if distance, ok := manualDistances[p.races[i].Number]; ok {
+			p.races[i].DistanceKm = distance
+			p.races[i].DistanceSource = domain.DistanceSourceManual
+		}
+	}
+
+	data := make([]*domain.EventData, len(p.data))
+	for i := range p.data {
+		data[i] = &p.data[i]
+	}
+	adjustments.apply(data)
+	applyDerivedStats(data, p.races)
+	applyRankings(data, rankingSource)
+}
+
+// applyDerivedStats calcula, para cada carrera, el ritmo según su distancia y las
+// diferencias con el ganador y con el participante anterior según el tiempo final
+func applyDerivedStats(data []*domain.EventData, races []domain.Race) {
+	distances := make(map[int]float64, len(races))
+	for _, race := range races {
+		distances[race.Number] = race.DistanceKm
+	}
+
+	byRace := make(map[int][]*domain.Result)
+	var raceOrder []int
+	for _, item := range data {
+		raceNumber := item.RaceNumber
+		if _, seen := byRace[raceNumber]; !seen {
+			raceOrder = append(raceOrder, raceNumber)
+		}
+		byRace[raceNumber] = append(byRace[raceNumber], &item.Result)
+	}
+
+	for _, raceNumber := range raceOrder {
+		computeRaceStats(byRace[raceNumber], distances[raceNumber])
+	}
+}
+
+// computeRaceStats calcula el ritmo y las diferencias de tiempo de los participantes de una carrera
+func computeRaceStats(results []*domain.Result, distanceKm float64) {
+	finishers := make([]*domain.Result, 0, len(results))
+	for _, result := range results {
+		result.GapToLeaderMs = 0
+		result.GapToPreviousMs = 0
+		result.PaceMsPerKm = 0
+
+		// Las exportaciones traen "00:00 min/Km" cuando el software no conoce la distancia
+		if strings.HasPrefix(result.Pace, "00:00") {
+			result.Pace = ""
+		}
+
+		if result.FinishTimeMs > 0 && result.Status == "" {
+			finishers = append(finishers, result)
+		}
+	}
+
+	sort.SliceStable(finishers, func(i, j int) bool {
+		return finishers[i].FinishTimeMs < finishers[j].FinishTimeMs
+	})
+
+	for i, result := range finishers {
+		result.GapToLeaderMs = result.FinishTimeMs - finishers[0].FinishTimeMs
+		if i > 0 {
+			result.GapToPreviousMs = result.FinishTimeMs - finishers[i-1].FinishTimeMs
+		}
+		if distanceKm > 0 {
+			result.PaceMsPerKm = int64(float64(result.FinishTimeMs) / distanceKm)
+			result.Pace = formatPace(result.PaceMsPerKm)
+		}
+	}
+}
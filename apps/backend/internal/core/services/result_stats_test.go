@@ -0,0 +1,50 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"testing"
+)
+
+func TestParseDistanceKm(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+		ok       bool
+	}{
+		{"10K", 10, true},
+		{"21,1K", 21.1, true},
+		{"42 KM", 42, true},
+		{"800M", 0.8, true},
+		{"Senior A Varones", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseDistanceKm(tt.input)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("parseDistanceKm(%q) = %v, %v, expected %v, %v", tt.input, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestComputeRaceStats(t *testing.T) {
+	second := &domain.Result{FinishTimeMs: 2_100_000, Pace: "00:00 min/Km"}
+	leader := &domain.Result{FinishTimeMs: 2_000_000, Pace: "00:00 min/Km"}
+	third := &domain.Result{FinishTimeMs: 2_400_000}
+	dnf := &domain.Result{FinishTimeText: "DNF", Pace: "00:00 min/Km"}
+
+	computeRaceStats([]*domain.Result{second, dnf, third, leader}, 10)
+
+	if leader.GapToLeaderMs != 0 || leader.GapToPreviousMs != 0 {
+		t.Errorf("leader gaps = %d, %d, expected 0, 0", leader.GapToLeaderMs, leader.GapToPreviousMs)
+	}
+	if third.GapToLeaderMs != 400_000 || third.GapToPreviousMs != 300_000 {
+		t.Errorf("third gaps = %d, %d, expected 400000, 300000", third.GapToLeaderMs, third.GapToPreviousMs)
+	}
+	if leader.Pace != "03:20 min/Km" || leader.PaceMsPerKm != 200_000 {
+		t.Errorf("leader pace = %q (%d ms/km), expected 03:20 min/Km", leader.Pace, leader.PaceMsPerKm)
+	}
+	if dnf.Pace != "" || dnf.GapToLeaderMs != 0 {
+		t.Errorf("dnf result should have no pace or gap: %+v", dnf)
+	}
+}
@@ -0,0 +1,359 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"backend/internal/utils"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type seriesService struct {
+	seriesRepository  ports.SeriesRepository
+	eventRepository   ports.EventRepository
+	athleteRepository ports.AthleteRepository
+}
+
+func NewSeriesService(seriesRepository ports.SeriesRepository, eventRepository ports.EventRepository, athleteRepository ports.AthleteRepository) ports.SeriesService {
+	return &seriesService{
+		seriesRepository:  seriesRepository,
+		eventRepository:   eventRepository,
+		athleteRepository: athleteRepository,
+	}
+}
+
+func (s *seriesService) CreateSeries(req *ports.SeriesRequest) (*domain.Series, error) {
+	series := &domain.Series{}
+	if err := s.applySeriesRequest(series, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.seriesRepository.SaveSeries(series); err != nil {
+		return nil, fmt.Errorf("could not save series: %w", err)
+	}
+	if _, err := s.recalculate(series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (s *seriesService) GetSeriesList(page int, limit int) (*ports.FindSeriesResult, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+
+	result, err := s.seriesRepository.FindSeries(page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("could not get series: %w", err)
+	}
+	return result, nil
+}
+
+func (s *seriesService) GetSeries(id string) (*domain.Series, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidObjectID, err)
+	}
+
+	series, err := s.seriesRepository.FindSeriesByID(objID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get series: %w", err)
+	}
+	if series == nil {
+		return nil, ErrSeriesNotFound
+	}
+	return series, nil
+}
+
+func (s *seriesService) GetSeriesBySlug(slug string) (*domain.Series, error) {
+	series, err := s.seriesRepository.FindSeriesBySlug(slug)
+	if err != nil {
+		return nil, fmt.Errorf("could not get series: %w", err)
+	}
+	if series == nil {
+		return nil, ErrSeriesNotFound
+	}
+	return series, nil
+}
+
+// UpdateSeries reemplaza el campeonato y recalcula su clasificación
+func (s *seriesService) UpdateSeries(id string, req *ports.SeriesRequest) (*domain.Series, error) {
+	series, err := s.GetSeries(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applySeriesRequest(series, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.seriesRepository.UpdateSeries(series); err != nil {
+		return nil, fmt.Errorf("could not update series: %w", err)
+	}
+	if _, err := s.recalculate(series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (s *seriesService) DeleteSeries(id string) error {
+	series, err := s.GetSeries(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.seriesRepository.DeleteSeries(series.ID); err != nil {
+		return fmt.Errorf("could not delete series: %w", err)
+	}
+	return nil
+}
+
+// applySeriesRequest valida la solicitud y la aplica al campeonato. El slug se genera a
+// partir del nombre, como en los eventos.
+func (s *seriesService) applySeriesRequest(series *domain.Series, req *ports.SeriesRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidSeries)
+	}
+	if req.Scoring.BestOf < 0 || req.Scoring.DropWorst < 0 || req.Scoring.ParticipationPoints < 0 {
+		return fmt.Errorf("%w: bestOf, dropWorst and participationPoints must not be negative", ErrInvalidSeries)
+	}
+	for _, points := range req.Scoring.PointsTable {
+		if points < 0 {
+			return fmt.Errorf("%w: points must not be negative", ErrInvalidSeries)
+		}
+	}
+
+	eventIDs := make([]primitive.ObjectID, 0, len(req.EventIDs))
+	seen := make(map[primitive.ObjectID]bool)
+	for _, ref := range req.EventIDs {
+		event, err := s.findEvent(ref)
+		if err != nil {
+			return err
+		}
+		if seen[event.ID] {
+			return fmt.Errorf("%w: event %s is listed twice", ErrInvalidSeries, ref)
+		}
+		seen[event.ID] = true
+		eventIDs = append(eventIDs, event.ID)
+	}
+
+	if series.Name != name || series.Slug == "" {
+		slug, err := s.uniqueSlug(name, series.ID)
+		if err != nil {
+			return err
+		}
+		series.Slug = slug
+	}
+	series.Name = name
+	series.Description = strings.TrimSpace(req.Description)
+	series.EventIDs = eventIDs
+	series.Scoring = req.Scoring
+	return nil
+}
+
+// findEvent obtiene una fecha del campeonato por ID o slug
+func (s *seriesService) findEvent(ref string) (*domain.Event, error) {
+	var event *domain.Event
+	var err error
+	if objID, parseErr := primitive.ObjectIDFromHex(ref); parseErr == nil {
+		event, err = s.eventRepository.FindByID(objID)
+	} else {
+		event, err = s.eventRepository.FindBySlug(ref)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get event: %w", err)
+	}
+	if event == nil {
+		return nil, fmt.Errorf("%w: event %s not found", ErrInvalidSeries, ref)
+	}
+	return event, nil
+}
+
+func (s *seriesService) uniqueSlug(name string, seriesID primitive.ObjectID) (string, error) {
+	slug := utils.GenerateSlug(name)
+	if slug == "" {
+		return "", fmt.Errorf("%w: name must contain at least one letter or number", ErrInvalidSeries)
+	}
+
+	uniqueSlug := slug
+	for counter := 1; ; counter++ {
+		existing, err := s.seriesRepository.FindSeriesBySlug(uniqueSlug)
+		if err != nil {
+			return "", fmt.Errorf("could not check slug uniqueness: %w", err)
+		}
+		if existing == nil || existing.ID == seriesID {
+			return uniqueSlug, nil
+		}
+		uniqueSlug = fmt.Sprintf("%s-%d", slug, counter)
+	}
+}
+
+// GetStandings retorna la clasificación del campeonato, opcionalmente de una sola categoría
+func (s *seriesService) GetStandings(id string, category *string) (*domain.SeriesStandings, error) {
+	series, err := s.GetSeries(id)
+	if err != nil {
+		return nil, err
+	}
+
+	standings, err := s.seriesRepository.FindStandings(series.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get standings: %w", err)
+	}
+	if standings == nil {
+		if standings, err = s.recalculate(series); err != nil {
+			return nil, err
+		}
+	}
+
+	if category != nil {
+		filtered := []domain.CategoryStanding{}
+		for _, standing := range standings.Categories {
+			if strings.EqualFold(standing.Category, *category) {
+				filtered = append(filtered, standing)
+			}
+		}
+		standings.Categories = filtered
+	}
+	return standings, nil
+}
+
+// RecalculateEventSeries recalcula los campeonatos que tienen al evento como fecha. Se
+// llama después de publicar resultados del evento.
+func (s *seriesService) RecalculateEventSeries(eventID primitive.ObjectID) error {
+	seriesList, err := s.seriesRepository.FindSeriesByEvent(eventID)
+	if err != nil {
+		return fmt.Errorf("could not find event series: %w", err)
+	}
+	for _, series := range seriesList {
+		if _, err := s.recalculate(series); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveEvent quita un evento eliminado de los campeonatos y recalcula sus clasificaciones
+func (s *seriesService) RemoveEvent(eventID primitive.ObjectID) error {
+	seriesList, err := s.seriesRepository.FindSeriesByEvent(eventID)
+	if err != nil {
+		return fmt.Errorf("could not find event series: %w", err)
+	}
+	for _, series := range seriesList {
+		eventIDs := make([]primitive.ObjectID, 0, len(series.EventIDs))
+		for _, id := range series.EventIDs {
+			if id != eventID {
+				eventIDs = append(eventIDs, id)
+			}
+		}
+		series.EventIDs = eventIDs
+
+		if err := s.seriesRepository.UpdateSeries(series); err != nil {
+			return fmt.Errorf("could not update series: %w", err)
+		}
+		if _, err := s.recalculate(series); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recalculate calcula y guarda la clasificación a partir de los resultados publicados de
+// cada fecha. Los corredores se reconocen entre fechas por el registro de atletas.
+func (s *seriesService) recalculate(series *domain.Series) (*domain.SeriesStandings, error) {
+	standings := &domain.SeriesStandings{
+		SeriesID: series.ID,
+		Rounds:   []domain.SeriesRound{},
+	}
+
+	var rounds []heldRound
+	for i, eventID := range series.EventIDs {
+		event, err := s.eventRepository.FindByID(eventID)
+		if err != nil {
+			return nil, fmt.Errorf("could not get event: %w", err)
+		}
+		if event == nil {
+			continue
+		}
+
+		round := domain.SeriesRound{
+			Number:    i + 1,
+			EventID:   event.ID,
+			EventName: event.Name,
+			EventSlug: event.Slug,
+			EventDate: event.Date,
+		}
+
+		results, err := s.roundResults(event.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) > 0 {
+			round.Held = true
+			rounds = append(rounds, heldRound{number: round.Number, results: results})
+		}
+		standings.Rounds = append(standings.Rounds, round)
+	}
+
+	standings.Categories = computeSeriesStandings(series.Scoring, rounds)
+	standings.UpdatedAt = time.Now()
+	if err := s.seriesRepository.SaveStandings(standings); err != nil {
+		return nil, fmt.Errorf("could not save standings: %w", err)
+	}
+	return standings, nil
+}
+
+// roundResults obtiene los resultados publicados de una fecha junto con el atleta de cada uno.
+// Quienes tienen un estado (DNF, DNS, DSQ, OTL) no suman puntos aunque el archivo les asigne
+// una posición.
+func (s *seriesService) roundResults(eventID primitive.ObjectID) ([]roundResult, error) {
+	data, err := s.eventRepository.FindAllData(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load event data: %w", err)
+	}
+
+	athleteByKey := make(map[string]primitive.ObjectID)
+	if s.athleteRepository != nil {
+		links, err := s.athleteRepository.FindEventAthleteResults(eventID)
+		if err != nil {
+			return nil, fmt.Errorf("could not load athlete results: %w", err)
+		}
+		for _, link := range links {
+			athleteByKey[link.ResultKey] = link.AthleteID
+		}
+	}
+
+	eventData := make([]domain.EventData, len(data))
+	for i, item := range data {
+		eventData[i] = *item
+	}
+	keys := athleteResultKeys(eventData)
+
+	results := make([]roundResult, 0, len(eventData))
+	for i, item := range eventData {
+		if item.Result.Status != "" {
+			continue
+		}
+		result := roundResult{
+			name:             item.Result.Name,
+			category:         strings.TrimSpace(item.Result.Category),
+			categoryPosition: item.Result.CategoryPosition,
+		}
+		if athleteID, ok := athleteByKey[keys[i]]; ok {
+			result.athleteID = athleteID
+			result.key = "athlete:" + athleteID.Hex()
+		} else if name := utils.NormalizeName(item.Result.Name); name != "" {
+			result.key = "name:" + name
+		} else {
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
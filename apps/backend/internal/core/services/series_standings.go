@@ -0,0 +1,196 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/utils"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultSeriesPointsTable se usa cuando el campeonato no define su tabla de puntos
+var defaultSeriesPointsTable = []int{25, 20, 16, 13, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+
+// roundResult es el resultado de un corredor en una fecha del campeonato
+type roundResult struct {
+	// key identifica al corredor entre fechas: su atleta o, si no está vinculado, su
+	// nombre normalizado
+	key       string
+	athleteID primitive.ObjectID
+	name      string
+	// category es el texto de la categoría en el archivo. Las categorías se agrupan con
+	// utils.NormalizeKey, por lo que "Damas 21K" y "DAMAS 21k" son la misma.
+	category         string
+	categoryPosition int
+}
+
+// heldRound es una fecha con resultados publicados
+type heldRound struct {
+	number  int
+	results []roundResult
+}
+
+// roundPoints retorna los puntos de una posición en la categoría. Sin posición, p. ej.
+// un corredor que no terminó, no suma puntos.
+func roundPoints(scoring domain.SeriesScoring, categoryPosition int) int {
+	if categoryPosition <= 0 {
+		return 0
+	}
+	table := scoring.PointsTable
+	if len(table) == 0 {
+		table = defaultSeriesPointsTable
+	}
+	if categoryPosition <= len(table) {
+		return table[categoryPosition-1]
+	}
+	return scoring.ParticipationPoints
+}
+
+// countedRounds retorna cuántas fechas suman al total de cada corredor
+func countedRounds(scoring domain.SeriesScoring, heldCount int) int {
+	counted := heldCount
+	if scoring.BestOf > 0 && scoring.BestOf < counted {
+		counted = scoring.BestOf
+	}
+	if scoring.DropWorst > 0 && heldCount-scoring.DropWorst < counted {
+		counted = heldCount - scoring.DropWorst
+	}
+	if counted < 0 {
+		counted = 0
+	}
+	return counted
+}
+
+type standingAccumulator struct {
+	entry  domain.StandingEntry
+	scores []int
+}
+
+// computeSeriesStandings calcula la clasificación de cada categoría. Las fechas en que un
+// corredor no participó cuentan como 0 puntos, por lo que son las primeras en descartarse.
+// Los empates en puntos se resuelven por la mayor cantidad de mejores puntajes en una fecha,
+// y si persisten los corredores comparten la posición. Cada categoría se muestra con el
+// nombre que tuvo en la fecha más reciente.
+func computeSeriesStandings(scoring domain.SeriesScoring, rounds []heldRound) []domain.CategoryStanding {
+	counted := countedRounds(scoring, len(rounds))
+
+	byCategory := make(map[string]map[string]*standingAccumulator)
+	categoryNames := make(map[string]string)
+	var categories []string
+	for _, round := range rounds {
+		for _, result := range round.results {
+			categoryKey := utils.NormalizeKey(result.category)
+			entries, ok := byCategory[categoryKey]
+			if !ok {
+				entries = make(map[string]*standingAccumulator)
+				byCategory[categoryKey] = entries
+				categories = append(categories, categoryKey)
+			}
+			categoryNames[categoryKey] = result.category
+
+			acc, ok := entries[result.key]
+			if !ok {
+				acc = &standingAccumulator{entry: domain.StandingEntry{AthleteID: result.athleteID}}
+				entries[result.key] = acc
+			}
+
+			score := domain.RoundScore{
+				Round:            round.number,
+				CategoryPosition: result.categoryPosition,
+				Points:           roundPoints(scoring, result.categoryPosition),
+			}
+			if n := len(acc.entry.Rounds); n > 0 && acc.entry.Rounds[n-1].Round == round.number {
+				// El mismo corredor dos veces en una fecha: se conserva el mejor resultado
+				if score.Points <= acc.entry.Rounds[n-1].Points {
+					continue
+				}
+				acc.entry.Rounds[n-1] = score
+			} else {
+				acc.entry.Rounds = append(acc.entry.Rounds, score)
+			}
+			// El nombre de la fecha más reciente
+			acc.entry.Name = result.name
+		}
+	}
+
+	standings := make([]domain.CategoryStanding, 0, len(categories))
+	for _, category := range categories {
+		accs := make([]*standingAccumulator, 0, len(byCategory[category]))
+		for _, acc := range byCategory[category] {
+			applyCountedRounds(acc, counted)
+			accs = append(accs, acc)
+		}
+
+		sort.Slice(accs, func(i, j int) bool {
+			if c := compareStanding(accs[i], accs[j]); c != 0 {
+				return c < 0
+			}
+			return strings.ToUpper(accs[i].entry.Name) < strings.ToUpper(accs[j].entry.Name)
+		})
+
+		entries := make([]domain.StandingEntry, len(accs))
+		for i, acc := range accs {
+			acc.entry.Position = i + 1
+			if i > 0 && compareStanding(accs[i-1], acc) == 0 {
+				acc.entry.Position = entries[i-1].Position
+			}
+			entries[i] = acc.entry
+		}
+		standings = append(standings, domain.CategoryStanding{Category: categoryNames[category], Entries: entries})
+	}
+	return standings
+}
+
+// applyCountedRounds marca las fechas que suman al total y calcula los puntos del corredor
+func applyCountedRounds(acc *standingAccumulator, counted int) {
+	order := make([]int, len(acc.entry.Rounds))
+	for i := range order {
+		order[i] = i
+	}
+	// Ante igual puntaje se descarta primero la fecha más antigua
+	sort.Slice(order, func(a, b int) bool {
+		ra, rb := acc.entry.Rounds[order[a]], acc.entry.Rounds[order[b]]
+		if ra.Points != rb.Points {
+			return ra.Points > rb.Points
+		}
+		return ra.Round > rb.Round
+	})
+
+	acc.entry.Points = 0
+	acc.scores = acc.scores[:0]
+	for rank, i := range order {
+		score := &acc.entry.Rounds[i]
+		score.Counted = rank < counted
+		if score.Counted {
+			acc.entry.Points += score.Points
+		}
+		acc.scores = append(acc.scores, score.Points)
+	}
+}
+
+// compareStanding ordena por puntos y luego por los mejores puntajes de fecha
+func compareStanding(a, b *standingAccumulator) int {
+	if a.entry.Points != b.entry.Points {
+		if a.entry.Points > b.entry.Points {
+			return -1
+		}
+		return 1
+	}
+	for i := 0; i < len(a.scores) || i < len(b.scores); i++ {
+		var sa, sb int
+		if i < len(a.scores) {
+			sa = a.scores[i]
+		}
+		if i < len(b.scores) {
+			sb = b.scores[i]
+		}
+		if sa != sb {
+			if sa > sb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
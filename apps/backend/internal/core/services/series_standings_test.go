@@ -0,0 +1,142 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"testing"
+)
+
+func TestRoundPoints(t *testing.T) {
+	scoring := domain.SeriesScoring{PointsTable: []int{10, 6, 4}, ParticipationPoints: 1}
+	tests := map[int]int{1: 10, 2: 6, 3: 4, 4: 1, 40: 1, 0: 0}
+	for position, expected := range tests {
+		if got := roundPoints(scoring, position); got != expected {
+			t.Errorf("roundPoints(%d) = %d, expected %d", position, got, expected)
+		}
+	}
+
+	if got := roundPoints(domain.SeriesScoring{}, 1); got != defaultSeriesPointsTable[0] {
+		t.Errorf("expected the default points table, got %d", got)
+	}
+}
+
+func TestCountedRounds(t *testing.T) {
+	tests := []struct {
+		scoring  domain.SeriesScoring
+		held     int
+		expected int
+	}{
+		{domain.SeriesScoring{}, 5, 5},
+		{domain.SeriesScoring{BestOf: 3}, 5, 3},
+		{domain.SeriesScoring{BestOf: 8}, 5, 5},
+		{domain.SeriesScoring{DropWorst: 1}, 5, 4},
+		{domain.SeriesScoring{BestOf: 4, DropWorst: 2}, 5, 3},
+		{domain.SeriesScoring{DropWorst: 2}, 1, 0},
+	}
+	for _, tt := range tests {
+		if got := countedRounds(tt.scoring, tt.held); got != tt.expected {
+			t.Errorf("countedRounds(%+v, %d) = %d, expected %d", tt.scoring, tt.held, got, tt.expected)
+		}
+	}
+}
+
+func TestComputeSeriesStandings(t *testing.T) {
+	scoring := domain.SeriesScoring{PointsTable: []int{10, 6, 4}, DropWorst: 1}
+	rounds := []heldRound{
+		{number: 1, results: []roundResult{
+			{key: "ana", name: "Ana", category: "Elite", categoryPosition: 1},
+			{key: "bea", name: "Bea", category: "Elite", categoryPosition: 2},
+			{key: "luis", name: "Luis", category: "Master", categoryPosition: 1},
+		}},
+		{number: 2, results: []roundResult{
+			{key: "bea", name: "Bea", category: "Elite", categoryPosition: 1},
+			{key: "ana", name: "Ana", category: "Elite", categoryPosition: 3},
+		}},
+		{number: 3, results: []roundResult{
+			{key: "bea", name: "Beatriz", category: "Elite", categoryPosition: 1},
+			{key: "ana", name: "Ana", category: "Elite", categoryPosition: 0},
+		}},
+	}
+
+	standings := computeSeriesStandings(scoring, rounds)
+	if len(standings) != 2 || standings[0].Category != "Elite" || standings[1].Category != "Master" {
+		t.Fatalf("categories = %+v", standings)
+	}
+
+	elite := standings[0].Entries
+	// Bea: 6 + 10 + 10, se descarta el 6. Ana: 10 + 4 + 0, se descarta el 0.
+	if elite[0].Name != "Beatriz" || elite[0].Points != 20 || elite[0].Position != 1 {
+		t.Errorf("first = %+v, expected Beatriz with 20 points", elite[0])
+	}
+	if elite[1].Name != "Ana" || elite[1].Points != 14 || elite[1].Position != 2 {
+		t.Errorf("second = %+v, expected Ana with 14 points", elite[1])
+	}
+	if elite[0].Rounds[0].Counted || !elite[0].Rounds[1].Counted || !elite[0].Rounds[2].Counted {
+		t.Errorf("expected the worst round of Bea to be dropped, rounds = %+v", elite[0].Rounds)
+	}
+
+	// Luis solo corrió una fecha: las dos que faltó valen 0 y se descarta una de ellas
+	master := standings[1].Entries
+	if len(master) != 1 || master[0].Points != 10 || len(master[0].Rounds) != 1 {
+		t.Errorf("master = %+v", master)
+	}
+}
+
+func TestComputeSeriesStandingsTies(t *testing.T) {
+	scoring := domain.SeriesScoring{PointsTable: []int{10, 6, 4, 2}}
+	rounds := []heldRound{
+		{number: 1, results: []roundResult{
+			{key: "a", name: "Andrés", category: "Elite", categoryPosition: 1},
+			{key: "b", name: "Bruno", category: "Elite", categoryPosition: 3},
+			{key: "c", name: "Carlos", category: "Elite", categoryPosition: 2},
+			{key: "d", name: "Diego", category: "Elite", categoryPosition: 4},
+		}},
+		{number: 2, results: []roundResult{
+			{key: "a", name: "Andrés", category: "Elite", categoryPosition: 4},
+			{key: "b", name: "Bruno", category: "Elite", categoryPosition: 2},
+			{key: "c", name: "Carlos", category: "Elite", categoryPosition: 3},
+			{key: "d", name: "Diego", category: "Elite", categoryPosition: 1},
+		}},
+	}
+
+	entries := computeSeriesStandings(scoring, rounds)[0].Entries
+	// Andrés y Diego suman 12 con fechas de 10 y 2; Bruno y Carlos suman 10 con fechas de
+	// 6 y 4. Ambos pares empatan también en el desempate y comparten la posición.
+	if entries[0].Name != "Andrés" || entries[1].Name != "Diego" || entries[0].Position != 1 || entries[1].Position != 1 {
+		t.Errorf("expected Andrés and Diego tied first, got %+v and %+v", entries[0], entries[1])
+	}
+	if entries[2].Position != 3 || entries[3].Position != 3 {
+		t.Errorf("expected Bruno and Carlos tied third, got %+v and %+v", entries[2], entries[3])
+	}
+}
+
+func TestApplyCountedRoundsDropsOlderRoundOnTie(t *testing.T) {
+	acc := &standingAccumulator{entry: domain.StandingEntry{Rounds: []domain.RoundScore{
+		{Round: 1, Points: 6},
+		{Round: 2, Points: 10},
+		{Round: 3, Points: 6},
+	}}}
+
+	applyCountedRounds(acc, 2)
+	rounds := acc.entry.Rounds
+	if rounds[0].Counted || !rounds[1].Counted || !rounds[2].Counted {
+		t.Errorf("expected the older of the tied rounds to be dropped, rounds = %+v", rounds)
+	}
+	if acc.entry.Points != 16 {
+		t.Errorf("points = %d, expected 16", acc.entry.Points)
+	}
+}
+
+func TestComputeSeriesStandingsNormalizesCategories(t *testing.T) {
+	rounds := []heldRound{
+		{number: 1, results: []roundResult{{key: "ana", name: "Ana", category: "Damas 21K", categoryPosition: 1}}},
+		{number: 2, results: []roundResult{{key: "ana", name: "Ana", category: "DAMAS 21k", categoryPosition: 2}}},
+	}
+
+	standings := computeSeriesStandings(domain.SeriesScoring{PointsTable: []int{10, 6}}, rounds)
+	if len(standings) != 1 {
+		t.Fatalf("expected a single category, got %+v", standings)
+	}
+	if standings[0].Category != "DAMAS 21k" || standings[0].Entries[0].Points != 16 {
+		t.Errorf("standing = %+v, expected the latest category name with 16 points", standings[0])
+	}
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"backend/internal/racecheck"
+	"backend/internal/utils"
+	"fmt"
+	"strings"
+)
+
+// startListEntries convierte las filas del archivo de inscritos en inscritos. Las filas sin
+// dorsal o con un dorsal repetido se informan en el reporte y no se guardan.
+func startListEntries(parsed *racecheck.File) ([]*domain.StartListEntry, domain.IngestReport) {
+	report := domain.IngestReport{Encoding: parsed.Encoding, Issues: []domain.IngestIssue{}}
+	for _, skipped := range parsed.Skipped {
+		report.Add(domain.IngestIssue{
+			Line:     skipped.Line,
+			Code:     domain.IssueSkippedRow,
+			Severity: domain.IssueSeverityError,
+			Reason:   skipped.Reason,
+			Raw:      skipped.Raw,
+		})
+	}
+
+	entries := []*domain.StartListEntry{}
+	bibLines := make(map[string]int)
+	chipLines := make(map[string]int)
+
+	for _, race := range parsed.Races {
+		for _, row := range race.Rows {
+			result := mapResult(race.RowMap(row))
+			issue := func(code, severity, reason string) {
+				report.Add(domain.IngestIssue{
+					Line:       row.Line,
+					RaceNumber: race.Number,
+					Code:       code,
+					Severity:   severity,
+					Reason:     reason,
+					Raw:        strings.Join(row.Values, "|"),
+				})
+			}
+
+			if result.Name == "" {
+				issue(domain.IssueBlankName, domain.IssueSeverityError, "participant name is blank")
+			}
+			if result.Chip != "" {
+				if line, ok := chipLines[result.Chip]; ok {
+					issue(domain.IssueDuplicateChip, domain.IssueSeverityWarning, fmt.Sprintf("chip %s already used on line %d", result.Chip, line))
+				} else {
+					chipLines[result.Chip] = row.Line
+				}
+			}
+
+			bib := normalizeBib(result.Bib)
+			if bib == "" {
+				issue(domain.IssueMissingBib, domain.IssueSeverityError, "registered participant has no bib")
+				continue
+			}
+			if line, ok := bibLines[bib]; ok {
+				issue(domain.IssueDuplicateBib, domain.IssueSeverityError, fmt.Sprintf("bib %s already used on line %d", bib, line))
+				continue
+			}
+			bibLines[bib] = row.Line
+
+			modality := result.Modality
+			if modality == "" {
+				modality = race.Name
+			}
+			entries = append(entries, &domain.StartListEntry{
+				Bib:      bib,
+				Chip:     result.Chip,
+				Name:     result.Name,
+				Sex:      result.Sex,
+				Category: result.Category,
+				Modality: modality,
+				Club:     result.Club,
+				Line:     row.Line,
+			})
+		}
+	}
+	return entries, report
+}
+
+// startListRow es un participante de los resultados que se compara con los inscritos
+type startListRow struct {
+	result     *domain.Result
+	raceNumber int
+	raceName   string
+	line       int
+	raw        string
+}
+
+// startListMismatch es un participante cuya categoría no coincide con la de su inscripción
+type startListMismatch struct {
+	row   startListRow
+	entry *domain.StartListEntry
+}
+
+// startListMatch es el resultado de comparar los participantes con los inscritos
+type startListMatch struct {
+	matched      int
+	notStarted   []*domain.StartListEntry
+	unregistered []startListRow
+	mismatches   []startListMismatch
+}
+
+// matchStartList busca por dorsal a cada participante entre los inscritos. No partieron los
+// inscritos que no aparecen en los resultados o que tienen estado DNS. Los participantes sin
+// dorsal no se pueden comparar y se ignoran.
+func matchStartList(entries []*domain.StartListEntry, rows []startListRow) startListMatch {
+	var match startListMatch
+	byBib := make(map[string]*domain.StartListEntry, len(entries))
+	for _, entry := range entries {
+		byBib[normalizeBib(entry.Bib)] = entry
+	}
+
+	seen := make(map[string]bool, len(rows))
+	started := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		bib := normalizeBib(row.result.Bib)
+		if bib == "" {
+			continue
+		}
+		entry, ok := byBib[bib]
+		if !ok {
+			match.unregistered = append(match.unregistered, row)
+			continue
+		}
+		// El dorsal repetido ya se informa en la validación del archivo
+		if seen[bib] {
+			continue
+		}
+		seen[bib] = true
+		match.matched++
+		if row.result.Status != domain.ResultStatusDNS {
+			started[bib] = true
+		}
+		if entry.Category != "" && row.result.Category != "" &&
+			utils.NormalizeKey(entry.Category) != utils.NormalizeKey(row.result.Category) {
+			match.mismatches = append(match.mismatches, startListMismatch{row: row, entry: entry})
+		}
+	}
+
+	for _, entry := range entries {
+		if !started[normalizeBib(entry.Bib)] {
+			match.notStarted = append(match.notStarted, entry)
+		}
+	}
+	return match
+}
+
+// checkStartList compara las filas del archivo de resultados con los inscritos del evento y
+// agrega al reporte las advertencias y el resumen de la comparación
+func checkStartList(report *domain.IngestReport, parsed *racecheck.File, entries []*domain.StartListEntry) {
+	var rows []startListRow
+	for _, race := range parsed.Races {
+		for _, row := range race.Rows {
+			result := mapResult(race.RowMap(row))
+			rows = append(rows, startListRow{
+				result:     &result,
+				raceNumber: race.Number,
+				raceName:   race.Name,
+				line:       row.Line,
+				raw:        strings.Join(row.Values, "|"),
+			})
+		}
+	}
+	match := matchStartList(entries, rows)
+
+	warn := func(row startListRow, code, reason string) {
+		report.Add(domain.IngestIssue{
+			Line:       row.line,
+			RaceNumber: row.raceNumber,
+			Code:       code,
+			Severity:   domain.IssueSeverityWarning,
+			Reason:     reason,
+			Raw:        row.raw,
+		})
+	}
+	for _, row := range match.unregistered {
+		warn(row, domain.IssueUnregisteredBib, fmt.Sprintf("bib %s is not in the start list", row.result.Bib))
+	}
+	for _, mismatch := range match.mismatches {
+		warn(mismatch.row, domain.IssueCategoryMismatch, fmt.Sprintf("category %q does not match registered category %q", mismatch.row.result.Category, mismatch.entry.Category))
+	}
+
+	summary := &domain.StartListSummary{
+		Registered:         len(entries),
+		Matched:            match.matched,
+		NotStartedBibs:     make([]string, 0, len(match.notStarted)),
+		UnregisteredCount:  len(match.unregistered),
+		CategoryMismatches: len(match.mismatches),
+	}
+	for _, entry := range match.notStarted {
+		summary.NotStartedBibs = append(summary.NotStartedBibs, entry.Bib)
+	}
+	report.StartList = summary
+}
+
+// reconcileStartList compara los participantes publicados con los inscritos del evento
+func reconcileStartList(entries []*domain.StartListEntry, data []*domain.EventData) *ports.StartListReconciliation {
+	rows := make([]startListRow, len(data))
+	for i, item := range data {
+		rows[i] = startListRow{result: &item.Result, raceNumber: item.RaceNumber, raceName: item.RaceName}
+	}
+	match := matchStartList(entries, rows)
+
+	reconciliation := &ports.StartListReconciliation{
+		Registered:         len(entries),
+		Matched:            match.matched,
+		NotStarted:         []*domain.StartListEntry{},
+		Unregistered:       []ports.StartListParticipant{},
+		CategoryMismatches: []ports.StartListCategoryMismatch{},
+	}
+	reconciliation.NotStarted = append(reconciliation.NotStarted, match.notStarted...)
+	for _, row := range match.unregistered {
+		reconciliation.Unregistered = append(reconciliation.Unregistered, startListParticipant(row))
+	}
+	for _, mismatch := range match.mismatches {
+		reconciliation.CategoryMismatches = append(reconciliation.CategoryMismatches, ports.StartListCategoryMismatch{
+			StartListParticipant: startListParticipant(mismatch.row),
+			RegisteredCategory:   mismatch.entry.Category,
+		})
+	}
+	return reconciliation
+}
+
+func startListParticipant(row startListRow) ports.StartListParticipant {
+	return ports.StartListParticipant{
+		Bib:        row.result.Bib,
+		Name:       row.result.Name,
+		Category:   row.result.Category,
+		RaceNumber: row.raceNumber,
+		RaceName:   row.raceName,
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/importer"
+	"backend/internal/racecheck"
+	"strings"
+	"testing"
+)
+
+func TestStartListEntries(t *testing.T) {
+	content := strings.Join([]string{
+		"Dorsal;Nombre;Chip;Categoria;Distancia",
+		" a1 ;Ana;QT001;Damas;10K",
+		";Sin dorsal;QT002;Damas;10K",
+		"A1;Repetida;QT003;Damas;10K",
+		"7;Luis;QT004;Varones;5K",
+	}, "\n")
+
+	parsed, err := importer.CSV{}.Import(strings.NewReader(content), importer.Options{})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	entries, report := startListEntries(parsed)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Bib != "A1" || entries[0].Name != "Ana" || entries[0].Category != "Damas" || entries[0].Modality != "10K" {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Bib != "7" || entries[1].Chip != "QT004" {
+		t.Errorf("unexpected second entry: %+v", entries[1])
+	}
+
+	codes := make(map[string]int)
+	for _, issue := range report.Issues {
+		codes[issue.Code]++
+	}
+	if codes[domain.IssueMissingBib] != 1 || codes[domain.IssueDuplicateBib] != 1 || report.ErrorCount != 2 {
+		t.Errorf("expected the missing and duplicate bib errors, got %+v", report.Issues)
+	}
+}
+
+func TestCheckStartList(t *testing.T) {
+	content := strings.Join([]string{
+		"1|CORRIDA DE PRUEBA",
+		";1|10K",
+		";SEXO|NOMBRE|CHIP|DORSAL|MODALIDAD|CATEGORIA|TIEMPO|POSICION|POS.CAT.|RITMO",
+		"F|Ana|QT001|1|10K|Damas|00:40:00|1|1|00:00 min/Km",
+		"M|Luis|QT002|2|10K|Juveniles|00:41:00|2|1|00:00 min/Km",
+		"M|Pedro|QT003|9|10K|Varones|00:42:00|3|2|00:00 min/Km",
+		"M|Juan|QT004|4|10K|Varones|DNS|-|-|00:00 min/Km",
+	}, "\r\n")
+
+	parsed, err := racecheck.Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	entries := []*domain.StartListEntry{
+		{Bib: "1", Name: "Ana", Category: "DAMAS"},
+		{Bib: "2", Name: "Luis", Category: "Varones"},
+		{Bib: "3", Name: "Marta", Category: "Damas"},
+		{Bib: "4", Name: "Juan", Category: "Varones"},
+	}
+
+	report := domain.IngestReport{}
+	checkStartList(&report, parsed, entries)
+
+	summary := report.StartList
+	if summary == nil {
+		t.Fatal("expected a start list summary")
+	}
+	if summary.Registered != 4 || summary.Matched != 3 || summary.UnregisteredCount != 1 || summary.CategoryMismatches != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	// Marta no aparece en el archivo y Juan no partió
+	if strings.Join(summary.NotStartedBibs, ",") != "3,4" {
+		t.Errorf("expected bibs 3 and 4 not to start, got %v", summary.NotStartedBibs)
+	}
+
+	codes := make(map[string]int)
+	for _, issue := range report.Issues {
+		if issue.Severity != domain.IssueSeverityWarning {
+			t.Errorf("expected only warnings, got %+v", issue)
+		}
+		codes[issue.Code]++
+	}
+	if codes[domain.IssueUnregisteredBib] != 1 || codes[domain.IssueCategoryMismatch] != 1 {
+		t.Errorf("unexpected issues: %+v", report.Issues)
+	}
+}
+
+func TestReconcileStartList(t *testing.T) {
+	entries := []*domain.StartListEntry{
+		{Bib: "1", Name: "Ana", Category: "Damas"},
+		{Bib: "2", Name: "Luis", Category: "Varones"},
+	}
+	data := []*domain.EventData{
+		{RaceNumber: 1, RaceName: "10K", Result: domain.Result{Bib: "1", Name: "Ana", Category: "Damas"}},
+		{RaceNumber: 1, RaceName: "10K", Result: domain.Result{Bib: "15", Name: "Sin inscripción", Category: "Damas"}},
+	}
+
+	reconciliation := reconcileStartList(entries, data)
+	if reconciliation.Matched != 1 || len(reconciliation.NotStarted) != 1 || reconciliation.NotStarted[0].Bib != "2" {
+		t.Errorf("expected Luis not to start, got %+v", reconciliation)
+	}
+	if len(reconciliation.Unregistered) != 1 || reconciliation.Unregistered[0].Bib != "15" || reconciliation.Unregistered[0].RaceName != "10K" {
+		t.Errorf("expected bib 15 to be unregistered, got %+v", reconciliation.Unregistered)
+	}
+	if len(reconciliation.CategoryMismatches) != 0 {
+		t.Errorf("expected no category mismatches, got %+v", reconciliation.CategoryMismatches)
+	}
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+	"backend/internal/utils"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultTeamScorers es la cantidad de corredores que puntúan cuando el evento no la configura
+const defaultTeamScorers = 3
+
+// unattachedTeams son los valores de club, normalizados con utils.NormalizeKey, con los que
+// los archivos indican que el corredor no pertenece a un equipo
+var unattachedTeams = map[string]bool{
+	"":              true,
+	"INDEPENDIENTE": true,
+	"SINCLUB":       true,
+	"SINEQUIPO":     true,
+	"PARTICULAR":    true,
+	"LIBRE":         true,
+	"NA":            true,
+}
+
+// effectiveTeamScoring completa la configuración del evento con los valores por defecto
+func effectiveTeamScoring(scoring *domain.TeamScoring) domain.TeamScoring {
+	effective := domain.TeamScoring{}
+	if scoring != nil {
+		effective = *scoring
+	}
+	if effective.Method == "" {
+		effective.Method = domain.TeamScoringPositions
+	}
+	if effective.Scorers <= 0 {
+		effective.Scorers = defaultTeamScorers
+	}
+	return effective
+}
+
+// teamGroup son los participantes que compiten entre sí por equipos: una carrera o un
+// sexo dentro de la carrera
+type teamGroup struct {
+	standing ports.TeamStanding
+	results  []*domain.EventData
+}
+
+type teamAccumulator struct {
+	result ports.TeamResult
+	places []int
+}
+
+// computeTeamStandings calcula la clasificación por equipos de cada carrera. El lugar de
+// cada corredor es su orden entre todos los que terminaron, tengan o no equipo, y el
+// puntaje de un equipo es la suma de los lugares o de los tiempos de sus Scorers mejores
+// corredores; gana el menor. Los empates se resuelven por el mejor lugar del último
+// corredor que puntúa, y si persisten los equipos comparten la posición.
+func computeTeamStandings(races []domain.Race, data []*domain.EventData, scoring domain.TeamScoring) []ports.TeamStanding {
+	groups := make(map[string]*teamGroup)
+	var order []string
+	raceNames := make(map[int]string, len(races))
+	for _, race := range races {
+		raceNames[race.Number] = race.Name
+	}
+
+	for _, item := range data {
+		sex := ""
+		if scoring.BySex {
+			sex = normalizeSex(item.Result.Sex)
+		}
+		key := strconv.Itoa(item.RaceNumber) + "|" + sex
+		group, ok := groups[key]
+		if !ok {
+			name := raceNames[item.RaceNumber]
+			if name == "" {
+				name = item.RaceName
+			}
+			group = &teamGroup{standing: ports.TeamStanding{RaceNumber: item.RaceNumber, RaceName: name, Sex: sex}}
+			groups[key] = group
+			order = append(order, key)
+		}
+		group.results = append(group.results, item)
+	}
+
+	// Las carreras en el orden del evento y, dentro de cada una, los sexos en orden alfabético
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := groups[order[i]].standing, groups[order[j]].standing
+		if a.RaceNumber != b.RaceNumber {
+			return a.RaceNumber < b.RaceNumber
+		}
+		return a.Sex < b.Sex
+	})
+
+	standings := make([]ports.TeamStanding, 0, len(order))
+	for _, key := range order {
+		group := groups[key]
+		group.standing.Teams = scoreTeams(group.results, scoring)
+		if len(group.standing.Teams) > 0 {
+			standings = append(standings, group.standing)
+		}
+	}
+	return standings
+}
+
+// scoreTeams calcula la clasificación por equipos de un grupo de participantes
+func scoreTeams(results []*domain.EventData, scoring domain.TeamScoring) []ports.TeamResult {
+	byTime := scoring.Method == domain.TeamScoringTimes
+
+	finishers := make([]*domain.Result, 0, len(results))
+	for _, item := range results {
+		result := &item.Result
+		if result.Status == "" && (result.FinishTimeMs > 0 || (!byTime && result.Position > 0)) {
+			finishers = append(finishers, result)
+		}
+	}
+	// Al puntuar por tiempos los lugares también se asignan por tiempo, aunque el archivo
+	// traiga otro orden
+	before := finisherBefore
+	if byTime {
+		before = fasterFinisher
+	}
+	sort.SliceStable(finishers, func(i, j int) bool {
+		return before(finishers[i], finishers[j])
+	})
+
+	teams := make(map[string]*teamAccumulator)
+	var teamOrder []string
+	place := 0
+	for i, result := range finishers {
+		// Los corredores empatados en posición y tiempo comparten el lugar
+		if i == 0 || before(finishers[i-1], result) {
+			place = i + 1
+		}
+		key := utils.NormalizeKey(result.Club)
+		if unattachedTeams[key] {
+			continue
+		}
+		acc, ok := teams[key]
+		if !ok {
+			acc = &teamAccumulator{result: ports.TeamResult{Team: strings.TrimSpace(result.Club)}}
+			teams[key] = acc
+			teamOrder = append(teamOrder, key)
+		}
+
+		member := ports.TeamMember{
+			Bib:            result.Bib,
+			Name:           result.Name,
+			Place:          place,
+			FinishTimeText: result.FinishTimeText,
+			FinishTimeMs:   result.FinishTimeMs,
+		}
+		if len(acc.places) < scoring.Scorers {
+			member.Scoring = true
+			acc.places = append(acc.places, member.Place)
+			if byTime {
+				acc.result.Score += result.FinishTimeMs
+			} else {
+				acc.result.Score += int64(member.Place)
+			}
+		}
+		acc.result.Members = append(acc.result.Members, member)
+	}
+
+	var complete, incomplete []*teamAccumulator
+	for _, key := range teamOrder {
+		acc := teams[key]
+		acc.result.Complete = len(acc.places) == scoring.Scorers
+		if byTime {
+			acc.result.ScoreText = formatTeamTime(acc.result.Score)
+		} else {
+			acc.result.ScoreText = strconv.FormatInt(acc.result.Score, 10)
+		}
+		if acc.result.Complete {
+			complete = append(complete, acc)
+		} else if scoring.IncludeIncomplete {
+			incomplete = append(incomplete, acc)
+		}
+	}
+
+	sort.SliceStable(complete, func(i, j int) bool {
+		return compareTeams(complete[i], complete[j]) < 0
+	})
+	// Los incompletos se listan al final, primero los que tienen más corredores
+	sort.SliceStable(incomplete, func(i, j int) bool {
+		if len(incomplete[i].places) != len(incomplete[j].places) {
+			return len(incomplete[i].places) > len(incomplete[j].places)
+		}
+		return incomplete[i].result.Score < incomplete[j].result.Score
+	})
+
+	standings := make([]ports.TeamResult, 0, len(complete)+len(incomplete))
+	for i, acc := range complete {
+		acc.result.Position = i + 1
+		if i > 0 && compareTeams(complete[i-1], acc) == 0 {
+			acc.result.Position = standings[i-1].Position
+		}
+		standings = append(standings, acc.result)
+	}
+	for _, acc := range incomplete {
+		standings = append(standings, acc.result)
+	}
+	return standings
+}
+
+// finisherBefore ordena a los que terminaron por la posición del archivo y luego por
+// tiempo. Los que no tienen posición van después de los que sí la tienen.
+func finisherBefore(a, b *domain.Result) bool {
+	if (a.Position > 0) != (b.Position > 0) {
+		return a.Position > 0
+	}
+	if a.Position != b.Position {
+		return a.Position < b.Position
+	}
+	return a.FinishTimeMs < b.FinishTimeMs
+}
+
+// fasterFinisher ordena a los que terminaron por tiempo. Ante el mismo tiempo decide la
+// posición del archivo, como en una llegada por foto.
+func fasterFinisher(a, b *domain.Result) bool {
+	if a.FinishTimeMs != b.FinishTimeMs {
+		return a.FinishTimeMs < b.FinishTimeMs
+	}
+	return finisherBefore(a, b)
+}
+
+// compareTeams ordena por puntaje y luego por el lugar del último corredor que puntúa
+func compareTeams(a, b *teamAccumulator) int {
+	if a.result.Score != b.result.Score {
+		if a.result.Score < b.result.Score {
+			return -1
+		}
+		return 1
+	}
+	lastA, lastB := a.places[len(a.places)-1], b.places[len(b.places)-1]
+	if lastA != lastB {
+		if lastA < lastB {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// formatTeamTime formatea la suma de tiempos de un equipo como "HH:MM:SS"
+func formatTeamTime(ms int64) string {
+	seconds := (ms + 500) / 1000
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
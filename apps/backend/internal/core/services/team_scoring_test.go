@@ -0,0 +1,133 @@
+package services
+
+import (
+	"backend/internal/core/domain"
+	"testing"
+)
+
+func teamEntry(race int, bib, sex, club string, position int, finishMs int64) *domain.EventData {
+	return &domain.EventData{
+		RaceNumber: race,
+		Result: domain.Result{
+			Bib:          bib,
+			Name:         "Corredor " + bib,
+			Sex:          sex,
+			Club:         club,
+			Position:     position,
+			FinishTimeMs: finishMs,
+		},
+	}
+}
+
+func TestComputeTeamStandingsPositions(t *testing.T) {
+	races := []domain.Race{{Number: 1, Name: "10K"}}
+	data := []*domain.EventData{
+		teamEntry(1, "1", "M", "Trotadores", 1, 1_800_000),
+		teamEntry(1, "2", "M", "Los Andes", 2, 1_850_000),
+		teamEntry(1, "3", "F", "Independiente", 3, 1_900_000),
+		teamEntry(1, "4", "F", "Los Andes", 4, 1_950_000),
+		teamEntry(1, "5", "M", "TROTADORES", 5, 2_000_000),
+		teamEntry(1, "6", "M", "Los Andes", 6, 2_050_000),
+		teamEntry(1, "7", "F", "Trotadores", 7, 2_100_000),
+		teamEntry(1, "8", "M", "Los Andes", 8, 2_150_000),
+		teamEntry(1, "9", "M", "Cerro Alegre", 9, 2_200_000),
+		teamEntry(1, "10", "M", "Trotadores", 0, 0),
+	}
+
+	scoring := effectiveTeamScoring(&domain.TeamScoring{IncludeIncomplete: true})
+	standings := computeTeamStandings(races, data, scoring)
+	if len(standings) != 1 || standings[0].RaceName != "10K" {
+		t.Fatalf("standings = %+v", standings)
+	}
+
+	teams := standings[0].Teams
+	if len(teams) != 3 {
+		t.Fatalf("expected 3 teams, got %+v", teams)
+	}
+	// Los Andes: 2 + 4 + 6 = 12 con un cuarto corredor que no puntúa. Trotadores: 1 + 5 + 7 = 13.
+	if teams[0].Team != "Los Andes" || teams[0].Score != 12 || teams[0].Position != 1 || len(teams[0].Members) != 4 {
+		t.Errorf("first = %+v, expected Los Andes with 12", teams[0])
+	}
+	if teams[0].Members[3].Scoring {
+		t.Errorf("expected the fourth runner of Los Andes not to score")
+	}
+	if teams[1].Team != "Trotadores" || teams[1].Score != 13 || teams[1].Position != 2 {
+		t.Errorf("second = %+v, expected Trotadores with 13", teams[1])
+	}
+	if teams[2].Team != "Cerro Alegre" || teams[2].Complete || teams[2].Position != 0 {
+		t.Errorf("third = %+v, expected Cerro Alegre incomplete", teams[2])
+	}
+
+	scoring.IncludeIncomplete = false
+	if teams := computeTeamStandings(races, data, scoring)[0].Teams; len(teams) != 2 {
+		t.Errorf("expected incomplete teams to be hidden, got %+v", teams)
+	}
+}
+
+func TestComputeTeamStandingsTimesBySex(t *testing.T) {
+	data := []*domain.EventData{
+		teamEntry(1, "1", "M", "A", 1, 1_000_000),
+		teamEntry(1, "2", "M", "B", 2, 1_100_000),
+		teamEntry(1, "3", "F", "A", 3, 1_200_000),
+		teamEntry(1, "4", "M", "B", 4, 1_300_000),
+		teamEntry(1, "5", "M", "A", 5, 1_400_000),
+		teamEntry(2, "6", "M", "A", 1, 3_000_000),
+	}
+
+	scoring := domain.TeamScoring{Method: domain.TeamScoringTimes, Scorers: 2, BySex: true}
+	standings := computeTeamStandings(nil, data, scoring)
+	if len(standings) != 1 || standings[0].Sex != "M" {
+		t.Fatalf("expected only the male standing of race 1 to be complete, got %+v", standings)
+	}
+
+	// A: 1.000.000 + 1.400.000; B: 1.100.000 + 1.300.000. Empatan en tiempo y gana B por el
+	// mejor lugar de su último corredor.
+	teams := standings[0].Teams
+	if teams[0].Team != "B" || teams[0].Score != 2_400_000 || teams[0].ScoreText != "00:40:00" || teams[0].Position != 1 {
+		t.Errorf("first = %+v, expected B", teams[0])
+	}
+	if teams[1].Team != "A" || teams[1].Position != 2 {
+		t.Errorf("second = %+v, expected A", teams[1])
+	}
+	if teams[1].Members[1].Place != 4 {
+		t.Errorf("expected places within the sex, got %+v", teams[1].Members)
+	}
+}
+
+func TestComputeTeamStandingsTimesIgnoreFilePositions(t *testing.T) {
+	// El archivo ubica al corredor 3 antes que al 2 aunque su tiempo es peor
+	data := []*domain.EventData{
+		teamEntry(1, "1", "M", "A", 1, 1_000_000),
+		teamEntry(1, "3", "M", "B", 2, 1_300_000),
+		teamEntry(1, "2", "M", "B", 3, 1_100_000),
+		teamEntry(1, "4", "M", "A", 4, 1_200_000),
+	}
+
+	scoring := domain.TeamScoring{Method: domain.TeamScoringTimes, Scorers: 1}
+	teams := computeTeamStandings(nil, data, scoring)[0].Teams
+	if teams[1].Team != "B" || teams[1].Score != 1_100_000 || teams[1].Members[0].Bib != "2" || teams[1].Members[0].Place != 2 {
+		t.Errorf("B = %+v, expected runner 2 to score in second place", teams[1])
+	}
+	if teams[0].Members[1].Place != 3 {
+		t.Errorf("A = %+v, expected runner 4 in third place", teams[0])
+	}
+}
+
+func TestComputeTeamStandingsSharedPosition(t *testing.T) {
+	data := []*domain.EventData{
+		teamEntry(1, "1", "M", "A", 1, 1_000_000),
+		teamEntry(1, "2", "M", "B", 2, 1_000_000),
+	}
+
+	teams := computeTeamStandings(nil, data, domain.TeamScoring{Method: domain.TeamScoringTimes, Scorers: 1})[0].Teams
+	if teams[0].Position != 1 || teams[1].Position != 2 {
+		t.Errorf("expected places to break the tie, got %+v", teams)
+	}
+
+	// Mismo tiempo y misma posición: los corredores comparten el lugar y los equipos la posición
+	data[1].Result.Position = 1
+	teams = computeTeamStandings(nil, data, domain.TeamScoring{Method: domain.TeamScoringTimes, Scorers: 1})[0].Teams
+	if teams[0].Position != 1 || teams[1].Position != 1 || teams[1].Members[0].Place != 1 {
+		t.Errorf("expected both teams tied first, got %+v", teams)
+	}
+}
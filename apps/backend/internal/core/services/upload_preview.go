@@ -0,0 +1,116 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// uploadPreviewTTL es el tiempo que un administrador tiene para confirmar una vista previa
+const uploadPreviewTTL = 30 * time.Minute
+
+// maxPreviewsPerEvent y maxPreviewBytes limitan la memoria usada por las vistas previas.
+// Al superarlos se descartan las vistas previas más antiguas.
+const (
+	maxPreviewsPerEvent = 5
+	maxPreviewBytes     = 256 << 20
+)
+
+// uploadPreview es un archivo ya verificado que espera confirmación para publicarse
+type uploadPreview struct {
+	eventID primitive.ObjectID
+	file    uploadedFile
+	// baseHash es el FileHash del evento al generar la vista previa. Si cambia antes
+	// de confirmar, otra carga se publicó entremedio y la vista previa ya no es válida.
+	baseHash  string
+	expiresAt time.Time
+}
+
+// previewStore guarda en memoria las vistas previas pendientes de confirmación
+type previewStore struct {
+	mu       sync.Mutex
+	previews map[string]*uploadPreview
+	bytes    int
+}
+
+func newPreviewStore() *previewStore {
+	return &previewStore{previews: make(map[string]*uploadPreview)}
+}
+
+// put guarda la vista previa y retorna el token para confirmarla
+func (s *previewStore) put(preview *uploadPreview) (string, error) {
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeExpired(time.Now())
+	for s.eventPreviews(preview.eventID) >= maxPreviewsPerEvent {
+		s.removeOldest(preview.eventID)
+	}
+	for len(s.previews) > 0 && s.bytes+len(preview.file.content) > maxPreviewBytes {
+		s.removeOldest(primitive.NilObjectID)
+	}
+	s.previews[token] = preview
+	s.bytes += len(preview.file.content)
+	return token, nil
+}
+
+// take retorna la vista previa del evento y la elimina, para que solo se pueda confirmar una vez
+func (s *previewStore) take(token string, eventID primitive.ObjectID) (*uploadPreview, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeExpired(time.Now())
+	preview, ok := s.previews[token]
+	if !ok || preview.eventID != eventID {
+		return nil, false
+	}
+	s.remove(token)
+	return preview, true
+}
+
+func (s *previewStore) remove(token string) {
+	if preview, ok := s.previews[token]; ok {
+		s.bytes -= len(preview.file.content)
+		delete(s.previews, token)
+	}
+}
+
+func (s *previewStore) removeExpired(now time.Time) {
+	for token, preview := range s.previews {
+		if now.After(preview.expiresAt) {
+			s.remove(token)
+		}
+	}
+}
+
+func (s *previewStore) eventPreviews(eventID primitive.ObjectID) int {
+	count := 0
+	for _, preview := range s.previews {
+		if preview.eventID == eventID {
+			count++
+		}
+	}
+	return count
+}
+
+// removeOldest descarta la vista previa que vence primero, del evento indicado o, si el
+// evento es vacío, de cualquier evento
+func (s *previewStore) removeOldest(eventID primitive.ObjectID) {
+	oldest := ""
+	for token, preview := range s.previews {
+		if !eventID.IsZero() && preview.eventID != eventID {
+			continue
+		}
+		if oldest == "" || preview.expiresAt.Before(s.previews[oldest].expiresAt) {
+			oldest = token
+		}
+	}
+	s.remove(oldest)
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPreviewStoreLimits(t *testing.T) {
+	store := newPreviewStore()
+	eventID := primitive.NewObjectID()
+	start := time.Now().Add(uploadPreviewTTL)
+
+	var first string
+	for i := 0; i < maxPreviewsPerEvent+2; i++ {
+		token, err := store.put(&uploadPreview{eventID: eventID, expiresAt: start.Add(time.Duration(i) * time.Second)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			first = token
+		}
+	}
+	if got := store.eventPreviews(eventID); got != maxPreviewsPerEvent {
+		t.Errorf("event has %d previews, expected %d", got, maxPreviewsPerEvent)
+	}
+	if _, ok := store.take(first, eventID); ok {
+		t.Error("expected the oldest preview of the event to be discarded")
+	}
+
+	other := primitive.NewObjectID()
+	large := uploadedFile{content: make([]byte, maxPreviewBytes/2+1)}
+	if _, err := store.put(&uploadPreview{eventID: other, file: large, expiresAt: start}); err != nil {
+		t.Fatal(err)
+	}
+	token, err := store.put(&uploadPreview{eventID: other, file: large, expiresAt: start.Add(time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.bytes > maxPreviewBytes || store.eventPreviews(other) != 1 {
+		t.Errorf("store holds %d bytes in %d previews", store.bytes, store.eventPreviews(other))
+	}
+	if _, ok := store.take(token, other); !ok || store.bytes != 0 {
+		t.Errorf("expected the latest preview to be kept, store holds %d bytes", store.bytes)
+	}
+}
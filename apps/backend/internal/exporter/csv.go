@@ -0,0 +1,40 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// CSV writes all races in a single table separated by ';', with a byte order
+// mark so spreadsheets open it as UTF-8.
+type CSV struct{}
+
+func (CSV) Format() string { return FormatCSV }
+
+func (CSV) ContentType() string { return "text/csv; charset=utf-8" }
+
+func (CSV) Extension() string { return ".csv" }
+
+func (CSV) Export(w io.Writer, doc Document) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+
+	extras := extraColumns(doc.Races)
+	cw := csv.NewWriter(w)
+	cw.Comma = ';'
+	cw.UseCRLF = true
+
+	if err := cw.Write(append(append([]string{}, tableColumns...), extras...)); err != nil {
+		return err
+	}
+	for _, race := range doc.Races {
+		for _, data := range race.Results {
+			if err := cw.Write(tableRow(race.Race, data, extras)); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
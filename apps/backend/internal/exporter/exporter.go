@@ -0,0 +1,216 @@
+// Package exporter writes published event results as CSV, XLSX, PDF or
+// racecheck files. Results are written in the order they are given, which is
+// the order of the participants listing.
+package exporter
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/utils"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Export formats.
+const (
+	FormatCSV       = "csv"
+	FormatXLSX      = "xlsx"
+	FormatPDF       = "pdf"
+	FormatRaceCheck = "racecheck"
+)
+
+// ErrUnknownFormat is returned by ForFormat when no exporter matches.
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// Document is what gets exported: an event and its results grouped by race.
+type Document struct {
+	Event *domain.Event
+	Races []RaceResults
+	// Filter describes the filters applied, e.g. "Categoría: Damas". It is shown
+	// in the PDF title and empty when the whole event is exported.
+	Filter string
+}
+
+// RaceResults are the results of one race, in listing order.
+type RaceResults struct {
+	Race    domain.Race
+	Results []*domain.EventData
+}
+
+// Exporter writes a document in a given format.
+type Exporter interface {
+	Format() string
+	ContentType() string
+	// Extension is the file extension, including the dot.
+	Extension() string
+	Export(w io.Writer, doc Document) error
+}
+
+var exporters = map[string]Exporter{
+	FormatCSV:       CSV{},
+	FormatXLSX:      XLSX{},
+	FormatPDF:       PDF{},
+	FormatRaceCheck: RaceCheck{},
+}
+
+// ForFormat returns the exporter for format.
+func ForFormat(format string) (Exporter, error) {
+	if exp, ok := exporters[strings.ToLower(strings.TrimSpace(format))]; ok {
+		return exp, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+}
+
+// GroupByRace groups results by race number, keeping the order of the results
+// within each race. Races are ordered as in the event; results of races the
+// event does not list are grouped under their race name.
+func GroupByRace(event *domain.Event, results []*domain.EventData) []RaceResults {
+	byNumber := make(map[int]*RaceResults)
+	var groups []*RaceResults
+
+	for _, race := range event.Races {
+		group := &RaceResults{Race: race}
+		byNumber[race.Number] = group
+		groups = append(groups, group)
+	}
+
+	for _, result := range results {
+		group, ok := byNumber[result.RaceNumber]
+		if !ok {
+			group = &RaceResults{Race: domain.Race{Number: result.RaceNumber, Name: result.RaceName}}
+			byNumber[result.RaceNumber] = group
+			groups = append(groups, group)
+		}
+		group.Results = append(group.Results, result)
+	}
+
+	races := make([]RaceResults, 0, len(groups))
+	for _, group := range groups {
+		if len(group.Results) > 0 {
+			races = append(races, *group)
+		}
+	}
+	return races
+}
+
+// Result columns, named like the racecheck columns so CSV and XLSX exports can
+// be imported again.
+const (
+	columnRace             = "CARRERA"
+	columnPosition         = "POSICION"
+	columnCategoryPosition = "POS.CAT."
+	columnBib              = "DORSAL"
+	columnName             = "NOMBRE"
+	columnSex              = "SEXO"
+	columnCategory         = "CATEGORIA"
+	columnModality         = "MODALIDAD"
+	columnChip             = "CHIP"
+	columnTime             = "TIEMPO"
+	columnPace             = "RITMO"
+	columnGap              = "DIFERENCIA"
+	// columnClub is written as the first extra column when any result has a club
+	columnClub = "CLUB"
+)
+
+// tableColumns are the columns written to CSV and XLSX, before extra columns.
+var tableColumns = []string{
+	columnRace, columnPosition, columnCategoryPosition, columnBib, columnName, columnSex,
+	columnCategory, columnModality, columnChip, columnTime, columnPace, columnGap,
+}
+
+// extraColumns returns the extra columns found in the results, sorted, after
+// the club column when any result has a club.
+func extraColumns(races []RaceResults) []string {
+	seen := make(map[string]bool)
+	hasClub := false
+	var extras []string
+	for _, race := range races {
+		for _, data := range race.Results {
+			if data.Result.Club != "" {
+				hasClub = true
+			}
+			for column := range data.Result.Extras {
+				if !seen[column] {
+					seen[column] = true
+					extras = append(extras, column)
+				}
+			}
+		}
+	}
+	sort.Strings(extras)
+	if hasClub && !seen[columnClub] {
+		extras = append([]string{columnClub}, extras...)
+	}
+	return extras
+}
+
+// extraValue returns the value of an extra column of a result.
+func extraValue(result domain.Result, column string) string {
+	if column == columnClub && result.Club != "" {
+		return result.Club
+	}
+	return result.Extras[column]
+}
+
+// tableRow returns the values of a result for tableColumns followed by extras.
+func tableRow(race domain.Race, data *domain.EventData, extras []string) []string {
+	result := data.Result
+	gap := ""
+	if result.FinishTimeMs > 0 && result.GapToLeaderMs > 0 {
+		gap = "+" + formatDuration(result.GapToLeaderMs)
+	}
+
+	row := []string{
+		race.Name,
+		positionText(data),
+		positionNumber(result.CategoryPosition),
+		result.Bib,
+		result.Name,
+		result.Sex,
+		result.Category,
+		result.Modality,
+		result.Chip,
+		result.FinishTimeText,
+		result.Pace,
+		gap,
+	}
+	for _, column := range extras {
+		row = append(row, extraValue(result, column))
+	}
+	return row
+}
+
+// positionText returns the position, or for participants without one the
+// original value of the position column, such as "DNF".
+func positionText(data *domain.EventData) string {
+	if data.Result.Position > 0 {
+		return strconv.Itoa(data.Result.Position)
+	}
+	for column, value := range data.Data {
+		if utils.NormalizeKey(column) == "POSICION" {
+			if text, ok := value.(string); ok {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+func positionNumber(position int) string {
+	if position <= 0 {
+		return ""
+	}
+	return strconv.Itoa(position)
+}
+
+// formatDuration formats milliseconds as HH:MM:SS, or MM:SS under an hour.
+func formatDuration(ms int64) string {
+	seconds := ms / 1000
+	if seconds >= 3600 {
+		return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
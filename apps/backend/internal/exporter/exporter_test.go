@@ -0,0 +1,158 @@
+package exporter
+
+import (
+	"backend/internal/core/domain"
+	"backend/internal/importer"
+	"backend/internal/racecheck"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testDocument() Document {
+	event := &domain.Event{
+		Name: "Corrida Casablanca 2024",
+		Date: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		Races: []domain.Race{
+			{Number: 1, Name: "10K"},
+			{Number: 2, Name: "5K"},
+		},
+	}
+	result := func(race int, position int, name, bib, sex, category, timeText string, ms int64) *domain.EventData {
+		return &domain.EventData{
+			RaceNumber: race,
+			Result: domain.Result{
+				Name: name, Bib: bib, Sex: sex, Category: category, Modality: event.Races[race-1].Name,
+				Position: position, CategoryPosition: position, FinishTimeText: timeText, FinishTimeMs: ms,
+				Extras: map[string]string{"CLUB": "Runners"},
+			},
+			Data: map[string]interface{}{"POSICION": "DNF"},
+		}
+	}
+	results := []*domain.EventData{
+		result(1, 1, "Cristobal Inostroza", "121", "M", "Senior", "00:33:37", 2017000),
+		result(1, 2, "Ana Muñoz", "45", "F", "Damas", "00:35:00", 2100000),
+		result(2, 1, "Benjamin Ruiz", "104", "M", "Senior", "00:18:00", 1080000),
+		result(1, 0, "Paula Rojas", "46", "F", "Damas", "", 0),
+	}
+	results[1].Result.GapToLeaderMs = 83000
+	return Document{Event: event, Races: GroupByRace(event, results)}
+}
+
+func TestGroupByRace(t *testing.T) {
+	doc := testDocument()
+	if len(doc.Races) != 2 || doc.Races[0].Race.Name != "10K" || len(doc.Races[0].Results) != 3 {
+		t.Fatalf("unexpected races: %+v", doc.Races)
+	}
+	if doc.Races[0].Results[2].Result.Name != "Paula Rojas" {
+		t.Errorf("results should keep listing order, got %q last", doc.Races[0].Results[2].Result.Name)
+	}
+}
+
+func TestCSVExportCanBeImported(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (CSV{}).Export(&buf, testDocument()); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	f, err := importer.CSV{}.Import(&buf, importer.Options{})
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+	if len(f.Races) != 2 || f.RowCount() != 4 {
+		t.Fatalf("got %d races and %d rows, expected 2 and 4", len(f.Races), f.RowCount())
+	}
+	race := f.Races[0]
+	row := race.RowMap(race.Rows[1])
+	if row[importer.ColumnName] != "Ana Muñoz" || row[importer.ColumnTime] != "00:35:00" || row["DIFERENCIA"] != "+01:23" || row["CLUB"] != "Runners" {
+		t.Errorf("unexpected row: %v", row)
+	}
+	if dnf := race.RowMap(race.Rows[2]); dnf[importer.ColumnPosition] != "DNF" {
+		t.Errorf("position = %q, expected DNF", dnf[importer.ColumnPosition])
+	}
+}
+
+func TestXLSXExportCanBeImported(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (XLSX{}).Export(&buf, testDocument()); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	// The importer reads the first sheet, which is the first race
+	f, err := importer.XLSX{}.Import(&buf, importer.Options{EventName: "Corrida"})
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+	if len(f.Races) != 1 || f.Races[0].Name != "10K" || len(f.Races[0].Rows) != 3 {
+		t.Fatalf("unexpected races: %+v", f.Races)
+	}
+	if name, _ := f.Races[0].Value(f.Races[0].Rows[0], importer.ColumnName); name != "Cristobal Inostroza" {
+		t.Errorf("name = %q", name)
+	}
+}
+
+func TestRaceCheckExport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (RaceCheck{}).Export(&buf, testDocument()); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	f, err := racecheck.Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if f.Header.Name != "Corrida Casablanca 2024" || len(f.Races) != 2 || f.RowCount() != 4 || len(f.Skipped) != 0 {
+		t.Fatalf("unexpected file: header %q, %d races, %d rows, %d skipped", f.Header.Name, len(f.Races), f.RowCount(), len(f.Skipped))
+	}
+	if bib, _ := f.Races[1].Value(f.Races[1].Rows[0], "DORSAL"); bib != "104" {
+		t.Errorf("bib = %q, expected 104", bib)
+	}
+}
+
+func TestPDFExport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (PDF{}).Export(&buf, testDocument()); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "%PDF-") {
+		t.Errorf("output is not a PDF: %q", buf.String()[:20])
+	}
+}
+
+func TestPodiums(t *testing.T) {
+	entry := func(name, category string, position int, status string) *domain.EventData {
+		return &domain.EventData{Result: domain.Result{
+			Name: name, Category: category, Position: position, Status: status, FinishTimeMs: int64(2000000 + position),
+		}}
+	}
+	results := []*domain.EventData{
+		entry("Ana", "Damas", 1, ""),
+		entry("Paula", "Damas", 2, ""),
+		entry("Rocío", "Damas", 0, domain.ResultStatusDSQ),
+		entry("Carla", "Damas", 0, ""),
+		entry("Inés", "Damas", 3, domain.ResultStatusOTL),
+		entry("Sofía", "Juvenil", 0, domain.ResultStatusDNF),
+	}
+
+	groups, order := podiums(results, func(d *domain.EventData) string { return d.Result.Category })
+	if len(order) != 1 || order[0] != "Damas" {
+		t.Fatalf("categories = %v, expected only Damas", order)
+	}
+	if places := groups["Damas"]; len(places) != 2 || places[0].Result.Name != "Ana" || places[1].Result.Name != "Paula" {
+		t.Errorf("podium = %+v, expected only the two ranked finishers", places)
+	}
+}
+
+func TestSheetName(t *testing.T) {
+	used := make(map[string]bool)
+	if name := sheetName("10K / Damas", 1, used); name != "10K - Damas" {
+		t.Errorf("sheetName = %q", name)
+	}
+	if name := sheetName("10k - damas", 2, used); name != "10k - damas (2)" {
+		t.Errorf("duplicate sheetName = %q", name)
+	}
+	if name := sheetName("", 3, used); name != "Carrera 3" {
+		t.Errorf("empty sheetName = %q", name)
+	}
+}
@@ -0,0 +1,215 @@
+package exporter
+
+import (
+	"backend/internal/core/domain"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// PDF writes a printable results sheet per race: the general podium by sex,
+// the podium of each category, and the full results table.
+type PDF struct{}
+
+func (PDF) Format() string { return FormatPDF }
+
+func (PDF) ContentType() string { return "application/pdf" }
+
+func (PDF) Extension() string { return ".pdf" }
+
+// podiumSize is the number of places shown in each podium.
+const podiumSize = 3
+
+type pdfColumn struct {
+	title string
+	width float64
+	align string
+	value func(data *domain.EventData) string
+}
+
+var pdfColumns = []pdfColumn{
+	{"Pos.", 12, "C", positionText},
+	{"Dorsal", 16, "C", func(d *domain.EventData) string { return d.Result.Bib }},
+	{"Nombre", 62, "L", func(d *domain.EventData) string { return d.Result.Name }},
+	{"Categoría", 44, "L", func(d *domain.EventData) string { return d.Result.Category }},
+	{"P. Cat.", 13, "C", func(d *domain.EventData) string { return positionNumber(d.Result.CategoryPosition) }},
+	{"Tiempo", 20, "C", func(d *domain.EventData) string { return d.Result.FinishTimeText }},
+	{"Ritmo", 23, "C", func(d *domain.EventData) string { return strings.TrimSuffix(d.Result.Pace, " min/Km") }},
+}
+
+func (PDF) Export(w io.Writer, doc Document) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(10, 12, 10)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+	pdf.SetTitle(doc.Event.Name, true)
+
+	// The core fonts use Windows-1252, like the files from the timing software
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(0, 6, tr(fmt.Sprintf("%s - Página %d de {nb}", doc.Event.Name, pdf.PageNo())), "", 0, "C", false, 0, "")
+	})
+
+	if len(doc.Races) == 0 {
+		pdf.AddPage()
+		writeTitle(pdf, tr, doc, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 8, tr("No hay resultados para exportar."), "", 1, "L", false, 0, "")
+	}
+
+	for _, race := range doc.Races {
+		pdf.AddPage()
+		writeTitle(pdf, tr, doc, race.Race.Name)
+		writePodiums(pdf, tr, race.Results)
+		writeResultsTable(pdf, tr, race.Results)
+	}
+
+	return pdf.Output(w)
+}
+
+func writeTitle(pdf *fpdf.Fpdf, tr func(string) string, doc Document, raceName string) {
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 8, tr(doc.Event.Name), "", 1, "L", false, 0, "")
+
+	var details []string
+	if raceName != "" {
+		details = append(details, raceName)
+	}
+	if !doc.Event.Date.IsZero() {
+		details = append(details, doc.Event.Date.Format("02/01/2006"))
+	}
+	if doc.Filter != "" {
+		details = append(details, doc.Filter)
+	}
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(0, 6, tr(strings.Join(details, " - ")), "", 1, "L", false, 0, "")
+	pdf.Ln(3)
+}
+
+// writePodiums writes the first places by sex and by category. Results are in
+// listing order, so the first finishers of each group are its podium.
+func writePodiums(pdf *fpdf.Fpdf, tr func(string) string, results []*domain.EventData) {
+	bySex, sexes := podiums(results, func(d *domain.EventData) string { return d.Result.Sex })
+	byCategory, categories := podiums(results, func(d *domain.EventData) string { return d.Result.Category })
+	if len(sexes) == 0 && len(categories) == 0 {
+		return
+	}
+
+	sectionTitle(pdf, tr, "Podio general")
+	for _, sex := range sexes {
+		label := "General"
+		if sex != "" {
+			label = "General " + sex
+		}
+		writePodium(pdf, tr, label, bySex[sex])
+	}
+
+	if len(categories) > 0 {
+		sectionTitle(pdf, tr, "Podios por categoría")
+		for _, category := range categories {
+			writePodium(pdf, tr, category, byCategory[category])
+		}
+	}
+	pdf.Ln(2)
+}
+
+// podiums groups the first finishers by key, keeping groups in order of appearance.
+// Only ranked finishers take a place: runners with a status (DNF, DNS, DSQ, OTL) or
+// without a position are skipped even if they have a finish time.
+func podiums(results []*domain.EventData, key func(*domain.EventData) string) (map[string][]*domain.EventData, []string) {
+	groups := make(map[string][]*domain.EventData)
+	var order []string
+	for _, data := range results {
+		if data.Result.Status != "" || data.Result.Position <= 0 {
+			continue
+		}
+		k := key(data)
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		if len(groups[k]) < podiumSize {
+			groups[k] = append(groups[k], data)
+		}
+	}
+	return groups, order
+}
+
+func sectionTitle(pdf *fpdf.Fpdf, tr func(string) string, title string) {
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.CellFormat(0, 7, tr(title), "B", 1, "L", false, 0, "")
+	pdf.Ln(1)
+}
+
+func writePodium(pdf *fpdf.Fpdf, tr func(string) string, label string, places []*domain.EventData) {
+	// Keep a podium on a single page
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+	if pdf.GetY()+float64(len(places)+1)*5 > pageHeight-bottom {
+		pdf.AddPage()
+	}
+
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(0, 6, tr(label), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for i, data := range places {
+		pdf.CellFormat(12, 5, strconv.Itoa(i+1)+tr("°"), "", 0, "C", false, 0, "")
+		pdf.CellFormat(110, 5, tr(data.Result.Name), "", 0, "L", false, 0, "")
+		pdf.CellFormat(25, 5, tr(data.Result.Bib), "", 0, "C", false, 0, "")
+		pdf.CellFormat(0, 5, tr(data.Result.FinishTimeText), "", 1, "R", false, 0, "")
+	}
+	pdf.Ln(1)
+}
+
+func writeResultsTable(pdf *fpdf.Fpdf, tr func(string) string, results []*domain.EventData) {
+	sectionTitle(pdf, tr, "Resultados")
+
+	const rowHeight = 5.5
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+
+	writeHeader := func() {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(40, 40, 40)
+		pdf.SetTextColor(255, 255, 255)
+		for _, col := range pdfColumns {
+			pdf.CellFormat(col.width, 6, tr(col.title), "", 0, col.align, true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetFillColor(240, 240, 240)
+	}
+	writeHeader()
+
+	for i, data := range results {
+		// Repeat the header on every page of the table
+		if pdf.GetY()+rowHeight > pageHeight-bottom {
+			pdf.AddPage()
+			writeHeader()
+		}
+		for _, col := range pdfColumns {
+			pdf.CellFormat(col.width, rowHeight, fitText(pdf, tr(col.value(data)), col.width-2), "", 0, col.align, i%2 == 1, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+}
+
+// fitText shortens text that does not fit in width. The text is already
+// translated to a single byte encoding, so it is cut by bytes.
+func fitText(pdf *fpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	for len(text) > 0 && pdf.GetStringWidth(text+"...") > width {
+		text = text[:len(text)-1]
+	}
+	return text + "..."
+}
@@ -0,0 +1,58 @@
+package exporter
+
+import (
+	"backend/internal/racecheck"
+	"io"
+)
+
+// raceCheckColumns are the columns written by the timing software.
+var raceCheckColumns = []string{
+	columnSex, columnName, columnChip, columnBib, columnModality,
+	columnCategory, columnTime, columnPosition, columnCategoryPosition, columnPace,
+}
+
+// RaceCheck writes the results back in the racecheck format, one section per
+// race, so they can be uploaded to another event or opened by the timing
+// software.
+type RaceCheck struct{}
+
+func (RaceCheck) Format() string { return FormatRaceCheck }
+
+func (RaceCheck) ContentType() string { return "text/plain; charset=utf-8" }
+
+func (RaceCheck) Extension() string { return ".racecheck" }
+
+func (RaceCheck) Export(w io.Writer, doc Document) error {
+	extras := extraColumns(doc.Races)
+	f := &racecheck.File{Header: racecheck.EventHeader{Prefix: "1", Name: doc.Event.Name}}
+
+	for _, race := range doc.Races {
+		section := &racecheck.Race{
+			Number:  race.Race.Number,
+			Name:    race.Race.Name,
+			Columns: append(append([]string{}, raceCheckColumns...), extras...),
+		}
+		for _, data := range race.Results {
+			result := data.Result
+			values := []string{
+				result.Sex,
+				result.Name,
+				result.Chip,
+				result.Bib,
+				result.Modality,
+				result.Category,
+				result.FinishTimeText,
+				positionText(data),
+				positionNumber(result.CategoryPosition),
+				result.Pace,
+			}
+			for _, column := range extras {
+				values = append(values, extraValue(result, column))
+			}
+			section.Rows = append(section.Rows, racecheck.Row{Values: values})
+		}
+		f.Races = append(f.Races, section)
+	}
+
+	return racecheck.Write(w, f)
+}
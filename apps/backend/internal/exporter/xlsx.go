@@ -0,0 +1,168 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// XLSX writes a workbook with one sheet per race. Positions are written as
+// numbers so the sheet can be sorted; everything else is text.
+type XLSX struct{}
+
+func (XLSX) Format() string { return FormatXLSX }
+
+func (XLSX) ContentType() string {
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+func (XLSX) Extension() string { return ".xlsx" }
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+%s</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+// numericColumns are written as numbers when the value is an integer.
+var numericColumns = map[string]bool{columnPosition: true, columnCategoryPosition: true}
+
+func (XLSX) Export(w io.Writer, doc Document) error {
+	extras := extraColumns(doc.Races)
+	header := append(append([]string{}, tableColumns...), extras...)
+
+	races := doc.Races
+	if len(races) == 0 {
+		// A workbook needs at least one sheet
+		races = []RaceResults{{}}
+	}
+
+	zw := zip.NewWriter(w)
+	var overrides, sheets, rels strings.Builder
+	usedNames := make(map[string]bool)
+
+	for i, race := range races {
+		n := i + 1
+		fmt.Fprintf(&overrides, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`+"\n", n)
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(sheetName(race.Race.Name, n, usedNames)), n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+
+		rows := [][]string{header}
+		for _, data := range race.Results {
+			rows = append(rows, tableRow(race.Race, data, extras))
+		}
+		if err := writeZipEntry(zw, fmt.Sprintf("xl/worksheets/sheet%d.xml", n), worksheetXML(header, rows)); err != nil {
+			return err
+		}
+	}
+
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` +
+		sheets.String() + `</sheets></workbook>`
+	workbookRels := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		rels.String() + `</Relationships>`
+
+	entries := []struct{ name, content string }{
+		{"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, overrides.String())},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", workbook},
+		{"xl/_rels/workbook.xml.rels", workbookRels},
+	}
+	for _, entry := range entries {
+		if err := writeZipEntry(zw, entry.name, entry.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// worksheetXML renders the rows of a sheet. The first row is the header.
+func worksheetXML(header []string, rows [][]string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for r, row := range rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, value := range row {
+			if value == "" {
+				continue
+			}
+			ref := cellRef(c, r+1)
+			if r > 0 && numericColumns[header[c]] {
+				if _, err := strconv.Atoi(value); err == nil {
+					fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, value)
+					continue
+				}
+			}
+			fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, xmlEscape(value))
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// cellRef returns the reference of a cell from a zero based column, e.g.
+// column 27 of row 3 is "AB3".
+func cellRef(col, row int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name + strconv.Itoa(row)
+}
+
+// sheetName returns a valid and unique sheet name: at most 31 characters and
+// none of []:*?/\.
+func sheetName(name string, n int, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = fmt.Sprintf("Carrera %d", n)
+	}
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+
+	candidate := name
+	for i := 2; used[strings.ToLower(candidate)]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		runes := []rune(name)
+		if len(runes)+len(suffix) > 31 {
+			runes = runes[:31-len(suffix)]
+		}
+		candidate = string(runes) + suffix
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+func xmlEscape(value string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(value))
+	return buf.String()
+}
+
+func writeZipEntry(zw *zip.Writer, name, content string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, content)
+	return err
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"backend/internal/core/ports"
+	"backend/internal/core/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AthleteHandler struct {
+	athleteService ports.AthleteService
+}
+
+func NewAthleteHandler(athleteService ports.AthleteService) *AthleteHandler {
+	return &AthleteHandler{
+		athleteService: athleteService,
+	}
+}
+
+func (h *AthleteHandler) GetAthletes(c *gin.Context) {
+	name := c.Query("name")
+
+	var page, limit int
+	var err error
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		page, err = strconv.Atoi(pageStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page format, must be a number"})
+			return
+		}
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit format, must be a number"})
+			return
+		}
+	}
+
+	var namePtr *string
+	if name != "" {
+		namePtr = &name
+	}
+
+	result, err := h.athleteService.GetAthletes(namePtr, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *AthleteHandler) GetAthlete(c *gin.Context) {
+	athlete, err := h.athleteService.GetAthlete(c.Param("id"))
+	if err != nil {
+		respondAthleteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, athlete)
+}
+
+// GetAthleteResults retorna el historial de carreras del atleta y sus mejores marcas por distancia
+func (h *AthleteHandler) GetAthleteResults(c *gin.Context) {
+	includeHidden := c.Query("includeHidden") == "true"
+
+	history, err := h.athleteService.GetAthleteResults(c.Param("id"), includeHidden)
+	if err != nil {
+		respondAthleteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *AthleteHandler) UpdateAthlete(c *gin.Context) {
+	var req ports.UpdateAthleteRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	athlete, err := h.athleteService.UpdateAthlete(c.Param("id"), &req)
+	if err != nil {
+		respondAthleteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, athlete)
+}
+
+// MergeAthletes une otros atletas al atleta de la ruta
+func (h *AthleteHandler) MergeAthletes(c *gin.Context) {
+	var req ports.MergeAthletesRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	athlete, err := h.athleteService.MergeAthletes(c.Param("id"), &req)
+	if err != nil {
+		respondAthleteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, athlete)
+}
+
+// SplitAthlete separa resultados del atleta de la ruta en un atleta nuevo, que se retorna
+func (h *AthleteHandler) SplitAthlete(c *gin.Context) {
+	var req ports.SplitAthleteRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	athlete, err := h.athleteService.SplitAthlete(c.Param("id"), &req)
+	if err != nil {
+		respondAthleteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, athlete)
+}
+
+func respondAthleteError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidObjectID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid athlete id"})
+	} else if errors.Is(err, services.ErrInvalidAthleteRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	} else if errors.Is(err, services.ErrAthleteNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
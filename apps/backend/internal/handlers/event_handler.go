@@ -5,10 +5,12 @@ import (
 	"backend/internal/core/ports"
 	"backend/internal/core/services"
 	"backend/internal/utils"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -68,10 +70,16 @@ func (h *EventHandler) Upload(c *gin.Context) {
 		return
 	}
 
+	options, err := uploadOptionsFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// 4. Call service
-	result, err := h.eventService.Upload(fileHeader, clientHash)
+	result, err := h.eventService.Upload(fileHeader, clientHash, options)
 	if err != nil {
-		if errors.Is(err, services.ErrFileHashMismatch) || errors.Is(err, services.ErrInvalidFileExtension) {
+		if errors.Is(err, services.ErrFileHashMismatch) || errors.Is(err, services.ErrInvalidFileExtension) || errors.Is(err, services.ErrInvalidImportFile) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -79,7 +87,11 @@ func (h *EventHandler) Upload(c *gin.Context) {
 		return
 	}
 
-	// 5. Return success response
+	// 5. Return response. A rejected upload carries its validation report.
+	if result.Rejected {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
 	c.JSON(http.StatusOK, result)
 }
 
@@ -239,6 +251,7 @@ func (h *EventHandler) GetParticipants(c *gin.Context) {
 	distance := c.Query("distance")
 	sex := c.Query("sex")
 	position := c.Query("position")
+	race := c.Query("race")
 	pageStr := c.Query("page")
 	limitStr := c.Query("limit")
 
@@ -291,7 +304,7 @@ func (h *EventHandler) GetParticipants(c *gin.Context) {
 	}
 
 	// 2. Call service
-	result, err := h.eventService.GetParticipants(eventID, toPtr(name), toPtr(chip), toPtr(dorsal), toPtr(category), toPtr(distance), toPtr(sex), toPtr(position), page, limit)
+	result, err := h.eventService.GetParticipants(eventID, toPtr(name), toPtr(chip), toPtr(dorsal), toPtr(category), toPtr(distance), toPtr(sex), toPtr(position), toPtr(race), page, limit)
 	if err != nil {
 		if errors.Is(err, services.ErrInvalidObjectID) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -465,26 +478,115 @@ func (h *EventHandler) UploadToEvent(c *gin.Context) {
 	}
 	fmt.Printf("[INFO] Client hash: %s\n", clientHash)
 
-	// 5. Call service
+	options, err := uploadOptionsFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 5. With preview=true, return what would change without modifying the event
+	if c.PostForm("preview") == "true" || c.Query("preview") == "true" {
+		fmt.Printf("[INFO] Calling eventService.PreviewUploadToEvent with eventID=%s, hash=%s\n", eventID, clientHash)
+		preview, err := h.eventService.PreviewUploadToEvent(fileHeader, clientHash, eventID, options)
+		if err != nil {
+			fmt.Printf("[ERROR] PreviewUploadToEvent service failed: %v\n", err)
+			h.respondUploadError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
+	// 6. Call service
 	fmt.Printf("[INFO] Calling eventService.UploadToEvent with eventID=%s, hash=%s\n", eventID, clientHash)
-	result, err := h.eventService.UploadToEvent(fileHeader, clientHash, eventID)
+	result, err := h.eventService.UploadToEvent(fileHeader, clientHash, eventID, options)
 	if err != nil {
 		fmt.Printf("[ERROR] UploadToEvent service failed: %v\n", err)
-		if errors.Is(err, services.ErrFileHashMismatch) || errors.Is(err, services.ErrInvalidFileExtension) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		} else if errors.Is(err, services.ErrInvalidObjectID) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		h.respondUploadError(c, err)
+		return
+	}
+
+	// 7. Return response. A rejected upload carries its validation report.
+	if result.Rejected {
+		fmt.Printf("[WARNING] Upload rejected. EventID=%s, Errors=%d\n", result.EventID, result.Report.ErrorCount)
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+	fmt.Printf("[SUCCESS] Upload completed. EventID=%s, RecordsInserted=%d, Reprocessed=%v\n", result.EventID, result.RecordsInserted, result.Reprocessed)
+	c.JSON(http.StatusOK, result)
+}
+
+// CommitUpload publica el archivo de una vista previa generada con preview=true
+func (h *EventHandler) CommitUpload(c *gin.Context) {
+	eventID := c.Param("id")
+
+	var req ports.CommitUploadRequest
+	if err := c.BindJSON(&req); err != nil || req.Token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		return
+	}
+
+	result, err := h.eventService.CommitUploadPreview(eventID, req.Token)
+	if err != nil {
+		fmt.Printf("[ERROR] CommitUploadPreview service failed: %v\n", err)
+		if errors.Is(err, services.ErrUploadPreviewNotFound) || err.Error() == "event not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if errors.Is(err, services.ErrUploadPreviewOutdated) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			h.respondUploadError(c, err)
 		}
 		return
 	}
 
-	// 6. Return success response
-	fmt.Printf("[SUCCESS] Upload completed. EventID=%s, RecordsInserted=%d, Reprocessed=%v\n", result.EventID, result.RecordsInserted, result.Reprocessed)
+	if result.Rejected {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
 	c.JSON(http.StatusOK, result)
 }
 
+// uploaderFromRequest identifica a quien sube el archivo, a partir del campo uploadedBy
+// del formulario o del header X-Uploaded-By
+func uploaderFromRequest(c *gin.Context) string {
+	if uploadedBy := strings.TrimSpace(c.PostForm("uploadedBy")); uploadedBy != "" {
+		return uploadedBy
+	}
+	return strings.TrimSpace(c.GetHeader("X-Uploaded-By"))
+}
+
+// uploadOptionsFromRequest lee las opciones de importación del formulario: format (csv,
+// xlsx, iofxml o racecheck), delimiter, columnMapping como objeto JSON con la columna del
+// archivo y la columna estándar, y eventName
+func uploadOptionsFromRequest(c *gin.Context) (ports.UploadOptions, error) {
+	options := ports.UploadOptions{
+		UploadedBy: uploaderFromRequest(c),
+		Format:     strings.TrimSpace(c.PostForm("format")),
+		Delimiter:  c.PostForm("delimiter"),
+		EventName:  strings.TrimSpace(c.PostForm("eventName")),
+	}
+	if options.Delimiter == "\\t" {
+		options.Delimiter = "\t"
+	}
+	if mapping := strings.TrimSpace(c.PostForm("columnMapping")); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &options.ColumnMapping); err != nil {
+			return options, fmt.Errorf("invalid columnMapping: %w", err)
+		}
+	}
+	return options, nil
+}
+
+// respondUploadError responde con el código HTTP que corresponde a un error de carga
+func (h *EventHandler) respondUploadError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrFileHashMismatch) || errors.Is(err, services.ErrInvalidFileExtension) || errors.Is(err, services.ErrInvalidImportFile) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	} else if errors.Is(err, services.ErrInvalidObjectID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 // GetParticipantComparison obtiene el 1er lugar y los 5 participantes anteriores
 func (h *EventHandler) GetParticipantComparison(c *gin.Context) {
 	eventParam := c.Param("id")
@@ -529,3 +631,734 @@ func (h *EventHandler) GetParticipantComparison(c *gin.Context) {
 
 	c.JSON(http.StatusOK, result)
 }
+
+// resolveEventID obtiene el ID del evento a partir del parámetro de ruta, que puede ser un ID o un slug.
+// Si no se puede resolver, escribe la respuesta de error y retorna false.
+func (h *EventHandler) resolveEventID(c *gin.Context) (string, bool) {
+	eventParam := c.Param("id")
+
+	if primitive.IsValidObjectID(eventParam) {
+		return eventParam, true
+	}
+
+	if !utils.IsValidSlug(eventParam) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event identifier"})
+		return "", false
+	}
+
+	event, err := h.eventService.GetEventBySlug(eventParam)
+	if err != nil {
+		if err.Error() == "event not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return "", false
+	}
+	return event.ID.Hex(), true
+}
+
+// GetEventRaces obtiene las carreras del evento con su cantidad de participantes y tiempo del ganador
+func (h *EventHandler) GetEventRaces(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	races, err := h.eventService.GetEventRaces(eventID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidObjectID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		} else if err.Error() == "event not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, races)
+}
+
+// UpdateRaceDistance configura la distancia de una carrera para calcular el ritmo de sus participantes
+func (h *EventHandler) UpdateRaceDistance(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	raceNumber, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid race number"})
+		return
+	}
+
+	var req ports.UpdateRaceDistanceRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	race, err := h.eventService.UpdateRaceDistance(eventID, raceNumber, req.DistanceKm)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidObjectID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		} else if err.Error() == "event not found" || err.Error() == "race not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if err.Error() == "distance must not be negative" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, race)
+}
+
+// raceSplitsError responde el error de las consultas de tiempos parciales de una carrera
+func raceSplitsError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidObjectID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+	} else if err.Error() == "event not found" || err.Error() == "race not found" {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GetRaceSplits retorna la clasificación de una carrera en cada punto de control o vuelta
+func (h *EventHandler) GetRaceSplits(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	raceNumber, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid race number"})
+		return
+	}
+
+	splits, err := h.eventService.GetRaceSplits(eventID, raceNumber)
+	if err != nil {
+		raceSplitsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, splits)
+}
+
+// GetRaceLapChart retorna la posición de cada participante de una carrera en cada punto de
+// control o vuelta
+func (h *EventHandler) GetRaceLapChart(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	raceNumber, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid race number"})
+		return
+	}
+
+	chart, err := h.eventService.GetRaceLapChart(eventID, raceNumber)
+	if err != nil {
+		raceSplitsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, chart)
+}
+
+// GetEventUploads obtiene las cargas de archivos del evento con su reporte de validación
+func (h *EventHandler) GetEventUploads(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	uploads, err := h.eventService.GetEventUploads(eventID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidObjectID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		} else if err.Error() == "event not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, uploads)
+}
+
+// GetEventChanges obtiene el historial de cambios de resultados entre cargas del archivo.
+// Con el parámetro bib, solo incluye los cambios de ese participante.
+func (h *EventHandler) GetEventChanges(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	var bib *string
+	if value := c.Query("bib"); value != "" {
+		bib = &value
+	}
+
+	changes, err := h.eventService.GetEventChanges(eventID, bib)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidObjectID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		} else if err.Error() == "event not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// GetEventVersions obtiene las versiones publicadas del archivo de resultados del evento
+func (h *EventHandler) GetEventVersions(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	versions, err := h.eventService.GetEventVersions(eventID)
+	if err != nil {
+		respondVersionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+// GetEventVersion obtiene una versión del archivo de resultados con sus participantes
+func (h *EventHandler) GetEventVersion(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	detail, err := h.eventService.GetEventVersion(eventID, c.Param("versionId"))
+	if err != nil {
+		respondVersionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// DownloadEventVersion descarga el archivo original de una versión
+func (h *EventHandler) DownloadEventVersion(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	version, err := h.eventService.GetEventVersionFile(eventID, c.Param("versionId"))
+	if err != nil {
+		respondVersionError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", version.FileName))
+	c.Data(http.StatusOK, "application/octet-stream", version.Content)
+}
+
+// ExportEvent descarga los resultados del evento en formato csv, xlsx, pdf o racecheck,
+// con filtros opcionales por carrera, categoría y sexo
+func (h *EventHandler) ExportEvent(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	filter := ports.ExportFilter{
+		Race:     c.Query("race"),
+		Category: c.Query("category"),
+		Sex:      c.Query("sex"),
+	}
+
+	file, err := h.eventService.ExportEvent(eventID, format, filter)
+	if err != nil {
+		fmt.Printf("[ERROR] ExportEvent service failed: %v\n", err)
+		if errors.Is(err, services.ErrInvalidExportFormat) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format, use csv, xlsx, pdf or racecheck"})
+		} else if errors.Is(err, services.ErrInvalidObjectID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		} else if err.Error() == "event not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+// UpdateCertificateTemplate configura el certificado de finalización del evento
+func (h *EventHandler) UpdateCertificateTemplate(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	var req ports.UpdateCertificateTemplateRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	event, err := h.eventService.UpdateCertificateTemplate(eventID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidObjectID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		} else if errors.Is(err, services.ErrInvalidCertificate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if err.Error() == "event not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// UpdateTeamScoring configura la clasificación por equipos del evento
+func (h *EventHandler) UpdateTeamScoring(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	var req ports.UpdateTeamScoringRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	event, err := h.eventService.UpdateTeamScoring(eventID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidObjectID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		} else if errors.Is(err, services.ErrInvalidTeamScoring) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if err.Error() == "event not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// UpdateRankingSource configura si el evento publica las posiciones del archivo o las
+// calculadas a partir de los tiempos
+func (h *EventHandler) UpdateRankingSource(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	var req ports.UpdateRankingSourceRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	event, err := h.eventService.UpdateRankingSource(eventID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidObjectID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		} else if errors.Is(err, services.ErrInvalidRankingSource) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if err.Error() == "event not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// GetParticipantStatuses lista los estados fijados por los administradores del evento
+func (h *EventHandler) GetParticipantStatuses(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	statuses, err := h.eventService.GetParticipantStatuses(eventID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidObjectID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		} else if err.Error() == "event not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, statuses)
+}
+
+// UpdateParticipantStatus fija el estado (DNF, DNS, DSQ u OTL) del participante con el
+// dorsal indicado. El estado se conserva en las próximas cargas del archivo.
+func (h *EventHandler) UpdateParticipantStatus(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	var req ports.UpdateParticipantStatusRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	participant, err := h.eventService.UpdateParticipantStatus(eventID, c.Param("bib"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidObjectID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		} else if errors.Is(err, services.ErrInvalidResultStatus) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if errors.Is(err, services.ErrParticipantNotFound) || err.Error() == "event not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, participant)
+}
+
+// editorFromRequest identifica a quien corrige los resultados, a partir del header
+// X-Edited-By
+func editorFromRequest(c *gin.Context) string {
+	return strings.TrimSpace(c.GetHeader("X-Edited-By"))
+}
+
+// correctionError responde el error de las operaciones sobre correcciones
+func correctionError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidObjectID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+	} else if errors.Is(err, services.ErrInvalidCorrection) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	} else if errors.Is(err, services.ErrCorrectionNotFound) || errors.Is(err, services.ErrParticipantNotFound) || err.Error() == "event not found" {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GetCorrections lista las correcciones de datos del evento
+func (h *EventHandler) GetCorrections(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	corrections, err := h.eventService.GetCorrections(eventID)
+	if err != nil {
+		correctionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, corrections)
+}
+
+// GetCorrection retorna la corrección del participante con el dorsal indicado
+func (h *EventHandler) GetCorrection(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	correction, err := h.eventService.GetCorrection(eventID, c.Param("bib"))
+	if err != nil {
+		correctionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, correction)
+}
+
+// SaveCorrection crea o reemplaza la corrección del participante con el dorsal indicado.
+// La corrección se conserva en las próximas cargas del archivo.
+func (h *EventHandler) SaveCorrection(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	var req ports.CorrectionRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	correction, err := h.eventService.SaveCorrection(eventID, c.Param("bib"), &req, editorFromRequest(c))
+	if err != nil {
+		correctionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, correction)
+}
+
+// DeleteCorrection elimina la corrección del participante, que vuelve a los datos del archivo
+func (h *EventHandler) DeleteCorrection(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.eventService.DeleteCorrection(eventID, c.Param("bib"), editorFromRequest(c)); err != nil {
+		correctionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "correction deleted successfully"})
+}
+
+// GetCorrectionAudit lista quién creó, modificó o eliminó las correcciones del evento.
+// Acepta el parámetro bib para ver solo las de un participante.
+func (h *EventHandler) GetCorrectionAudit(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	var bib *string
+	if value := c.Query("bib"); value != "" {
+		bib = &value
+	}
+
+	entries, err := h.eventService.GetCorrectionAudit(eventID, bib)
+	if err != nil {
+		correctionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// startListError responde el error de las operaciones sobre la lista de inscritos
+func startListError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidObjectID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+	} else if errors.Is(err, services.ErrInvalidFileExtension) || errors.Is(err, services.ErrInvalidImportFile) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	} else if errors.Is(err, services.ErrStartListNotFound) || err.Error() == "event not found" {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// ImportStartList carga la lista de inscritos del evento desde un archivo CSV, XLSX o
+// racecheck. Acepta las mismas opciones de importación que la carga de resultados.
+func (h *EventHandler) ImportStartList(c *gin.Context) {
+	if err := c.Request.ParseMultipartForm(10 << 20); err != nil { // 10 MB
+		c.JSON(http.StatusBadRequest, gin.H{"error": "could not parse multipart form"})
+		return
+	}
+
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	options, err := uploadOptionsFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.eventService.ImportStartList(fileHeader, eventID, options)
+	if err != nil {
+		startListError(c, err)
+		return
+	}
+
+	if result.Rejected {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetStartList retorna la lista de inscritos del evento si está publicada. Con
+// includeHidden=true también la retorna antes de publicarla.
+func (h *EventHandler) GetStartList(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	startList, err := h.eventService.GetStartList(eventID, c.Query("includeHidden") == "true")
+	if err != nil {
+		startListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, startList)
+}
+
+// PublishStartList muestra u oculta al público la lista de inscritos del evento
+func (h *EventHandler) PublishStartList(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	var req ports.PublishStartListRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	event, err := h.eventService.PublishStartList(eventID, &req)
+	if err != nil {
+		startListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// DeleteStartList elimina la lista de inscritos del evento
+func (h *EventHandler) DeleteStartList(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.eventService.DeleteStartList(eventID); err != nil {
+		startListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "start list deleted successfully"})
+}
+
+// ReconcileStartList compara los inscritos con los resultados publicados del evento
+func (h *EventHandler) ReconcileStartList(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	reconciliation, err := h.eventService.ReconcileStartList(eventID)
+	if err != nil {
+		startListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reconciliation)
+}
+
+// GetEventTeams retorna la clasificación por equipos de cada carrera del evento
+func (h *EventHandler) GetEventTeams(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	teams, err := h.eventService.GetEventTeams(eventID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidObjectID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		} else if err.Error() == "event not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, teams)
+}
+
+// GetParticipantCertificate genera el certificado de finalización del participante en
+// formato pdf (por defecto) o png
+func (h *EventHandler) GetParticipantCertificate(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	file, err := h.eventService.GetParticipantCertificate(eventID, c.Param("bib"), c.DefaultQuery("format", "pdf"))
+	if err != nil {
+		fmt.Printf("[ERROR] GetParticipantCertificate service failed: %v\n", err)
+		if errors.Is(err, services.ErrInvalidExportFormat) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format, use pdf or png"})
+		} else if errors.Is(err, services.ErrInvalidObjectID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		} else if errors.Is(err, services.ErrParticipantNotFinished) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		} else if errors.Is(err, services.ErrParticipantNotFound) || err.Error() == "event not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	disposition := "inline"
+	if c.Query("download") == "true" {
+		disposition = "attachment"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, file.FileName))
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+// RollbackEventVersion vuelve a publicar los resultados de una versión anterior
+func (h *EventHandler) RollbackEventVersion(c *gin.Context) {
+	eventID, ok := h.resolveEventID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.eventService.RollbackEventVersion(eventID, c.Param("versionId"))
+	if err != nil {
+		fmt.Printf("[ERROR] RollbackEventVersion service failed: %v\n", err)
+		respondVersionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func respondVersionError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidObjectID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event or version id"})
+	} else if err.Error() == "event not found" || err.Error() == "version not found" {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"backend/internal/core/ports"
+	"backend/internal/core/services"
+	"backend/internal/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SeriesHandler struct {
+	seriesService ports.SeriesService
+}
+
+func NewSeriesHandler(seriesService ports.SeriesService) *SeriesHandler {
+	return &SeriesHandler{
+		seriesService: seriesService,
+	}
+}
+
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	var req ports.SeriesRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	series, err := h.seriesService.CreateSeries(&req)
+	if err != nil {
+		respondSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, series)
+}
+
+func (h *SeriesHandler) GetSeriesList(c *gin.Context) {
+	var page, limit int
+	var err error
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		page, err = strconv.Atoi(pageStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page format, must be a number"})
+			return
+		}
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit format, must be a number"})
+			return
+		}
+	}
+
+	result, err := h.seriesService.GetSeriesList(page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+	seriesID, ok := h.resolveSeriesID(c)
+	if !ok {
+		return
+	}
+
+	series, err := h.seriesService.GetSeries(seriesID)
+	if err != nil {
+		respondSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+	seriesID, ok := h.resolveSeriesID(c)
+	if !ok {
+		return
+	}
+
+	var req ports.SeriesRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	series, err := h.seriesService.UpdateSeries(seriesID, &req)
+	if err != nil {
+		respondSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+func (h *SeriesHandler) DeleteSeries(c *gin.Context) {
+	seriesID, ok := h.resolveSeriesID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.seriesService.DeleteSeries(seriesID); err != nil {
+		respondSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "series deleted successfully"})
+}
+
+// GetStandings retorna la clasificación del campeonato por categoría. Acepta ?category=
+// para obtener una sola categoría.
+func (h *SeriesHandler) GetStandings(c *gin.Context) {
+	seriesID, ok := h.resolveSeriesID(c)
+	if !ok {
+		return
+	}
+
+	var category *string
+	if value := c.Query("category"); value != "" {
+		category = &value
+	}
+
+	standings, err := h.seriesService.GetStandings(seriesID, category)
+	if err != nil {
+		respondSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, standings)
+}
+
+// resolveSeriesID acepta el ID o el slug del campeonato, igual que las rutas de eventos
+func (h *SeriesHandler) resolveSeriesID(c *gin.Context) (string, bool) {
+	seriesParam := c.Param("id")
+
+	if primitive.IsValidObjectID(seriesParam) {
+		return seriesParam, true
+	}
+
+	if !utils.IsValidSlug(seriesParam) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid series identifier"})
+		return "", false
+	}
+
+	series, err := h.seriesService.GetSeriesBySlug(seriesParam)
+	if err != nil {
+		respondSeriesError(c, err)
+		return "", false
+	}
+	return series.ID.Hex(), true
+}
+
+func respondSeriesError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidObjectID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid series id"})
+	} else if errors.Is(err, services.ErrInvalidSeries) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	} else if errors.Is(err, services.ErrSeriesNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
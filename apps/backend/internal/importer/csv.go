@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"backend/internal/racecheck"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CSV imports delimited text files with a header row. Each row is a result;
+// rows are grouped into races by the CARRERA column, or by MODALIDAD.
+type CSV struct{}
+
+func (CSV) Format() string { return FormatCSV }
+
+func (CSV) Extensions() []string { return []string{".csv", ".txt", ".tsv"} }
+
+func (CSV) Import(r io.Reader, opts Options) (*racecheck.File, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	decoded, encoding, err := racecheck.DecodeText(content)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode file: %w", err)
+	}
+
+	delimiter := opts.Delimiter
+	if delimiter == 0 {
+		delimiter = detectDelimiter(decoded)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(decoded))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not read CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("CSV file is empty")
+	}
+
+	f, err := fromTable(records[0], records[1:], 1, opts)
+	if err != nil {
+		return nil, err
+	}
+	f.Encoding = encoding
+	return f, nil
+}
+
+// detectDelimiter picks the most frequent of ';', ',' and tab in the header line.
+// Spanish spreadsheet exports use ';' because ',' is the decimal separator.
+func detectDelimiter(content []byte) rune {
+	header := string(content)
+	if i := strings.IndexAny(header, "\r\n"); i >= 0 {
+		header = header[:i]
+	}
+
+	best, bestCount := ',', 0
+	for _, candidate := range []rune{';', ',', '\t'} {
+		if count := strings.Count(header, string(candidate)); count > bestCount {
+			best, bestCount = candidate, count
+		}
+	}
+	return best
+}
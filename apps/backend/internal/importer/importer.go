@@ -0,0 +1,376 @@
+// Package importer converts result files exported by other timing systems into
+// the racecheck model, so that they are validated, versioned and published
+// through the same path as racecheck uploads.
+package importer
+
+import (
+	"backend/internal/racecheck"
+	"backend/internal/utils"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Formats registered by DefaultRegistry. FormatRaceCheck is handled by the
+// racecheck parser directly and has no Importer.
+const (
+	FormatRaceCheck = "racecheck"
+	FormatCSV       = "csv"
+	FormatXLSX      = "xlsx"
+	FormatIOFXML    = "iofxml"
+)
+
+// Standard racecheck columns, in the order the timing software writes them.
+const (
+	ColumnSex              = "SEXO"
+	ColumnName             = "NOMBRE"
+	ColumnChip             = "CHIP"
+	ColumnBib              = "DORSAL"
+	ColumnModality         = "MODALIDAD"
+	ColumnCategory         = "CATEGORIA"
+	ColumnTime             = "TIEMPO"
+	ColumnPosition         = "POSICION"
+	ColumnCategoryPosition = "POS.CAT."
+	ColumnPace             = "RITMO"
+	// ColumnRace groups the rows of a table into races. It is not written to
+	// the result columns. When a table has no race column, rows are grouped by
+	// MODALIDAD.
+	ColumnRace = "CARRERA"
+	// ColumnClub holds the club or team of the participant. It is optional, so
+	// it is written as an extra column and only when the source has one.
+	ColumnClub = "CLUB"
+	// ColumnSplitPrefix and ColumnLapPrefix name the optional split columns,
+	// numbered from 1: SPLIT_n holds the elapsed time at checkpoint n and
+	// VUELTA_n the duration of lap n. Like CLUB, they are extra columns.
+	ColumnSplitPrefix = "SPLIT_"
+	ColumnLapPrefix   = "VUELTA_"
+)
+
+// splitHeaderPattern matches split headers normalized with utils.NormalizeKey,
+// e.g. "SPLIT_1", "Parcial 2", "PC3", "Vuelta 1" or "LAP 4".
+var splitHeaderPattern = regexp.MustCompile(`^(SPLIT|PARCIAL|PC|VUELTA|LAP)(\d+)$`)
+
+// ParseSplitColumn reports whether header names a split column, whether it is
+// a lap rather than a checkpoint, and its number.
+func ParseSplitColumn(header string) (lap bool, number int, ok bool) {
+	matches := splitHeaderPattern.FindStringSubmatch(utils.NormalizeKey(header))
+	if matches == nil {
+		return false, 0, false
+	}
+	number, err := strconv.Atoi(matches[2])
+	if err != nil || number < 1 {
+		return false, 0, false
+	}
+	lap = matches[1] == "VUELTA" || matches[1] == "LAP"
+	return lap, number, true
+}
+
+// splitColumnName returns the standard name of a split column.
+func splitColumnName(lap bool, number int) string {
+	if lap {
+		return ColumnLapPrefix + strconv.Itoa(number)
+	}
+	return ColumnSplitPrefix + strconv.Itoa(number)
+}
+
+// StandardColumns are the columns every imported race has, followed by any
+// extra columns found in the source file.
+var StandardColumns = []string{
+	ColumnSex, ColumnName, ColumnChip, ColumnBib, ColumnModality,
+	ColumnCategory, ColumnTime, ColumnPosition, ColumnCategoryPosition, ColumnPace,
+}
+
+// ErrUnknownFormat is returned by Registry lookups when no importer matches.
+var ErrUnknownFormat = errors.New("unknown import format")
+
+// headerAliases maps source headers, normalized with utils.NormalizeKey, to the
+// standard columns.
+var headerAliases = map[string]string{
+	"SEXO": ColumnSex, "SEX": ColumnSex, "GENERO": ColumnSex, "GENDER": ColumnSex,
+
+	"NOMBRE": ColumnName, "NAME": ColumnName, "PARTICIPANTE": ColumnName,
+	"ATLETA": ColumnName, "ATHLETE": ColumnName, "CORREDOR": ColumnName,
+
+	"CHIP": ColumnChip, "TAG": ColumnChip, "CONTROLCARD": ColumnChip,
+
+	"DORSAL": ColumnBib, "BIB": ColumnBib, "NUMERO": ColumnBib,
+
+	"MODALIDAD": ColumnModality, "DISTANCIA": ColumnModality, "DISTANCE": ColumnModality,
+
+	"CATEGORIA": ColumnCategory, "CATEGORY": ColumnCategory,
+
+	"TIEMPO": ColumnTime, "TIME": ColumnTime, "TIEMPONETO": ColumnTime, "NETTIME": ColumnTime,
+
+	"POSICION": ColumnPosition, "POSITION": ColumnPosition, "POS": ColumnPosition,
+	"PLACE": ColumnPosition, "LUGAR": ColumnPosition,
+
+	"POSCAT": ColumnCategoryPosition, "POSICIONCATEGORIA": ColumnCategoryPosition,
+	"CATEGORYPOSITION": ColumnCategoryPosition, "CATPOS": ColumnCategoryPosition,
+
+	"RITMO": ColumnPace, "PACE": ColumnPace,
+
+	"CARRERA": ColumnRace, "RACE": ColumnRace,
+
+	"CLUB": ColumnClub, "EQUIPO": ColumnClub, "TEAM": ColumnClub, "CLUBEQUIPO": ColumnClub,
+	"ORGANISATION": ColumnClub, "ORGANIZATION": ColumnClub,
+}
+
+// Options configures an import. The zero value detects everything.
+type Options struct {
+	// Delimiter separates CSV columns. When zero it is detected from the header
+	// line among ';', ',' and tab.
+	Delimiter rune
+	// ColumnMapping maps source headers to standard columns, e.g.
+	// {"Apellidos y nombre": "NOMBRE"}. It takes precedence over the built-in
+	// aliases. Mapping a header to "" drops the column.
+	ColumnMapping map[string]string
+	// EventName is used as the event header when the file does not carry one.
+	EventName string
+}
+
+// Importer converts a result file in a given format into a racecheck file.
+type Importer interface {
+	// Format is the name used to select the importer explicitly.
+	Format() string
+	// Extensions are the file extensions, including the dot, that select the
+	// importer when no format is given.
+	Extensions() []string
+	Import(r io.Reader, opts Options) (*racecheck.File, error)
+}
+
+// Registry looks up importers by format name or file extension.
+type Registry struct {
+	byFormat    map[string]Importer
+	byExtension map[string]Importer
+}
+
+// NewRegistry returns a registry with the given importers. Later importers win
+// when two claim the same format or extension.
+func NewRegistry(importers ...Importer) *Registry {
+	r := &Registry{
+		byFormat:    make(map[string]Importer),
+		byExtension: make(map[string]Importer),
+	}
+	for _, imp := range importers {
+		r.Register(imp)
+	}
+	return r
+}
+
+// DefaultRegistry returns a registry with the CSV, XLSX and IOF XML importers.
+func DefaultRegistry() *Registry {
+	return NewRegistry(CSV{}, XLSX{}, IOFXML{})
+}
+
+// Register adds an importer to the registry.
+func (r *Registry) Register(imp Importer) {
+	r.byFormat[strings.ToLower(imp.Format())] = imp
+	for _, ext := range imp.Extensions() {
+		r.byExtension[strings.ToLower(ext)] = imp
+	}
+}
+
+// ForFormat returns the importer registered for format.
+func (r *Registry) ForFormat(format string) (Importer, error) {
+	if imp, ok := r.byFormat[strings.ToLower(strings.TrimSpace(format))]; ok {
+		return imp, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+}
+
+// ForExtension returns the importer registered for the file extension.
+func (r *Registry) ForExtension(ext string) (Importer, error) {
+	if imp, ok := r.byExtension[strings.ToLower(ext)]; ok {
+		return imp, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, ext)
+}
+
+// Formats returns the registered format names, sorted.
+func (r *Registry) Formats() []string {
+	formats := make([]string, 0, len(r.byFormat))
+	for format := range r.byFormat {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// tableColumn is where a source column ends up in the imported races.
+type tableColumn struct {
+	standard string
+	extra    string
+	// split marks extra columns holding split times, normalized like TIEMPO
+	split bool
+}
+
+// resolveHeader maps each source header to a standard column or keeps it as an
+// extra column with its original name in upper case.
+func resolveHeader(header []string, mapping map[string]string) ([]tableColumn, error) {
+	normalizedMapping := make(map[string]string, len(mapping))
+	for source, target := range mapping {
+		normalizedMapping[utils.NormalizeKey(source)] = target
+	}
+
+	columns := make([]tableColumn, len(header))
+	seen := make(map[string]bool)
+	used := make(map[string]bool, len(StandardColumns))
+	for _, c := range StandardColumns {
+		used[c] = true
+	}
+	for i, h := range header {
+		key := utils.NormalizeKey(h)
+		if key == "" {
+			continue
+		}
+
+		standard, mapped := normalizedMapping[key]
+		if mapped {
+			if standard == "" {
+				continue
+			}
+			target, ok := headerAliases[utils.NormalizeKey(standard)]
+			if !ok {
+				return nil, fmt.Errorf("column mapping for %q: unknown column %q", h, standard)
+			}
+			standard = target
+		} else {
+			standard = headerAliases[key]
+		}
+
+		if standard != "" && standard != ColumnClub && !seen[standard] {
+			seen[standard] = true
+			columns[i] = tableColumn{standard: standard}
+			continue
+		}
+		name := strings.ToUpper(strings.TrimSpace(h))
+		if standard == ColumnClub && !seen[ColumnClub] {
+			seen[ColumnClub] = true
+			name = ColumnClub
+		}
+		lap, number, split := ParseSplitColumn(h)
+		if split && standard == "" {
+			name = splitColumnName(lap, number)
+		}
+		// Repeated headers are kept with a suffix so they do not overwrite another column
+		extra := name
+		for n := 2; used[extra]; n++ {
+			extra = fmt.Sprintf("%s_%d", name, n)
+		}
+		used[extra] = true
+		columns[i] = tableColumn{extra: extra, split: split && standard == ""}
+	}
+
+	if !seen[ColumnName] {
+		return nil, fmt.Errorf("missing required column %s", ColumnName)
+	}
+	return columns, nil
+}
+
+// fromTable builds a racecheck file from a header row and data rows. firstLine
+// is the source line of the header, so row lines point back into the file.
+func fromTable(header []string, rows [][]string, firstLine int, opts Options) (*racecheck.File, error) {
+	columns, err := resolveHeader(header, opts.ColumnMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	var extras []string
+	for _, col := range columns {
+		if col.extra != "" {
+			extras = append(extras, col.extra)
+		}
+	}
+	raceColumns := append(append([]string{}, StandardColumns...), extras...)
+
+	index := make(map[string]int, len(raceColumns))
+	for i, c := range raceColumns {
+		index[c] = i
+	}
+
+	f := &racecheck.File{
+		Header: racecheck.EventHeader{Prefix: "1", Name: opts.EventName, Line: firstLine},
+		Lines:  firstLine + len(rows),
+	}
+	races := make(map[string]*racecheck.Race)
+
+	for i, source := range rows {
+		line := firstLine + i + 1
+		values := make([]string, len(raceColumns))
+		raceName := ""
+		empty := true
+		for j, col := range columns {
+			if j >= len(source) {
+				break
+			}
+			value := strings.TrimSpace(source[j])
+			if value != "" {
+				empty = false
+			}
+			switch {
+			case col.standard == ColumnRace:
+				raceName = value
+			case col.standard == ColumnTime:
+				values[index[ColumnTime]] = normalizeTime(value)
+			case col.standard != "":
+				values[index[col.standard]] = value
+			case col.split:
+				values[index[col.extra]] = normalizeTime(value)
+			case col.extra != "":
+				values[index[col.extra]] = value
+			}
+		}
+		if empty {
+			continue
+		}
+		if raceName == "" {
+			raceName = values[index[ColumnModality]]
+		}
+
+		race, ok := races[raceName]
+		if !ok {
+			race = &racecheck.Race{
+				Number:     len(f.Races) + 1,
+				Name:       raceName,
+				Line:       line,
+				Columns:    raceColumns,
+				HeaderLine: firstLine,
+			}
+			races[raceName] = race
+			f.Races = append(f.Races, race)
+		}
+		race.Rows = append(race.Rows, racecheck.Row{Line: line, Values: values})
+	}
+
+	return f, nil
+}
+
+// normalizeTime converts spreadsheet times, stored as a fraction of a day, to
+// HH:MM:SS. Other values are returned unchanged.
+func normalizeTime(value string) string {
+	if strings.Contains(value, ":") {
+		return value
+	}
+	days, err := strconv.ParseFloat(value, 64)
+	if err != nil || days <= 0 || days >= 1 {
+		return value
+	}
+	return formatSeconds(days * 24 * 60 * 60)
+}
+
+// formatSeconds formats a duration in seconds as HH:MM:SS, keeping tenths when
+// the duration is not a whole number of seconds.
+func formatSeconds(seconds float64) string {
+	tenths := int64(math.Round(seconds * 10))
+	whole := tenths / 10
+	text := fmt.Sprintf("%02d:%02d:%02d", whole/3600, whole/60%60, whole%60)
+	if tenths%10 != 0 {
+		text += fmt.Sprintf(".%d", tenths%10)
+	}
+	return text
+}
@@ -0,0 +1,317 @@
+package importer
+
+import (
+	"archive/zip"
+	"backend/internal/racecheck"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCSVImport(t *testing.T) {
+	input := "Carrera;Nombre;Dorsal;Sexo;Categoría;Tiempo;Lugar;Club\r\n" +
+		"10K;Cristobal Inostroza;121;M;Senior A Varones;00:33:37;1;Runners\r\n" +
+		"5K;Ana Muñoz;45;F;Damas;00:21:10;1;\r\n" +
+		"10K;Benjamin Ruiz;104;M;Todo Competidor;00:34:40;2;\r\n"
+
+	f, err := CSV{}.Import(strings.NewReader(input), Options{EventName: "Corrida"})
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+
+	if f.Header.Name != "Corrida" {
+		t.Errorf("event name = %q, expected Corrida", f.Header.Name)
+	}
+	if len(f.Races) != 2 || f.Races[0].Name != "10K" || f.Races[1].Name != "5K" {
+		t.Fatalf("unexpected races: %+v", f.Races)
+	}
+	if len(f.Races[0].Rows) != 2 || f.RowCount() != 3 {
+		t.Errorf("10K has %d rows and the file %d, expected 2 and 3", len(f.Races[0].Rows), f.RowCount())
+	}
+
+	race := f.Races[1]
+	row := race.RowMap(race.Rows[0])
+	if row[ColumnName] != "Ana Muñoz" || row[ColumnBib] != "45" || row[ColumnTime] != "00:21:10" || row[ColumnPosition] != "1" {
+		t.Errorf("unexpected row: %v", row)
+	}
+	if _, ok := row["CLUB"]; !ok {
+		t.Errorf("extra column CLUB missing: %v", race.Columns)
+	}
+	if _, ok := row[ColumnRace]; ok {
+		t.Errorf("race column should not be a result column: %v", race.Columns)
+	}
+	if race.Rows[0].Line != 3 {
+		t.Errorf("row line = %d, expected 3", race.Rows[0].Line)
+	}
+}
+
+func TestCSVImportWithMappingAndDelimiter(t *testing.T) {
+	input := "Apellidos y nombre|Nro|Distancia|Marca\nAna Muñoz|45|5K|21:10\n"
+	mapping := map[string]string{"Apellidos y nombre": "NOMBRE", "Nro": "DORSAL", "Marca": "TIEMPO"}
+
+	f, err := CSV{}.Import(strings.NewReader(input), Options{Delimiter: '|', ColumnMapping: mapping})
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+
+	race := f.Races[0]
+	row := race.RowMap(race.Rows[0])
+	if race.Name != "5K" || row[ColumnName] != "Ana Muñoz" || row[ColumnBib] != "45" || row[ColumnTime] != "21:10" {
+		t.Errorf("unexpected race %q and row %v", race.Name, row)
+	}
+}
+
+func TestCSVImportTeamColumn(t *testing.T) {
+	input := "Nombre;Equipo;Club\nAna Muñoz;Runners;Club Atlético\n"
+
+	f, err := CSV{}.Import(strings.NewReader(input), Options{})
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+
+	// The first club column is renamed to CLUB; a second one is kept as is
+	race := f.Races[0]
+	row := race.RowMap(race.Rows[0])
+	if row[ColumnClub] != "Runners" || row["CLUB_2"] != "Club Atlético" {
+		t.Errorf("unexpected row: %v", row)
+	}
+}
+
+func TestCSVImportSplitColumns(t *testing.T) {
+	input := "Nombre;Parcial 1;PC2;Vuelta 1;Tiempo\nAna Muñoz;0.0138888889;00:40:00;00:25:00;01:00:00\n"
+
+	f, err := CSV{}.Import(strings.NewReader(input), Options{})
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+
+	// Split headers are renamed to SPLIT_n and VUELTA_n and spreadsheet times normalized
+	race := f.Races[0]
+	row := race.RowMap(race.Rows[0])
+	if row["SPLIT_1"] != "00:20:00" || row["SPLIT_2"] != "00:40:00" || row["VUELTA_1"] != "00:25:00" {
+		t.Errorf("unexpected row: %v", row)
+	}
+}
+
+func TestParseSplitColumn(t *testing.T) {
+	tests := []struct {
+		header string
+		lap    bool
+		number int
+		ok     bool
+	}{
+		{"SPLIT_1", false, 1, true},
+		{"Parcial 12", false, 12, true},
+		{"VUELTA_3", true, 3, true},
+		{"Lap 2", true, 2, true},
+		{"SPLIT_0", false, 0, false},
+		{"SPLIT", false, 0, false},
+		{"TIEMPO", false, 0, false},
+	}
+	for _, tt := range tests {
+		lap, number, ok := ParseSplitColumn(tt.header)
+		if lap != tt.lap || number != tt.number || ok != tt.ok {
+			t.Errorf("ParseSplitColumn(%q) = %v, %d, %v, expected %v, %d, %v", tt.header, lap, number, ok, tt.lap, tt.number, tt.ok)
+		}
+	}
+}
+
+func TestCSVImportWindows1252(t *testing.T) {
+	input := []byte("NOMBRE,TIEMPO\nAna Mu\xf1oz,00:21:10\n")
+
+	f, err := CSV{}.Import(bytes.NewReader(input), Options{})
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+	if f.Encoding != racecheck.EncodingWindows1252 {
+		t.Errorf("encoding = %q, expected %q", f.Encoding, racecheck.EncodingWindows1252)
+	}
+	if name, _ := f.Races[0].Value(f.Races[0].Rows[0], ColumnName); name != "Ana Muñoz" {
+		t.Errorf("name = %q, expected Ana Muñoz", name)
+	}
+}
+
+func TestCSVImportRequiresName(t *testing.T) {
+	_, err := CSV{}.Import(strings.NewReader("DORSAL;TIEMPO\n1;00:20:00\n"), Options{})
+	if err == nil {
+		t.Fatal("expected an error for a file without a name column")
+	}
+}
+
+// xlsxTestWorkbook builds a workbook whose first sheet has the given sheetData rows
+func xlsxTestWorkbook(t *testing.T, sheetRows string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Resultados" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="worksheet" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":     `<sst><si><t>NOMBRE</t></si><si><t>TIEMPO</t></si><si><r><t>Ana </t></r><r><t>Muñoz</t></r></si><si><t>MODALIDAD</t></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>` + sheetRows + `</sheetData></worksheet>`,
+	}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestXLSXImport(t *testing.T) {
+	buf := xlsxTestWorkbook(t,
+		`<row r="2"><c r="A2" t="s"><v>0</v></c><c r="B2" t="s"><v>1</v></c><c r="D2" t="s"><v>3</v></c></row>`+
+			`<row r="3"><c r="A3" t="s"><v>2</v></c><c r="B3"><v>0.014699074074074074</v></c><c r="D3" t="inlineStr"><is><t>5K</t></is></c></row>`)
+
+	f, err := XLSX{}.Import(buf, Options{EventName: "Corrida"})
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+
+	race := f.Races[0]
+	row := race.RowMap(race.Rows[0])
+	if race.Name != "5K" || row[ColumnName] != "Ana Muñoz" || row[ColumnTime] != "00:21:10" {
+		t.Errorf("unexpected race %q and row %v", race.Name, row)
+	}
+	if race.Rows[0].Line != 3 {
+		t.Errorf("row line = %d, expected 3", race.Rows[0].Line)
+	}
+}
+
+func TestXLSXImportRejectsOutOfRangeCells(t *testing.T) {
+	tests := []struct {
+		name string
+		rows string
+		err  string
+	}{
+		{"overflowing column", `<row r="1"><c r="ZZZZZZZZZZZZZZZ1" t="inlineStr"><is><t>NOMBRE</t></is></c></row>`, "beyond the worksheet limit"},
+		{"column out of range", `<row r="1"><c r="XFE1" t="inlineStr"><is><t>NOMBRE</t></is></c></row>`, "beyond the worksheet limit"},
+		{"row out of range", `<row r="1048577"><c r="A1048577" t="inlineStr"><is><t>NOMBRE</t></is></c></row>`, "beyond the worksheet limit"},
+		{"reference without column", `<row r="1"><c r="12" t="inlineStr"><is><t>NOMBRE</t></is></c></row>`, "invalid cell reference"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := XLSX{}.Import(xlsxTestWorkbook(t, tt.rows), Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Import error = %v, expected it to mention %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestXLSXImportLastColumn(t *testing.T) {
+	// XFD is the last column Excel allows
+	f, err := XLSX{}.Import(xlsxTestWorkbook(t,
+		`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="XFD1" t="inlineStr"><is><t>NOTA</t></is></c></row>`+
+			`<row r="2"><c r="A2" t="inlineStr"><is><t>Ana</t></is></c></row>`), Options{EventName: "Corrida"})
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+	if row := f.Races[0].RowMap(f.Races[0].Rows[0]); row[ColumnName] != "Ana" {
+		t.Errorf("unexpected row %v", row)
+	}
+}
+
+func TestIOFXMLImport(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<ResultList xmlns="http://www.orienteering.org/datastandard/3.0" iofVersion="3.0">
+  <Event><Name>Trail Maipo</Name></Event>
+  <ClassResult>
+    <Class sex="F"><Name>Damas 21K</Name></Class>
+    <PersonResult>
+      <Person><Name><Family>Muñoz</Family><Given>Ana</Given></Name></Person>
+      <Organisation><Name>Runners</Name></Organisation>
+      <Result><BibNumber>45</BibNumber><Time>7384.5</Time><Position>1</Position><Status>OK</Status><ControlCard>QT045</ControlCard>
+        <SplitTime><ControlCode>31</ControlCode><Time>1800</Time></SplitTime>
+        <SplitTime status="Missing"><ControlCode>32</ControlCode></SplitTime>
+      </Result>
+    </PersonResult>
+    <PersonResult>
+      <Person sex="F"><Name><Family>Rojas</Family><Given>Paula</Given></Name></Person>
+      <Result><BibNumber>46</BibNumber><Status>DidNotFinish</Status></Result>
+    </PersonResult>
+  </ClassResult>
+</ResultList>`
+
+	f, err := IOFXML{}.Import(strings.NewReader(input), Options{EventName: "ignored"})
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+
+	if f.Header.Name != "Trail Maipo" || len(f.Races) != 1 || f.Races[0].Name != "Damas 21K" {
+		t.Fatalf("unexpected file: %+v", f)
+	}
+	race := f.Races[0]
+	first := race.RowMap(race.Rows[0])
+	if first[ColumnName] != "Ana Muñoz" || first[ColumnSex] != "F" || first[ColumnChip] != "QT045" ||
+		first[ColumnTime] != "02:03:04.5" || first[ColumnPosition] != "1" || first[ColumnClub] != "Runners" {
+		t.Errorf("unexpected first row: %v", first)
+	}
+	if first["SPLIT_1"] != "00:30:00" || first["SPLIT_2"] != "" {
+		t.Errorf("unexpected split times: %v", first)
+	}
+	second := race.RowMap(race.Rows[1])
+	if second[ColumnPosition] != "DNF" || second[ColumnTime] != "" || second["SPLIT_1"] != "" {
+		t.Errorf("unexpected second row: %v", second)
+	}
+}
+
+func TestIOFXMLImportStatus(t *testing.T) {
+	tests := []struct {
+		status   string
+		position string
+		time     string
+	}{
+		{"OK", "", "01:00:00"},
+		{"DidNotStart", "DNS", ""},
+		{"DidNotFinish", "DNF", ""},
+		{"MissingPunch", "DSQ", ""},
+		{"Disqualified", "DSQ", ""},
+		{"OverTime", "OTL", ""},
+		{"NotCompeting", "NC", "01:00:00"},
+		{"Moved", "MOVED", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			input := `<ResultList><Event><Name>Trail</Name></Event><ClassResult><Class><Name>21K</Name></Class>
+<PersonResult><Person><Name><Family>Rojas</Family><Given>Paula</Given></Name></Person>
+<Result><BibNumber>46</BibNumber><Time>3600</Time><Status>` + tt.status + `</Status></Result></PersonResult>
+</ClassResult></ResultList>`
+
+			f, err := IOFXML{}.Import(strings.NewReader(input), Options{})
+			if err != nil {
+				t.Fatalf("Import returned error: %v", err)
+			}
+			row := f.Races[0].RowMap(f.Races[0].Rows[0])
+			if row[ColumnPosition] != tt.position {
+				t.Errorf("position = %q, expected %q", row[ColumnPosition], tt.position)
+			}
+			if row[ColumnTime] != tt.time {
+				t.Errorf("time = %q, expected %q", row[ColumnTime], tt.time)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	registry := DefaultRegistry()
+
+	if imp, err := registry.ForExtension(".CSV"); err != nil || imp.Format() != FormatCSV {
+		t.Errorf("ForExtension(.CSV) = %v, %v", imp, err)
+	}
+	if imp, err := registry.ForFormat("iofxml"); err != nil || imp.Format() != FormatIOFXML {
+		t.Errorf("ForFormat(iofxml) = %v, %v", imp, err)
+	}
+	if _, err := registry.ForExtension(".pdf"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("ForExtension(.pdf) error = %v, expected ErrUnknownFormat", err)
+	}
+}
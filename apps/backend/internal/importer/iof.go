@@ -0,0 +1,171 @@
+package importer
+
+import (
+	"backend/internal/racecheck"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// IOFXML imports IOF XML 3.0 ResultList documents, the interchange format of
+// orienteering and trail timing software. Each ClassResult becomes a race and
+// the class name is used as modality and category.
+type IOFXML struct{}
+
+func (IOFXML) Format() string { return FormatIOFXML }
+
+func (IOFXML) Extensions() []string { return []string{".xml"} }
+
+type iofResultList struct {
+	XMLName      xml.Name `xml:"ResultList"`
+	EventName    string   `xml:"Event>Name"`
+	ClassResults []struct {
+		Class struct {
+			Name string `xml:"Name"`
+			Sex  string `xml:"sex,attr"`
+		} `xml:"Class"`
+		PersonResults []struct {
+			Person struct {
+				Sex    string `xml:"sex,attr"`
+				Family string `xml:"Name>Family"`
+				Given  string `xml:"Name>Given"`
+			} `xml:"Person"`
+			Organisation string `xml:"Organisation>Name"`
+			Result       struct {
+				BibNumber   string `xml:"BibNumber"`
+				Time        string `xml:"Time"`
+				Position    string `xml:"Position"`
+				Status      string `xml:"Status"`
+				ControlCard string `xml:"ControlCard"`
+				SplitTimes  []struct {
+					Time string `xml:"Time"`
+				} `xml:"SplitTime"`
+			} `xml:"Result"`
+		} `xml:"PersonResult"`
+	} `xml:"ClassResult"`
+}
+
+// iofStatusCodes are the position codes used for IOF result statuses other
+// than OK. NotCompeting keeps its own code and its time: the runner finished,
+// so it is not a result status, but it has no position and stays out of the
+// rankings of files that carry positions.
+var iofStatusCodes = map[string]string{
+	"DidNotStart":        "DNS",
+	"DidNotFinish":       "DNF",
+	"Disqualified":       "DSQ",
+	"MissingPunch":       "DSQ",
+	"OverTime":           "OTL",
+	"NotCompeting":       "NC",
+	"DidNotEnter":        "DNS",
+	"Cancelled":          "DNS",
+	"SportingWithdrawal": "DNF",
+}
+
+func (IOFXML) Import(r io.Reader, opts Options) (*racecheck.File, error) {
+	var list iofResultList
+	if err := xml.NewDecoder(r).Decode(&list); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("XML file is empty")
+		}
+		return nil, fmt.Errorf("could not read IOF XML: %w", err)
+	}
+	if list.XMLName.Local != "ResultList" {
+		return nil, fmt.Errorf("unsupported IOF XML document %q, expected ResultList", list.XMLName.Local)
+	}
+
+	eventName := strings.TrimSpace(list.EventName)
+	if eventName == "" {
+		eventName = opts.EventName
+	}
+
+	f := &racecheck.File{
+		Header:   racecheck.EventHeader{Prefix: "1", Name: eventName, Line: 1},
+		Encoding: racecheck.EncodingUTF8,
+	}
+
+	// IOF XML has no meaningful line numbers; rows are numbered in document
+	// order so validation issues can still be told apart.
+	line := 1
+	for i, class := range list.ClassResults {
+		className := strings.TrimSpace(class.Class.Name)
+		line++
+
+		// Split times are elapsed times at each control, written as SPLIT_n columns
+		splits := 0
+		for _, pr := range class.PersonResults {
+			if len(pr.Result.SplitTimes) > splits {
+				splits = len(pr.Result.SplitTimes)
+			}
+		}
+		columns := append(append([]string{}, StandardColumns...), ColumnClub)
+		for n := 1; n <= splits; n++ {
+			columns = append(columns, splitColumnName(false, n))
+		}
+
+		race := &racecheck.Race{
+			Number:     i + 1,
+			Name:       className,
+			Line:       line,
+			Columns:    columns,
+			HeaderLine: line,
+		}
+
+		for _, pr := range class.PersonResults {
+			line++
+			sex := pr.Person.Sex
+			if sex == "" {
+				sex = class.Class.Sex
+			}
+
+			position := strings.TrimSpace(pr.Result.Position)
+			timeText := ""
+			status := strings.TrimSpace(pr.Result.Status)
+			if status == "" || status == "OK" || status == "NotCompeting" {
+				if seconds, err := strconv.ParseFloat(strings.TrimSpace(pr.Result.Time), 64); err == nil && seconds > 0 {
+					timeText = formatSeconds(seconds)
+				}
+			}
+			if code, ok := iofStatusCodes[status]; ok {
+				position = code
+			} else if status != "" && status != "OK" {
+				position = strings.ToUpper(status)
+			}
+
+			values := []string{
+				sex,
+				personName(pr.Person.Family, pr.Person.Given),
+				strings.TrimSpace(pr.Result.ControlCard),
+				strings.TrimSpace(pr.Result.BibNumber),
+				className,
+				className,
+				timeText,
+				position,
+				position,
+				"",
+				strings.TrimSpace(pr.Organisation),
+			}
+			for n := 0; n < splits; n++ {
+				splitText := ""
+				if n < len(pr.Result.SplitTimes) {
+					if seconds, err := strconv.ParseFloat(strings.TrimSpace(pr.Result.SplitTimes[n].Time), 64); err == nil && seconds > 0 {
+						splitText = formatSeconds(seconds)
+					}
+				}
+				values = append(values, splitText)
+			}
+			race.Rows = append(race.Rows, racecheck.Row{Line: line, Values: values})
+		}
+		f.Races = append(f.Races, race)
+	}
+	f.Lines = line
+
+	return f, nil
+}
+
+// personName formats names as "Given Family", like the timing software exports.
+func personName(family, given string) string {
+	return strings.TrimSpace(strings.TrimSpace(given) + " " + strings.TrimSpace(family))
+}
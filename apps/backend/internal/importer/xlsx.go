@@ -0,0 +1,269 @@
+package importer
+
+import (
+	"archive/zip"
+	"backend/internal/racecheck"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Limits of a worksheet as defined by Excel. Uploads are untrusted, so larger
+// row or column numbers are rejected before any padding is allocated.
+const (
+	xlsxMaxRows    = 1048576
+	xlsxMaxColumns = 16384
+)
+
+// maxXLSXEntrySize bounds the decompressed size of each workbook part read, so
+// a small zip cannot expand into gigabytes of XML.
+const maxXLSXEntrySize = 64 << 20
+
+// XLSX imports the first worksheet of an Office Open XML workbook. The sheet is
+// read like a CSV file: the first non-empty row is the header.
+type XLSX struct{}
+
+func (XLSX) Format() string { return FormatXLSX }
+
+func (XLSX) Extensions() []string { return []string{".xlsx"} }
+
+func (XLSX) Import(r io.Reader, opts Options) (*racecheck.File, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("could not open workbook: %w", err)
+	}
+
+	sheetPath, err := firstSheetPath(zr)
+	if err != nil {
+		return nil, err
+	}
+	sharedStrings, err := readSharedStrings(zr)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := readSheetRows(zr, sheetPath, sharedStrings)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip blank rows above the header
+	headerIndex := -1
+	for i, row := range rows {
+		if !isEmptyRow(row) {
+			headerIndex = i
+			break
+		}
+	}
+	if headerIndex == -1 {
+		return nil, errors.New("worksheet is empty")
+	}
+
+	f, err := fromTable(rows[headerIndex], rows[headerIndex+1:], headerIndex+1, opts)
+	if err != nil {
+		return nil, err
+	}
+	f.Encoding = racecheck.EncodingUTF8
+	return f, nil
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+// xlsxRichText is a string that is either plain (<t>) or split in runs (<r><t>).
+type xlsxRichText struct {
+	Text string   `xml:"t"`
+	Runs []string `xml:"r>t"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	return strings.Join(t.Runs, "")
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Index int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string       `xml:"r,attr"`
+			Type   string       `xml:"t,attr"`
+			Value  string       `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// firstSheetPath resolves the zip path of the first sheet in the workbook.
+func firstSheetPath(zr *zip.Reader) (string, error) {
+	var workbook xlsxWorkbook
+	if err := decodeZipXML(zr, "xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("workbook has no sheets")
+	}
+
+	var rels xlsxRelationships
+	if err := decodeZipXML(zr, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", fmt.Errorf("could not find sheet %q", workbook.Sheets[0].Name)
+}
+
+// readSharedStrings reads the shared string table. Workbooks without strings
+// have no table.
+func readSharedStrings(zr *zip.Reader) ([]string, error) {
+	var table xlsxSharedStrings
+	if err := decodeZipXML(zr, "xl/sharedStrings.xml", &table); err != nil {
+		if errors.Is(err, errZipEntryNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	values := make([]string, len(table.Items))
+	for i, item := range table.Items {
+		values[i] = item.String()
+	}
+	return values, nil
+}
+
+// readSheetRows reads the cell values of a sheet, placing each cell in its
+// column and keeping blank rows so row numbers match the spreadsheet.
+func readSheetRows(zr *zip.Reader, sheetPath string, sharedStrings []string) ([][]string, error) {
+	var sheet xlsxSheet
+	if err := decodeZipXML(zr, sheetPath, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, sheetRow := range sheet.Rows {
+		rowIndex := sheetRow.Index
+		if rowIndex <= 0 {
+			rowIndex = len(rows) + 1
+		}
+		if rowIndex > xlsxMaxRows {
+			return nil, fmt.Errorf("row %d is beyond the worksheet limit of %d rows", rowIndex, xlsxMaxRows)
+		}
+		for len(rows) < rowIndex {
+			rows = append(rows, nil)
+		}
+
+		var values []string
+		for i, cell := range sheetRow.Cells {
+			col := i
+			if cell.Ref != "" {
+				var err error
+				if col, err = columnIndex(cell.Ref); err != nil {
+					return nil, err
+				}
+			}
+			if col >= xlsxMaxColumns {
+				return nil, fmt.Errorf("row %d has more than %d columns", rowIndex, xlsxMaxColumns)
+			}
+			for len(values) <= col {
+				values = append(values, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				n, err := strconv.Atoi(cell.Value)
+				if err != nil || n < 0 || n >= len(sharedStrings) {
+					return nil, fmt.Errorf("cell %s: invalid shared string %q", cell.Ref, cell.Value)
+				}
+				values[col] = sharedStrings[n]
+			case "inlineStr":
+				values[col] = cell.Inline.String()
+			default:
+				values[col] = cell.Value
+			}
+		}
+		rows[rowIndex-1] = values
+	}
+	return rows, nil
+}
+
+// columnIndex returns the zero based column of a cell reference like "AB12".
+func columnIndex(ref string) (int, error) {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		// Checked on every letter so long references cannot overflow
+		if col > xlsxMaxColumns {
+			return 0, fmt.Errorf("cell %s is beyond the worksheet limit of %d columns", ref, xlsxMaxColumns)
+		}
+	}
+	if col == 0 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return col - 1, nil
+}
+
+func isEmptyRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+var errZipEntryNotFound = errors.New("entry not found")
+
+func decodeZipXML(zr *zip.Reader, name string, v interface{}) error {
+	for _, file := range zr.File {
+		if file.Name != name {
+			continue
+		}
+		if file.UncompressedSize64 > maxXLSXEntrySize {
+			return fmt.Errorf("%s is larger than %d bytes", name, maxXLSXEntrySize)
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("could not open %s: %w", name, err)
+		}
+		defer rc.Close()
+		// The declared size can lie, so the reader is limited as well
+		if err := xml.NewDecoder(io.LimitReader(rc, maxXLSXEntrySize)).Decode(v); err != nil {
+			return fmt.Errorf("could not read %s: %w", name, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s: %w", name, errZipEntryNotFound)
+}
@@ -0,0 +1,115 @@
+package racecheck
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Encodings reported by Parser.Encoding and File.Encoding.
+const (
+	EncodingUTF8        = "UTF-8"
+	EncodingUTF8BOM     = "UTF-8 (BOM)"
+	EncodingUTF16LE     = "UTF-16LE"
+	EncodingUTF16BE     = "UTF-16BE"
+	EncodingWindows1252 = "Windows-1252"
+)
+
+// maxLineSize is the longest line the parser accepts. Files with many extra
+// columns (splits, laps) easily exceed bufio.Scanner's 64KB default.
+const maxLineSize = 1 << 20
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// decodeReader strips a byte order mark and converts UTF-16 input to UTF-8. It
+// returns the reader to scan and the encoding implied by the BOM, or
+// EncodingUTF8 when there is none.
+func decodeReader(r io.Reader) (io.Reader, string) {
+	br := bufio.NewReader(r)
+	prefix, _ := br.Peek(3)
+
+	switch {
+	case bytes.HasPrefix(prefix, bomUTF8):
+		br.Discard(len(bomUTF8))
+		return br, EncodingUTF8BOM
+	case bytes.HasPrefix(prefix, bomUTF16LE):
+		return transform.NewReader(br, unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder()), EncodingUTF16LE
+	case bytes.HasPrefix(prefix, bomUTF16BE):
+		return transform.NewReader(br, unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder()), EncodingUTF16BE
+	}
+	return br, EncodingUTF8
+}
+
+// decodeLine returns the line as UTF-8. Lines that are not valid UTF-8 come from
+// the Windows timing software and are decoded as Windows-1252, a superset of
+// Latin-1, so names like "Muñoz" are preserved.
+func decodeLine(line []byte) (string, bool) {
+	if utf8.Valid(line) {
+		return string(line), false
+	}
+	decoded, err := charmap.Windows1252.NewDecoder().Bytes(line)
+	if err != nil {
+		return string(line), false
+	}
+	return string(decoded), true
+}
+
+// scanLines is a bufio.SplitFunc that accepts "\n", "\r\n" and lone "\r" line
+// endings and never returns the terminator as part of the line.
+func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		// "\r": look at the next byte to tell "\r\n" from a lone "\r"
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+			return i + 1, data[:i], nil
+		}
+		if atEOF {
+			return i + 1, data[:i], nil
+		}
+		// Need more data to decide
+		return 0, nil, nil
+	}
+
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// DecodeText converts a whole file to UTF-8 using the same detection as the
+// parser: a byte order mark selects UTF-8 or UTF-16, and content that is not
+// valid UTF-8 is decoded as Windows-1252. Importers for other formats use it so
+// that every upload reports its encoding the same way.
+func DecodeText(content []byte) ([]byte, string, error) {
+	r, encoding := decodeReader(bytes.NewReader(content))
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return nil, encoding, err
+	}
+	if encoding == EncodingUTF8 && !utf8.Valid(decoded) {
+		converted, err := charmap.Windows1252.NewDecoder().Bytes(decoded)
+		if err != nil {
+			return nil, encoding, err
+		}
+		return converted, EncodingWindows1252, nil
+	}
+	return decoded, encoding, nil
+}
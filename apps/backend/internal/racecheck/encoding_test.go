@@ -0,0 +1,89 @@
+package racecheck
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestParseDetectsEncoding(t *testing.T) {
+	original := readSample(t, "MEDIO MARATON SAN JOSE DE MAIPO.racecheck")
+	expected, err := Parse(bytes.NewReader(original))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if expected.Encoding != EncodingUTF8 {
+		t.Errorf("Encoding = %q, expected %q", expected.Encoding, EncodingUTF8)
+	}
+
+	windows1252, err := charmap.Windows1252.NewEncoder().Bytes(original)
+	if err != nil {
+		t.Fatalf("could not encode sample as Windows-1252: %v", err)
+	}
+	utf16, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes(original)
+	if err != nil {
+		t.Fatalf("could not encode sample as UTF-16: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		content  []byte
+		encoding string
+	}{
+		{"windows-1252", windows1252, EncodingWindows1252},
+		{"utf-8 bom", append([]byte{0xEF, 0xBB, 0xBF}, original...), EncodingUTF8BOM},
+		{"utf-16 bom", utf16, EncodingUTF16LE},
+		{"lf", bytes.ReplaceAll(original, []byte("\r\n"), []byte("\n")), EncodingUTF8},
+		{"lone cr", bytes.ReplaceAll(original, []byte("\r\n"), []byte("\r")), EncodingUTF8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(bytes.NewReader(tt.content))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if f.Encoding != tt.encoding {
+				t.Errorf("Encoding = %q, expected %q", f.Encoding, tt.encoding)
+			}
+			if f.Header != expected.Header {
+				t.Errorf("Header = %+v, expected %+v", f.Header, expected.Header)
+			}
+			if !reflect.DeepEqual(f.Races, expected.Races) {
+				t.Errorf("races differ from the UTF-8 parse")
+			}
+		})
+	}
+}
+
+func TestParseStripsCarriageReturnFromLastColumn(t *testing.T) {
+	content := "1|EVENTO\r\n;1|10K\r\n;SEXO|NOMBRE|RITMO\r\nM|Muñoz|04:00 min/Km\r\n"
+
+	f, err := Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if value, _ := f.Races[0].Value(f.Races[0].Rows[0], "RITMO"); value != "04:00 min/Km" {
+		t.Errorf("RITMO = %q, expected no trailing carriage return", value)
+	}
+}
+
+func TestParseLongLines(t *testing.T) {
+	extra := strings.Repeat("x", 100*1024)
+	content := "1|EVENTO\n;1|10K\n;SEXO|NOMBRE|NOTAS\nM|Ana|" + extra + "\n"
+
+	f, err := Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if f.RowCount() != 1 {
+		t.Fatalf("RowCount() = %d, expected 1", f.RowCount())
+	}
+	if value, _ := f.Races[0].Value(f.Races[0].Rows[0], "NOTAS"); len(value) != len(extra) {
+		t.Errorf("len(NOTAS) = %d, expected %d", len(value), len(extra))
+	}
+}
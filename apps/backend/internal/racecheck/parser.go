@@ -0,0 +1,209 @@
+package racecheck
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrNoHeader is returned when the input is empty.
+var ErrNoHeader = errors.New("racecheck file has no event header")
+
+// ItemKind identifies the type of an Item produced by the Parser.
+type ItemKind int
+
+const (
+	// ItemRace is a ";N|NAME" race line.
+	ItemRace ItemKind = iota
+	// ItemColumns is a ";SEXO|..." column header line.
+	ItemColumns
+	// ItemRow is a result row matching the current column header.
+	ItemRow
+	// ItemSkipped is a line that could not be interpreted.
+	ItemSkipped
+)
+
+// Item is a single element yielded by the Parser. Only the field matching Kind is set.
+type Item struct {
+	Kind    ItemKind
+	Race    *Race
+	Row     Row
+	Skipped SkippedLine
+}
+
+// Parser reads a racecheck file line by line.
+type Parser struct {
+	scanner  *bufio.Scanner
+	encoding string
+	header   *EventHeader
+	lineNum  int
+	race     *Race
+	err      error
+}
+
+// NewParser returns a streaming parser reading from r. The input may be UTF-8,
+// UTF-16 with a byte order mark or Windows-1252, with any line endings; lines
+// are always returned as UTF-8 without the terminator.
+func NewParser(r io.Reader) *Parser {
+	decoded, encoding := decodeReader(r)
+	scanner := bufio.NewScanner(decoded)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	scanner.Split(scanLines)
+	return &Parser{scanner: scanner, encoding: encoding}
+}
+
+// Encoding returns the encoding detected so far. A UTF-8 file becomes
+// EncodingWindows1252 once a line that is not valid UTF-8 has been read.
+func (p *Parser) Encoding() string {
+	return p.encoding
+}
+
+// text returns the current line decoded as UTF-8.
+func (p *Parser) text() string {
+	line, converted := decodeLine(p.scanner.Bytes())
+	if converted {
+		p.encoding = EncodingWindows1252
+	}
+	return line
+}
+
+// Header reads and returns the event header. It is called implicitly by Next.
+func (p *Parser) Header() (EventHeader, error) {
+	if p.header != nil {
+		return *p.header, nil
+	}
+	if p.err != nil {
+		return EventHeader{}, p.err
+	}
+
+	if !p.scanner.Scan() {
+		if err := p.scanner.Err(); err != nil {
+			p.err = fmt.Errorf("error reading file: %w", err)
+		} else {
+			p.err = ErrNoHeader
+		}
+		return EventHeader{}, p.err
+	}
+	p.lineNum++
+
+	header := parseHeader(p.text())
+	header.Line = p.lineNum
+	p.header = &header
+	return header, nil
+}
+
+// Next returns the next item in the file. It returns io.EOF when the input is
+// exhausted. Blank lines are ignored.
+func (p *Parser) Next() (Item, error) {
+	if _, err := p.Header(); err != nil {
+		return Item{}, err
+	}
+
+	for p.scanner.Scan() {
+		p.lineNum++
+		line := p.text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, ";SEXO|") {
+			columns := strings.Split(strings.TrimPrefix(line, ";"), "|")
+			if p.race == nil || p.race.Columns != nil {
+				// Columns without a preceding race line start an implicit race
+				p.race = &Race{Line: p.lineNum}
+			}
+			p.race.Columns = columns
+			p.race.HeaderLine = p.lineNum
+			return Item{Kind: ItemColumns, Race: p.race}, nil
+		}
+
+		if strings.HasPrefix(line, ";") && strings.Contains(line, "|") {
+			parts := strings.SplitN(strings.TrimPrefix(line, ";"), "|", 2)
+			number, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+			if err != nil {
+				p.race = nil
+				return p.skip(line, fmt.Sprintf("invalid race number %q", parts[0])), nil
+			}
+			p.race = &Race{Number: number, Name: strings.TrimSpace(parts[1]), Line: p.lineNum}
+			return Item{Kind: ItemRace, Race: p.race}, nil
+		}
+
+		if p.race == nil || p.race.Columns == nil {
+			return p.skip(line, "row appears before a column header"), nil
+		}
+
+		values := strings.Split(line, "|")
+		if len(values) != len(p.race.Columns) {
+			return p.skip(line, fmt.Sprintf("expected %d columns but found %d", len(p.race.Columns), len(values))), nil
+		}
+
+		return Item{Kind: ItemRow, Race: p.race, Row: Row{Line: p.lineNum, Values: values}}, nil
+	}
+
+	if err := p.scanner.Err(); err != nil {
+		p.err = fmt.Errorf("error reading file: %w", err)
+		return Item{}, p.err
+	}
+	return Item{}, io.EOF
+}
+
+// Lines returns the number of lines read so far.
+func (p *Parser) Lines() int {
+	return p.lineNum
+}
+
+func (p *Parser) skip(line, reason string) Item {
+	return Item{Kind: ItemSkipped, Skipped: SkippedLine{Line: p.lineNum, Reason: reason, Raw: line}}
+}
+
+// Parse reads the whole file into memory.
+func Parse(r io.Reader) (*File, error) {
+	p := NewParser(r)
+	header, err := p.Header()
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{Header: header}
+	var current *Race
+	for {
+		item, err := p.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch item.Kind {
+		case ItemRace, ItemColumns:
+			if item.Race != current {
+				current = item.Race
+				f.Races = append(f.Races, current)
+			}
+		case ItemRow:
+			current.Rows = append(current.Rows, item.Row)
+		case ItemSkipped:
+			f.Skipped = append(f.Skipped, item.Skipped)
+		}
+	}
+	f.Lines = p.Lines()
+	f.Encoding = p.Encoding()
+
+	return f, nil
+}
+
+// parseHeader splits the "1|EVENT NAME" header. A header without a pipe is
+// treated as the event name.
+func parseHeader(line string) EventHeader {
+	if idx := strings.Index(line, "|"); idx != -1 {
+		return EventHeader{
+			Prefix: strings.TrimSpace(line[:idx]),
+			Name:   strings.TrimSpace(line[idx+1:]),
+		}
+	}
+	return EventHeader{Name: strings.TrimSpace(line)}
+}
@@ -0,0 +1,147 @@
+package racecheck
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleDir = "../../../../data"
+
+func TestParseSampleFiles(t *testing.T) {
+	tests := []struct {
+		file      string
+		eventName string
+		races     int
+		rows      int
+		firstRace string
+	}{
+		{"CORRIDA CASABLANCA 2024.racecheck", "CORRIDA CASABLANCA 2024", 4, 257, "10K"},
+		{"MEDIO MARATON SAN JOSE DE MAIPO.racecheck", "MEDIO MARATON SAN JOSE DE MAIPO", 2, 123, "10K"},
+		{"2ra Fecha XCO Metropolitano 2025 (2).racecheck", "2ra Fecha XCO Metropolitano 2025", 34, 281, "CAD 3G"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data := readSample(t, tt.file)
+
+			f, err := Parse(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if f.Header.Name != tt.eventName {
+				t.Errorf("Header.Name = %q, expected %q", f.Header.Name, tt.eventName)
+			}
+			if len(f.Races) != tt.races {
+				t.Errorf("len(Races) = %d, expected %d", len(f.Races), tt.races)
+			}
+			if f.RowCount() != tt.rows {
+				t.Errorf("RowCount() = %d, expected %d", f.RowCount(), tt.rows)
+			}
+			if len(f.Skipped) != 0 {
+				t.Errorf("Skipped = %v, expected none", f.Skipped)
+			}
+			if f.Races[0].Number != 1 || f.Races[0].Name != tt.firstRace {
+				t.Errorf("first race = %d|%s, expected 1|%s", f.Races[0].Number, f.Races[0].Name, tt.firstRace)
+			}
+			for _, race := range f.Races {
+				for _, row := range race.Rows {
+					if len(row.Values) != len(race.Columns) {
+						t.Fatalf("line %d has %d values for %d columns", row.Line, len(row.Values), len(race.Columns))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(sampleDir, "*.racecheck"))
+	if err != nil || len(files) == 0 {
+		t.Skip("sample files not available")
+	}
+
+	for _, path := range files {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			data := readSample(t, filepath.Base(path))
+
+			f, err := Parse(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var out bytes.Buffer
+			if err := Write(&out, f); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			// Blank lines between races are not preserved
+			var expected strings.Builder
+			for _, line := range strings.Split(string(data), "\r\n") {
+				if line != "" {
+					expected.WriteString(line + "\r\n")
+				}
+			}
+			if out.String() != expected.String() {
+				t.Errorf("Write() output differs from the original file")
+			}
+		})
+	}
+}
+
+func TestParseSkipsMalformedLines(t *testing.T) {
+	input := "1|TEST\n" +
+		"M|row before header\n" +
+		";1|10K\n" +
+		";SEXO|NOMBRE|TIEMPO\n" +
+		"M|Juan|00:40:00\n" +
+		"F|Ana\n" +
+		"\n" +
+		";2|5K\n" +
+		";SEXO|NOMBRE|TIEMPO\n" +
+		"F|Maria|00:20:00\n"
+
+	f, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if f.RowCount() != 2 {
+		t.Errorf("RowCount() = %d, expected 2", f.RowCount())
+	}
+	if len(f.Skipped) != 2 {
+		t.Fatalf("len(Skipped) = %d, expected 2", len(f.Skipped))
+	}
+	if f.Skipped[0].Line != 2 || f.Skipped[1].Line != 6 {
+		t.Errorf("skipped lines = %d, %d, expected 2, 6", f.Skipped[0].Line, f.Skipped[1].Line)
+	}
+	if f.Races[1].Rows[0].Line != 10 {
+		t.Errorf("row line = %d, expected 10", f.Races[1].Rows[0].Line)
+	}
+	if got, _ := f.Races[1].Value(f.Races[1].Rows[0], "NOMBRE"); got != "Maria" {
+		t.Errorf("Value(NOMBRE) = %q, expected Maria", got)
+	}
+}
+
+func TestParseEmptyInput(t *testing.T) {
+	if _, err := Parse(strings.NewReader("")); err != ErrNoHeader {
+		t.Errorf("Parse() error = %v, expected ErrNoHeader", err)
+	}
+
+	p := NewParser(strings.NewReader("1|ONLY HEADER\n"))
+	if _, err := p.Next(); err != io.EOF {
+		t.Errorf("Next() error = %v, expected io.EOF", err)
+	}
+}
+
+func readSample(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(sampleDir, name))
+	if err != nil {
+		t.Skipf("sample file not available: %v", err)
+	}
+	return data
+}
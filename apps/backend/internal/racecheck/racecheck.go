@@ -0,0 +1,85 @@
+// Package racecheck reads and writes the pipe-separated results format exported
+// by the timing software.
+//
+// A racecheck file starts with an event header line, followed by one section per
+// race. Each race section has a race line, a column header line and the result rows:
+//
+//	1|CORRIDA CASABLANCA 2024
+//	;1|10K
+//	;SEXO|NOMBRE|CHIP|DORSAL|MODALIDAD|CATEGORIA|TIEMPO|POSICION|POS.CAT.|RITMO
+//	M|Cristobal Inostroza|QT00121|121|10K|Senior A Varones|00:33:37|1|1|00:00 min/Km
+package racecheck
+
+// EventHeader is the first line of the file.
+type EventHeader struct {
+	// Prefix is the value before the first pipe, usually "1". Empty when the
+	// header has no pipe.
+	Prefix string
+	Name   string
+	Line   int
+}
+
+// Race is a ";N|NAME" section with its column header and rows.
+type Race struct {
+	// Number is the race number. Rows that appear before any race line are
+	// grouped under an implicit race with Number 0 and an empty Name.
+	Number     int
+	Name       string
+	Line       int
+	Columns    []string
+	HeaderLine int
+	Rows       []Row
+}
+
+// Row is a single result line. Values are aligned with the race Columns.
+type Row struct {
+	Line   int
+	Values []string
+}
+
+// SkippedLine is a line that could not be interpreted.
+type SkippedLine struct {
+	Line   int
+	Reason string
+	Raw    string
+}
+
+// File is the parsed representation of a racecheck file.
+type File struct {
+	Header  EventHeader
+	Races   []*Race
+	Skipped []SkippedLine
+	// Lines is the total number of lines read, including the header.
+	Lines int
+	// Encoding is the detected encoding of the input, one of the Encoding constants.
+	Encoding string
+}
+
+// Value returns the value of column in the row, and false if the race has no
+// such column.
+func (r *Race) Value(row Row, column string) (string, bool) {
+	for i, c := range r.Columns {
+		if c == column {
+			return row.Values[i], true
+		}
+	}
+	return "", false
+}
+
+// RowMap returns the row as a map keyed by column name.
+func (r *Race) RowMap(row Row) map[string]string {
+	m := make(map[string]string, len(r.Columns))
+	for i, c := range r.Columns {
+		m[c] = row.Values[i]
+	}
+	return m
+}
+
+// RowCount returns the number of result rows across all races.
+func (f *File) RowCount() int {
+	count := 0
+	for _, race := range f.Races {
+		count += len(race.Rows)
+	}
+	return count
+}
@@ -0,0 +1,89 @@
+package racecheck
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Writer writes racecheck files.
+type Writer struct {
+	w *bufio.Writer
+	// LineEnding is written after every line. Defaults to "\r\n", which is what
+	// the timing software produces.
+	LineEnding string
+}
+
+// NewWriter returns a Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w), LineEnding: "\r\n"}
+}
+
+// WriteHeader writes the event header line.
+func (w *Writer) WriteHeader(h EventHeader) error {
+	if h.Prefix == "" {
+		h.Prefix = "1"
+	}
+	return w.line(h.Prefix + "|" + sanitize(h.Name))
+}
+
+// WriteRace writes a race line followed by its column header. Races with
+// Number 0 are implicit and only the column header is written.
+func (w *Writer) WriteRace(r *Race) error {
+	if r.Number != 0 || r.Name != "" {
+		if err := w.line(fmt.Sprintf(";%d|%s", r.Number, sanitize(r.Name))); err != nil {
+			return err
+		}
+	}
+	if r.Columns == nil {
+		return nil
+	}
+	return w.line(";" + strings.Join(r.Columns, "|"))
+}
+
+// WriteRow writes a result row.
+func (w *Writer) WriteRow(row Row) error {
+	values := make([]string, len(row.Values))
+	for i, v := range row.Values {
+		values[i] = sanitize(v)
+	}
+	return w.line(strings.Join(values, "|"))
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+func (w *Writer) line(s string) error {
+	if _, err := w.w.WriteString(s); err != nil {
+		return err
+	}
+	_, err := w.w.WriteString(w.LineEnding)
+	return err
+}
+
+// Write writes the whole file. Skipped lines are not written.
+func Write(w io.Writer, f *File) error {
+	rw := NewWriter(w)
+	if err := rw.WriteHeader(f.Header); err != nil {
+		return err
+	}
+	for _, race := range f.Races {
+		if err := rw.WriteRace(race); err != nil {
+			return err
+		}
+		for _, row := range race.Rows {
+			if err := rw.WriteRow(row); err != nil {
+				return err
+			}
+		}
+	}
+	return rw.Flush()
+}
+
+// sanitize removes characters that would break the line and column structure.
+func sanitize(value string) string {
+	return strings.NewReplacer("|", "/", "\r", " ", "\n", " ").Replace(value)
+}
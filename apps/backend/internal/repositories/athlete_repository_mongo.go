@@ -0,0 +1,287 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoAthleteRepository struct {
+	db     *mongo.Client
+	dbName string
+}
+
+func NewMongoAthleteRepository(db *mongo.Client) ports.AthleteRepository {
+	return &mongoAthleteRepository{
+		db:     db,
+		dbName: os.Getenv("MONGO_DATABASE"),
+	}
+}
+
+func (r *mongoAthleteRepository) getAthleteCollection() *mongo.Collection {
+	return r.db.Database(r.dbName).Collection("athletes")
+}
+
+func (r *mongoAthleteRepository) getAthleteResultCollection() *mongo.Collection {
+	return r.db.Database(r.dbName).Collection("athlete_results")
+}
+
+func (r *mongoAthleteRepository) SaveAthlete(athlete *domain.Athlete) error {
+	if athlete.ID.IsZero() {
+		athlete.ID = primitive.NewObjectID()
+	}
+	athlete.CreatedAt = time.Now()
+	athlete.UpdatedAt = athlete.CreatedAt
+	_, err := r.getAthleteCollection().InsertOne(context.Background(), athlete)
+	return err
+}
+
+func (r *mongoAthleteRepository) UpdateAthlete(athlete *domain.Athlete) error {
+	athlete.UpdatedAt = time.Now()
+	_, err := r.getAthleteCollection().UpdateOne(
+		context.Background(),
+		bson.M{"_id": athlete.ID},
+		bson.M{"$set": bson.M{
+			"name":            athlete.Name,
+			"normalizedNames": athlete.NormalizedNames,
+			"chips":           athlete.Chips,
+			"nationalId":      athlete.NationalID,
+			"sex":             athlete.Sex,
+			"updatedAt":       athlete.UpdatedAt,
+		}},
+	)
+	return err
+}
+
+func (r *mongoAthleteRepository) DeleteAthletes(ids []primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.getAthleteCollection().DeleteMany(context.Background(), bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+func (r *mongoAthleteRepository) FindAthleteByID(id primitive.ObjectID) (*domain.Athlete, error) {
+	var athlete domain.Athlete
+	err := r.getAthleteCollection().FindOne(context.Background(), bson.M{"_id": id}).Decode(&athlete)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &athlete, nil
+}
+
+func (r *mongoAthleteRepository) FindAthletesByIDs(ids []primitive.ObjectID) ([]*domain.Athlete, error) {
+	return r.findAthletes(bson.M{"_id": bson.M{"$in": ids}})
+}
+
+// FindAthletesByIdentity retorna los atletas que coinciden con alguno de los documentos,
+// nombres normalizados o chips
+func (r *mongoAthleteRepository) FindAthletesByIdentity(nationalIDs, names, chips []string) ([]*domain.Athlete, error) {
+	var or bson.A
+	if len(nationalIDs) > 0 {
+		or = append(or, bson.M{"nationalId": bson.M{"$in": nationalIDs}})
+	}
+	if len(names) > 0 {
+		or = append(or, bson.M{"normalizedNames": bson.M{"$in": names}})
+	}
+	if len(chips) > 0 {
+		or = append(or, bson.M{"chips": bson.M{"$in": chips}})
+	}
+	if len(or) == 0 {
+		return []*domain.Athlete{}, nil
+	}
+	return r.findAthletes(bson.M{"$or": or})
+}
+
+func (r *mongoAthleteRepository) findAthletes(filter bson.M) ([]*domain.Athlete, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.getAthleteCollection().Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	athletes := []*domain.Athlete{}
+	if err := cursor.All(context.Background(), &athletes); err != nil {
+		return nil, err
+	}
+	return athletes, nil
+}
+
+func (r *mongoAthleteRepository) FindAthletes(name *string, page int, limit int) (*ports.FindAthletesResult, error) {
+	filter := bson.M{}
+	if name != nil {
+		filter["name"] = bson.M{"$regex": regexp.QuoteMeta(*name), "$options": "i"}
+	}
+
+	totalCount, err := r.getAthleteCollection().CountDocuments(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64((page - 1) * limit))
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.getAthleteCollection().Find(context.Background(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	athletes := []*domain.Athlete{}
+	if err = cursor.All(context.Background(), &athletes); err != nil {
+		return nil, err
+	}
+
+	return &ports.FindAthletesResult{
+		Athletes:   athletes,
+		TotalCount: totalCount,
+	}, nil
+}
+
+// SaveAthleteResults inserta o reemplaza los vínculos; los nuevos reciben un ID
+func (r *mongoAthleteRepository) SaveAthleteResults(results []*domain.AthleteResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(results))
+	now := time.Now()
+	for i, result := range results {
+		if result.ID.IsZero() {
+			result.ID = primitive.NewObjectID()
+		}
+		result.UpdatedAt = now
+		models[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": result.ID}).
+			SetReplacement(result).
+			SetUpsert(true)
+	}
+
+	if _, err := r.getAthleteResultCollection().BulkWrite(context.Background(), models); err != nil {
+		return fmt.Errorf("could not save athlete results: %w", err)
+	}
+	return nil
+}
+
+func (r *mongoAthleteRepository) DeleteAthleteResults(ids []primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.getAthleteResultCollection().DeleteMany(context.Background(), bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+// DeleteEventAthleteResults elimina los vínculos del evento y retorna los atletas que tenían
+func (r *mongoAthleteRepository) DeleteEventAthleteResults(eventID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	athleteIDs, err := r.getAthleteResultCollection().Distinct(context.Background(), "athleteId", bson.M{"eventId": eventID})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.getAthleteResultCollection().DeleteMany(context.Background(), bson.M{"eventId": eventID}); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(athleteIDs))
+	for _, id := range athleteIDs {
+		if objID, ok := id.(primitive.ObjectID); ok {
+			ids = append(ids, objID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *mongoAthleteRepository) FindEventAthleteResults(eventID primitive.ObjectID) ([]*domain.AthleteResult, error) {
+	return r.findAthleteResults(bson.M{"eventId": eventID})
+}
+
+func (r *mongoAthleteRepository) FindAthleteResults(athleteID primitive.ObjectID) ([]*domain.AthleteResult, error) {
+	return r.findAthleteResults(bson.M{"athleteId": athleteID})
+}
+
+func (r *mongoAthleteRepository) findAthleteResults(filter bson.M) ([]*domain.AthleteResult, error) {
+	cursor, err := r.getAthleteResultCollection().Find(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	results := []*domain.AthleteResult{}
+	if err := cursor.All(context.Background(), &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *mongoAthleteRepository) MoveAthleteResults(ids []primitive.ObjectID, athleteID primitive.ObjectID, manual bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.getAthleteResultCollection().UpdateMany(
+		context.Background(),
+		bson.M{"_id": bson.M{"$in": ids}},
+		bson.M{"$set": bson.M{"athleteId": athleteID, "manual": manual, "updatedAt": time.Now()}},
+	)
+	return err
+}
+
+// MoveAllAthleteResults asigna a athleteID todos los resultados de los atletas indicados.
+// Los vínculos quedan como manuales para que una nueva carga no los vuelva a separar.
+func (r *mongoAthleteRepository) MoveAllAthleteResults(fromIDs []primitive.ObjectID, athleteID primitive.ObjectID) error {
+	if len(fromIDs) == 0 {
+		return nil
+	}
+	_, err := r.getAthleteResultCollection().UpdateMany(
+		context.Background(),
+		bson.M{"athleteId": bson.M{"$in": fromIDs}},
+		bson.M{"$set": bson.M{"athleteId": athleteID, "manual": true, "updatedAt": time.Now()}},
+	)
+	return err
+}
+
+func (r *mongoAthleteRepository) CountEventAthleteResults(eventID primitive.ObjectID) (int, error) {
+	count, err := r.getAthleteResultCollection().CountDocuments(context.Background(), bson.M{"eventId": eventID})
+	return int(count), err
+}
+
+// DeleteOrphanAthletes elimina, de entre los atletas indicados, los que ya no tienen resultados
+func (r *mongoAthleteRepository) DeleteOrphanAthletes(ids []primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	linked, err := r.getAthleteResultCollection().Distinct(context.Background(), "athleteId", bson.M{"athleteId": bson.M{"$in": ids}})
+	if err != nil {
+		return err
+	}
+	hasResults := make(map[primitive.ObjectID]bool, len(linked))
+	for _, id := range linked {
+		if objID, ok := id.(primitive.ObjectID); ok {
+			hasResults[objID] = true
+		}
+	}
+
+	var orphans []primitive.ObjectID
+	for _, id := range ids {
+		if !hasResults[id] {
+			orphans = append(orphans, id)
+		}
+	}
+	return r.DeleteAthletes(orphans)
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *mongoEventRepository) getCorrectionCollection() *mongo.Collection {
+	return r.db.Database(r.dbName).Collection("result_corrections")
+}
+
+func (r *mongoEventRepository) getCorrectionAuditCollection() *mongo.Collection {
+	return r.db.Database(r.dbName).Collection("result_correction_audit")
+}
+
+// SaveCorrection crea la corrección o reemplaza la existente con el mismo ID. Una corrección
+// con ID que ya no existe, p. ej. al revertir su eliminación, se vuelve a crear.
+func (r *mongoEventRepository) SaveCorrection(correction *domain.ResultCorrection) error {
+	correction.UpdatedAt = time.Now()
+	if correction.ID.IsZero() {
+		correction.ID = primitive.NewObjectID()
+		correction.CreatedAt = correction.UpdatedAt
+		_, err := r.getCorrectionCollection().InsertOne(context.Background(), correction)
+		return err
+	}
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.getCorrectionCollection().ReplaceOne(context.Background(), bson.M{"_id": correction.ID}, correction, opts)
+	return err
+}
+
+func (r *mongoEventRepository) DeleteCorrection(id primitive.ObjectID) error {
+	_, err := r.getCorrectionCollection().DeleteOne(context.Background(), bson.M{"_id": id})
+	return err
+}
+
+// DeleteCorrections elimina las correcciones del evento junto con su auditoría
+func (r *mongoEventRepository) DeleteCorrections(eventID primitive.ObjectID) error {
+	if _, err := r.getCorrectionAuditCollection().DeleteMany(context.Background(), bson.M{"eventId": eventID}); err != nil {
+		return err
+	}
+	_, err := r.getCorrectionCollection().DeleteMany(context.Background(), bson.M{"eventId": eventID})
+	return err
+}
+
+func (r *mongoEventRepository) FindCorrection(eventID primitive.ObjectID, bib string) (*domain.ResultCorrection, error) {
+	var correction domain.ResultCorrection
+	err := r.getCorrectionCollection().FindOne(context.Background(), bson.M{"eventId": eventID, "bib": bib}).Decode(&correction)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &correction, nil
+}
+
+func (r *mongoEventRepository) FindCorrections(eventID primitive.ObjectID) ([]*domain.ResultCorrection, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "bib", Value: 1}})
+	cursor, err := r.getCorrectionCollection().Find(context.Background(), bson.M{"eventId": eventID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	corrections := []*domain.ResultCorrection{}
+	if err := cursor.All(context.Background(), &corrections); err != nil {
+		return nil, err
+	}
+	return corrections, nil
+}
+
+func (r *mongoEventRepository) SaveCorrectionAudit(entry *domain.CorrectionAuditEntry) error {
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+	entry.CreatedAt = time.Now()
+	_, err := r.getCorrectionAuditCollection().InsertOne(context.Background(), entry)
+	return err
+}
+
+// FindCorrectionAudit obtiene la auditoría de correcciones del evento, de la más reciente a
+// la más antigua. Con bib, solo la del participante con ese dorsal.
+func (r *mongoEventRepository) FindCorrectionAudit(eventID primitive.ObjectID, bib *string) ([]*domain.CorrectionAuditEntry, error) {
+	filter := bson.M{"eventId": eventID}
+	if bib != nil {
+		filter["bib"] = *bib
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.getCorrectionAuditCollection().Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	entries := []*domain.CorrectionAuditEntry{}
+	if err := cursor.All(context.Background(), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
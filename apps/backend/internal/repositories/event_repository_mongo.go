@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"time"
 
@@ -29,6 +30,34 @@ func NewMongoEventRepository(db *mongo.Client) ports.EventRepository {
 	}
 }
 
+// EnsureIndexes crea los índices de los que depende la integridad de los datos del evento
+func (r *mongoEventRepository) EnsureIndexes() error {
+	_, err := r.getVersionCollection().Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "eventId", Value: 1}, {Key: "number", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("could not create event version index: %w", err)
+	}
+
+	_, err = r.getCorrectionCollection().Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "eventId", Value: 1}, {Key: "bib", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("could not create result correction index: %w", err)
+	}
+
+	_, err = r.getParticipantStatusCollection().Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "eventId", Value: 1}, {Key: "bib", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("could not create participant status index: %w", err)
+	}
+	return nil
+}
+
 func (r *mongoEventRepository) getEventCollection() *mongo.Collection {
 	return r.db.Database(r.dbName).Collection("events")
 }
@@ -37,19 +66,12 @@ func (r *mongoEventRepository) getEventDataCollection() *mongo.Collection {
 	return r.db.Database(r.dbName).Collection("event_data")
 }
 
-// parseSearchValue intenta convertir un valor de búsqueda string a número si es posible
-func parseSearchValue(value string) (interface{}, error) {
-	// Intentar parsear como entero
-	if intVal, err := strconv.Atoi(value); err == nil {
-		return intVal, nil
-	}
-
-	// Intentar parsear como float
-	if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
-		return floatVal, nil
-	}
+func (r *mongoEventRepository) getUploadCollection() *mongo.Collection {
+	return r.db.Database(r.dbName).Collection("uploads")
+}
 
-	return nil, fmt.Errorf("cannot parse as number")
+func (r *mongoEventRepository) getChangeLogCollection() *mongo.Collection {
+	return r.db.Database(r.dbName).Collection("event_changes")
 }
 
 func (r *mongoEventRepository) Save(event *domain.Event) error {
@@ -115,37 +137,309 @@ func (r *mongoEventRepository) UpdateFileHash(id primitive.ObjectID, hash string
 	return err
 }
 
-func (r *mongoEventRepository) UpdateFileStats(id primitive.ObjectID, hash string, uniqueModalities []string, uniqueCategories []string, recordsCount int) error {
+func (r *mongoEventRepository) DeleteEventData(eventID primitive.ObjectID) error {
+	_, err := r.getEventDataCollection().DeleteMany(context.Background(), bson.M{"eventId": eventID})
+	return err
+}
+
+// staleGenerationAge es la antigüedad desde la que una generación que no está activa ni es la
+// anterior se considera abandonada, p. ej. por una carga interrumpida. Es mucho mayor que lo
+// que demora cualquier carga, para no eliminar la de otra carga que aún no cambia la activa.
+const staleGenerationAge = time.Hour
+
+// ReplaceEventData publica los participantes del evento sin que los lectores vean nunca un
+// conjunto incompleto: los nuevos registros se insertan con una generación nueva que aún no
+// es visible, y luego se cambia la generación activa del evento junto con sus estadísticas en
+// una sola actualización. La generación reemplazada se conserva hasta el siguiente reemplazo,
+// para los lectores que obtuvieron la generación activa justo antes del cambio, y solo se
+// elimina la que ella había reemplazado. Así dos cargas simultáneas nunca eliminan la
+// generación que la otra publicó.
+func (r *mongoEventRepository) ReplaceEventData(event *domain.Event, data []domain.EventData) (int, error) {
+	generation := primitive.NewObjectID()
+
+	inserted := 0
+	if len(data) > 0 {
+		docs := make([]interface{}, len(data))
+		for i, d := range data {
+			d.ID = primitive.NilObjectID
+			d.EventID = event.ID
+			d.Generation = generation
+			d.CreatedAt = time.Now()
+			docs[i] = d
+		}
+
+		result, err := r.getEventDataCollection().InsertMany(context.Background(), docs)
+		if err != nil {
+			// Descartar lo insertado; el evento sigue mostrando la generación anterior
+			r.deleteGeneration(event.ID, generation)
+			return 0, fmt.Errorf("could not save event data: %w", err)
+		}
+		inserted = len(result.InsertedIDs)
+	}
+
+	// El cambio retorna el evento anterior, con la generación que se está reemplazando. Se hace
+	// con un pipeline para copiar la generación activa a la anterior en la misma operación; los
+	// valores van en $literal para que un texto que empiece con "$" no se lea como un campo.
+	var replaced struct {
+		ActiveGeneration   primitive.ObjectID `bson:"activeGeneration"`
+		PreviousGeneration primitive.ObjectID `bson:"previousGeneration"`
+	}
+	err := r.getEventCollection().FindOneAndUpdate(
+		context.Background(),
+		bson.M{"_id": event.ID},
+		bson.A{bson.M{"$set": bson.M{
+			"fileHash":           bson.M{"$literal": event.FileHash},
+			"uniqueModalities":   bson.M{"$literal": event.UniqueModalities},
+			"uniqueCategories":   bson.M{"$literal": event.UniqueCategories},
+			"recordsCount":       event.RecordsCount,
+			"races":              bson.M{"$literal": event.Races},
+			"activeVersionId":    event.ActiveVersionID,
+			"previousGeneration": "$activeGeneration",
+			"activeGeneration":   generation,
+		}}},
+		options.FindOneAndUpdate().
+			SetReturnDocument(options.Before).
+			SetProjection(bson.M{"activeGeneration": 1, "previousGeneration": 1}),
+	).Decode(&replaced)
+	if err != nil {
+		r.deleteGeneration(event.ID, generation)
+		return 0, fmt.Errorf("could not update event stats: %w", err)
+	}
+	event.PreviousGeneration = replaced.ActiveGeneration
+	event.ActiveGeneration = generation
+
+	// La generación que había reemplazado a la anterior ya no la puede estar leyendo nadie.
+	// Sin generación activa previa, los datos sin generación pasan a ser los anteriores.
+	if !replaced.ActiveGeneration.IsZero() {
+		r.deleteGeneration(event.ID, replaced.PreviousGeneration)
+	}
+	r.deleteStaleGenerations(event.ID, generation, replaced.ActiveGeneration)
+
+	return inserted, nil
+}
+
+// generationFilter retorna el filtro de los participantes de una generación. La generación
+// vacía corresponde a los datos cargados antes de existir las generaciones.
+func generationFilter(eventID primitive.ObjectID, generation primitive.ObjectID) bson.M {
+	if generation.IsZero() {
+		return bson.M{"eventId": eventID, "generation": nil}
+	}
+	return bson.M{"eventId": eventID, "generation": generation}
+}
+
+func (r *mongoEventRepository) deleteGeneration(eventID primitive.ObjectID, generation primitive.ObjectID) {
+	if _, err := r.getEventDataCollection().DeleteMany(context.Background(), generationFilter(eventID, generation)); err != nil {
+		fmt.Printf("[WARNING] Could not delete event data generation %s: %v\n", generation.Hex(), err)
+	}
+}
+
+// deleteStaleGenerations elimina las generaciones abandonadas: las que no están activa ni son
+// la anterior y tienen más de staleGenerationAge, p. ej. las que no se pudieron eliminar antes
+func (r *mongoEventRepository) deleteStaleGenerations(eventID primitive.ObjectID, active, previous primitive.ObjectID) {
+	keep := bson.A{active, nil}
+	if !previous.IsZero() {
+		keep = bson.A{active, previous}
+	}
+	if _, err := r.getEventDataCollection().DeleteMany(context.Background(), bson.M{
+		"eventId":    eventID,
+		"generation": bson.M{"$nin": keep},
+		"createdAt":  bson.M{"$lt": time.Now().Add(-staleGenerationAge)},
+	}); err != nil {
+		fmt.Printf("[WARNING] Could not delete stale event data generations for %s: %v\n", eventID.Hex(), err)
+	}
+}
+
+// publishedDataFilter retorna el filtro de los participantes publicados del evento, que son
+// los de su generación activa. Los datos cargados antes de existir las generaciones no
+// tienen una, y siguen visibles mientras el evento no tenga generación activa.
+func (r *mongoEventRepository) publishedDataFilter(eventID primitive.ObjectID) (bson.M, error) {
+	var event struct {
+		ActiveGeneration primitive.ObjectID `bson:"activeGeneration"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"activeGeneration": 1})
+	err := r.getEventCollection().FindOne(context.Background(), bson.M{"_id": eventID}, opts).Decode(&event)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	return generationFilter(eventID, event.ActiveGeneration), nil
+}
+
+func (r *mongoEventRepository) FindDataWithoutResult(limit int) ([]*domain.EventData, error) {
+	opts := options.Find().SetLimit(int64(limit))
+	cursor, err := r.getEventDataCollection().Find(context.Background(), bson.M{"result": bson.M{"$exists": false}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var data []*domain.EventData
+	if err := cursor.All(context.Background(), &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *mongoEventRepository) UpdateDataResult(id primitive.ObjectID, result domain.Result) error {
+	_, err := r.getEventDataCollection().UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"result": result}},
+	)
+	return err
+}
+
+// UpdateDataResults guarda el resultado de varios participantes en una sola operación
+func (r *mongoEventRepository) UpdateDataResults(data []*domain.EventData) error {
+	if len(data) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, len(data))
+	for i, item := range data {
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": item.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"result": item.Result}})
+	}
+	_, err := r.getEventDataCollection().BulkWrite(context.Background(), models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r *mongoEventRepository) FindAllData(eventID primitive.ObjectID) ([]*domain.EventData, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "raceNumber", Value: 1}, {Key: "_id", Value: 1}})
+	filter, err := r.publishedDataFilter(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.getEventDataCollection().Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	data := []*domain.EventData{}
+	if err := cursor.All(context.Background(), &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *mongoEventRepository) FindRaceData(eventID primitive.ObjectID, raceNumber int) ([]*domain.EventData, error) {
+	filter, err := r.publishedDataFilter(eventID)
+	if err != nil {
+		return nil, err
+	}
+	filter["raceNumber"] = raceNumber
+
+	cursor, err := r.getEventDataCollection().Find(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var data []*domain.EventData
+	if err := cursor.All(context.Background(), &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *mongoEventRepository) UpdateRaces(id primitive.ObjectID, races []domain.Race) error {
 	_, err := r.getEventCollection().UpdateOne(
 		context.Background(),
 		bson.M{"_id": id},
-		bson.M{"$set": bson.M{
-			"fileHash":         hash,
-			"uniqueModalities": uniqueModalities,
-			"uniqueCategories": uniqueCategories,
-			"recordsCount":     recordsCount,
-		}},
+		bson.M{"$set": bson.M{"races": races}},
 	)
 	return err
 }
 
-func (r *mongoEventRepository) DeleteEventData(eventID primitive.ObjectID) error {
-	_, err := r.getEventDataCollection().DeleteMany(context.Background(), bson.M{"eventId": eventID})
+func (r *mongoEventRepository) UpdateCertificateTemplate(id primitive.ObjectID, template *domain.CertificateTemplate) error {
+	_, err := r.getEventCollection().UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"certificate": template}},
+	)
+	return err
+}
+
+func (r *mongoEventRepository) UpdateTeamScoring(id primitive.ObjectID, scoring *domain.TeamScoring) error {
+	_, err := r.getEventCollection().UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"teamScoring": scoring}},
+	)
+	return err
+}
+
+func (r *mongoEventRepository) UpdateRankingSource(id primitive.ObjectID, source string) error {
+	_, err := r.getEventCollection().UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"rankingSource": source}},
+	)
+	return err
+}
+
+func (r *mongoEventRepository) SaveUpload(upload *domain.Upload) error {
+	_, err := r.getUploadCollection().InsertOne(context.Background(), upload)
+	return err
+}
+
+func (r *mongoEventRepository) FindUploads(eventID primitive.ObjectID) ([]*domain.Upload, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.getUploadCollection().Find(context.Background(), bson.M{"eventId": eventID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	uploads := []*domain.Upload{}
+	if err := cursor.All(context.Background(), &uploads); err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
+
+func (r *mongoEventRepository) SaveChangeLogEntry(entry *domain.ChangeLogEntry) error {
+	_, err := r.getChangeLogCollection().InsertOne(context.Background(), entry)
 	return err
 }
 
-func (r *mongoEventRepository) SaveAllData(data []domain.EventData) (int, error) {
-	docs := make([]interface{}, len(data))
-	for i, d := range data {
-		d.CreatedAt = time.Now()
-		docs[i] = d
+func (r *mongoEventRepository) FindChangeLog(eventID primitive.ObjectID) ([]*domain.ChangeLogEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.getChangeLogCollection().Find(context.Background(), bson.M{"eventId": eventID}, opts)
+	if err != nil {
+		return nil, err
 	}
+	defer cursor.Close(context.Background())
 
-	result, err := r.getEventDataCollection().InsertMany(context.Background(), docs)
+	entries := []*domain.ChangeLogEntry{}
+	if err := cursor.All(context.Background(), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// FindEventIDsWithRecords retorna solo los identificadores de los eventos con participantes
+func (r *mongoEventRepository) FindEventIDsWithRecords() ([]primitive.ObjectID, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.getEventCollection().Find(context.Background(), bson.M{"recordsCount": bson.M{"$gt": 0}}, opts)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var events []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(context.Background(), &events); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
 	}
-	return len(result.InsertedIDs), nil
+	return ids, nil
 }
 
 func (r *mongoEventRepository) Find(name *string, date *time.Time, page int, limit int) (*ports.FindEventsResult, error) {
@@ -192,100 +486,48 @@ func (r *mongoEventRepository) Find(name *string, date *time.Time, page int, lim
 	}, nil
 }
 
-func (r *mongoEventRepository) FindData(eventID primitive.ObjectID, name, chip, dorsal, category, distance, sex, position *string, page int, limit int) (*ports.FindParticipantsResult, error) {
-	filter := bson.M{"eventId": eventID}
-	var orConditions []bson.M
+func (r *mongoEventRepository) FindData(eventID primitive.ObjectID, name, chip, dorsal, category, distance, sex, position, race *string, page int, limit int) (*ports.FindParticipantsResult, error) {
+	filter, err := r.publishedDataFilter(eventID)
+	if err != nil {
+		return nil, err
+	}
 
-	// Para nombre, buscar en múltiples campos con case-insensitive
+	// Para nombre, búsqueda parcial case-insensitive
 	if name != nil {
-		nameRegex := bson.M{"$regex": *name, "$options": "i"}
-		orConditions = append(orConditions,
-			bson.M{"data.Nombre": nameRegex},
-			bson.M{"data.NOMBRE": nameRegex},
-			bson.M{"data.name": nameRegex},
-			bson.M{"data.nombre": nameRegex},
-		)
+		filter["result.name"] = bson.M{"$regex": regexp.QuoteMeta(*name), "$options": "i"}
 	}
 
-	// Para categoría, buscar en múltiples campos con coincidencia exacta (case-insensitive)
+	// Para categoría y distancia, coincidencia exacta case-insensitive
 	if category != nil {
-		orConditions = append(orConditions,
-			bson.M{"data.Categoría": bson.M{"$regex": "^" + *category + "$", "$options": "i"}},
-			bson.M{"data.CATEGORIA": bson.M{"$regex": "^" + *category + "$", "$options": "i"}},
-			bson.M{"data.category": bson.M{"$regex": "^" + *category + "$", "$options": "i"}},
-			bson.M{"data.categoria": bson.M{"$regex": "^" + *category + "$", "$options": "i"}},
-		)
+		filter["result.category"] = exactMatch(*category)
 	}
-
-	// Para distancia, buscar en múltiples campos con coincidencia exacta (case-insensitive)
 	if distance != nil {
-		orConditions = append(orConditions,
-			bson.M{"data.MODALIDAD": bson.M{"$regex": "^" + *distance + "$", "$options": "i"}},
-			bson.M{"data.distance": bson.M{"$regex": "^" + *distance + "$", "$options": "i"}},
-			bson.M{"data.distancia": bson.M{"$regex": "^" + *distance + "$", "$options": "i"}},
-		)
+		filter["result.modality"] = exactMatch(*distance)
 	}
 
-	// Para sexo, buscar en múltiples campos con case-insensitive
+	// Para sexo, búsqueda case-insensitive
 	if sex != nil {
-		sexRegex := bson.M{"$regex": *sex, "$options": "i"}
-		orConditions = append(orConditions,
-			bson.M{"data.Sexo": sexRegex},
-			bson.M{"data.SEXO": sexRegex},
-			bson.M{"data.sex": sexRegex},
-		)
+		filter["result.sex"] = bson.M{"$regex": regexp.QuoteMeta(*sex), "$options": "i"}
 	}
 
-	// Para chip, dorsal y posición, buscar tanto como string como número
 	if chip != nil {
-		if chipNum, err := parseSearchValue(*chip); err == nil {
-			orConditions = append(orConditions,
-				bson.M{"data.Chip": chipNum},
-				bson.M{"data.Chip": bson.M{"$regex": *chip, "$options": "i"}},
-			)
-		} else {
-			filter["data.Chip"] = bson.M{"$regex": *chip, "$options": "i"}
-		}
+		filter["result.chip"] = bson.M{"$regex": regexp.QuoteMeta(*chip), "$options": "i"}
 	}
 	if dorsal != nil {
-		if dorsalNum, err := parseSearchValue(*dorsal); err == nil {
-			// Si es numérico, buscar como número y también como string
-			orConditions = append(orConditions,
-				bson.M{"data.Dorsal": dorsalNum},
-				bson.M{"data.DORSAL": dorsalNum},
-				bson.M{"data.dorsal": dorsalNum},
-				bson.M{"data.Dorsal": *dorsal},
-				bson.M{"data.DORSAL": *dorsal},
-				bson.M{"data.dorsal": *dorsal},
-			)
-		} else {
-			// Búsqueda exacta case-insensitive usando regex con ^...$ para asegurar coincidencia completa
-			escapedDorsal := regexp.QuoteMeta(*dorsal)
-			orConditions = append(orConditions,
-				bson.M{"data.Dorsal": bson.M{"$regex": "^" + escapedDorsal + "$", "$options": "i"}},
-				bson.M{"data.DORSAL": bson.M{"$regex": "^" + escapedDorsal + "$", "$options": "i"}},
-				bson.M{"data.dorsal": bson.M{"$regex": "^" + escapedDorsal + "$", "$options": "i"}},
-			)
-		}
+		filter["result.bib"] = exactMatch(*dorsal)
 	}
 	if position != nil {
-		if posNum, err := parseSearchValue(*position); err == nil {
-			orConditions = append(orConditions,
-				bson.M{"data.POSICION": posNum},
-				bson.M{"data.POSICION": bson.M{"$regex": *position, "$options": "i"}},
-			)
-		} else {
-			filter["data.POSICION"] = bson.M{"$regex": *position, "$options": "i"}
-		}
+		// Un valor no numérico (p. ej. "DNF") busca a los participantes sin posición
+		posNum, _ := strconv.Atoi(*position)
+		filter["result.position"] = posNum
 	}
 
-	// Si hay condiciones OR, agregarlas al filtro
-	if len(orConditions) > 0 {
-		if existingOr, ok := filter["$or"]; ok {
-			// Combinar con condiciones OR existentes
-			filter["$or"] = append(existingOr.([]bson.M), orConditions...)
+	// Para carrera, filtrar por el número de la sección ";N|NOMBRE" del archivo
+	if race != nil {
+		if raceNum, err := strconv.Atoi(*race); err == nil {
+			filter["raceNumber"] = raceNum
 		} else {
-			filter["$or"] = orConditions
+			filter["raceName"] = exactMatch(*race)
 		}
 	}
 
@@ -294,23 +536,32 @@ func (r *mongoEventRepository) FindData(eventID primitive.ObjectID, name, chip,
 		return nil, err
 	}
 
-	// Usar agregación para ordenar correctamente la posición como número
+	// Usar agregación para dejar al final a los participantes sin posición publicada
 	pipeline := []bson.M{
 		{"$match": filter},
 		{
 			"$addFields": bson.M{
-				"posicionNumerica": bson.M{
-					"$toInt": bson.M{
-						"$ifNull": []interface{}{"$data.POSICION", 999999},
-					},
-				},
+				// Los participantes sin posición (0) van al final
+				"sinPosicion": bson.M{"$lte": []interface{}{"$result.position", 0}},
+				// Entre participantes sin posición, los que tienen tiempo van primero
+				"sinTiempo": bson.M{"$lte": []interface{}{"$result.finishTimeMs", 0}},
 			},
 		},
-		{"$sort": bson.M{"data.MODALIDAD": 1, "posicionNumerica": 1, "_id": 1}},
-		{"$skip": int64((page - 1) * limit)},
-		{"$limit": int64(limit)},
-		{"$project": bson.M{"posicionNumerica": 0}}, // Remover campo temporal
+		{"$sort": bson.D{
+			{Key: "raceNumber", Value: 1},
+			{Key: "result.modality", Value: 1},
+			{Key: "sinPosicion", Value: 1},
+			{Key: "result.position", Value: 1},
+			{Key: "sinTiempo", Value: 1},
+			{Key: "result.finishTimeMs", Value: 1},
+			{Key: "_id", Value: 1},
+		}},
+	}
+	// Con limit <= 0 se retornan todos los participantes, p. ej. para exportar
+	if limit > 0 {
+		pipeline = append(pipeline, bson.M{"$skip": int64((page - 1) * limit)}, bson.M{"$limit": int64(limit)})
 	}
+	pipeline = append(pipeline, bson.M{"$project": bson.M{"sinPosicion": 0, "sinTiempo": 0}}) // Remover campos temporales
 
 	cursor, err := r.getEventDataCollection().Aggregate(context.Background(), pipeline)
 	if err != nil {
@@ -363,6 +614,7 @@ func (r *mongoEventRepository) Update(id primitive.ObjectID, event *domain.Event
 			"uniqueModalities": event.UniqueModalities,
 			"uniqueCategories": event.UniqueCategories,
 			"recordsCount":     event.RecordsCount,
+			"races":            event.Races,
 		}},
 	)
 	return err
@@ -373,6 +625,18 @@ func (r *mongoEventRepository) Delete(id primitive.ObjectID) error {
 	if err := r.DeleteEventData(id); err != nil {
 		return fmt.Errorf("could not delete event data: %w", err)
 	}
+	if err := r.DeleteVersions(id); err != nil {
+		return fmt.Errorf("could not delete event versions: %w", err)
+	}
+	if err := r.DeleteParticipantStatuses(id); err != nil {
+		return fmt.Errorf("could not delete participant statuses: %w", err)
+	}
+	if err := r.DeleteCorrections(id); err != nil {
+		return fmt.Errorf("could not delete result corrections: %w", err)
+	}
+	if err := r.DeleteStartList(id); err != nil {
+		return fmt.Errorf("could not delete start list: %w", err)
+	}
 
 	// Then delete the event itself
 	_, err := r.getEventCollection().DeleteOne(context.Background(), bson.M{"_id": id})
@@ -393,32 +657,17 @@ func (r *mongoEventRepository) GetParticipantComparison(eventID primitive.Object
 	collection := r.getEventDataCollection()
 
 	// Filtro para obtener participantes de la misma distancia y categoría
-	distanceFilter := bson.M{
-		"eventId": eventID,
-		"$and": []bson.M{
-			{
-				"$or": []bson.M{
-					bson.M{"data.MODALIDAD": bson.M{"$regex": "^" + distance + "$", "$options": "i"}},
-					bson.M{"data.distance": bson.M{"$regex": "^" + distance + "$", "$options": "i"}},
-					bson.M{"data.distancia": bson.M{"$regex": "^" + distance + "$", "$options": "i"}},
-				},
-			},
-			{
-				"$or": []bson.M{
-					bson.M{"data.CATEGORIA": bson.M{"$regex": "^" + category + "$", "$options": "i"}},
-					bson.M{"data.Categoría": bson.M{"$regex": "^" + category + "$", "$options": "i"}},
-					bson.M{"data.category": bson.M{"$regex": "^" + category + "$", "$options": "i"}},
-					bson.M{"data.categoria": bson.M{"$regex": "^" + category + "$", "$options": "i"}},
-				},
-			},
-		},
+	distanceFilter, err := r.publishedDataFilter(eventID)
+	if err != nil {
+		return nil, err
 	}
+	distanceFilter["result.modality"] = exactMatch(distance)
+	distanceFilter["result.category"] = exactMatch(category)
+	// Quienes no terminaron (DNF, DNS, DSQ, OTL) no se comparan
+	distanceFilter["result.status"] = bson.M{"$in": bson.A{nil, ""}}
 
 	// Obtener todos los participantes de la misma distancia, ordenados por posición
-	opts := options.Find().SetSort(bson.D{
-		{Key: "data.POSICION", Value: 1},
-	})
-	cursor, err := collection.Find(context.Background(), distanceFilter, opts)
+	cursor, err := collection.Find(context.Background(), distanceFilter)
 	if err != nil {
 		return nil, fmt.Errorf("could not find participants: %w", err)
 	}
@@ -429,6 +678,15 @@ func (r *mongoEventRepository) GetParticipantComparison(eventID primitive.Object
 		return nil, fmt.Errorf("could not decode participants: %w", err)
 	}
 
+	// Los participantes sin posición (0) van al final
+	sort.SliceStable(allParticipants, func(i, j int) bool {
+		pi, pj := allParticipants[i].Result.Position, allParticipants[j].Result.Position
+		if pi == 0 || pj == 0 {
+			return pj == 0 && pi != 0
+		}
+		return pi < pj
+	})
+
 	result := &ports.ComparisonResult{
 		FirstPlace:           nil,
 		PreviousParticipants: []*domain.EventData{},
@@ -441,8 +699,7 @@ func (r *mongoEventRepository) GetParticipantComparison(eventID primitive.Object
 	// Buscar el participante seleccionado por dorsal
 	var selectedIndex = -1
 	for i, p := range allParticipants {
-		bibValue := getBibValue(p.Data)
-		if bibValue == bib {
+		if p.Result.Bib == bib {
 			selectedIndex = i
 			break
 		}
@@ -460,10 +717,7 @@ func (r *mongoEventRepository) GetParticipantComparison(eventID primitive.Object
 	}
 
 	// Agregar los participantes anteriores
-	previousParticipantsSet := make(map[string]*domain.EventData)
 	for i := startIndex; i < selectedIndex; i++ {
-		bibVal := getBibValue(allParticipants[i].Data)
-		previousParticipantsSet[bibVal] = allParticipants[i]
 		result.PreviousParticipants = append(result.PreviousParticipants, allParticipants[i])
 	}
 
@@ -475,26 +729,7 @@ func (r *mongoEventRepository) GetParticipantComparison(eventID primitive.Object
 	return result, nil
 }
 
-// getBibValue extrae el valor del dorsal de múltiples campos posibles
-func getBibValue(data map[string]interface{}) string {
-	possibleKeys := []string{"DORSAL", "dorsal", "bib", "Dorsal", "Bib"}
-
-	for _, key := range possibleKeys {
-		if val, ok := data[key]; ok {
-			switch v := val.(type) {
-			case string:
-				return v
-			case float64:
-				return strconv.FormatFloat(v, 'f', 0, 64)
-			case int:
-				return strconv.Itoa(v)
-			case int32:
-				return strconv.Itoa(int(v))
-			case int64:
-				return strconv.FormatInt(v, 10)
-			}
-		}
-	}
-
-	return ""
+// exactMatch crea un filtro de coincidencia exacta case-insensitive
+func exactMatch(value string) bson.M {
+	return bson.M{"$regex": "^" + regexp.QuoteMeta(value) + "$", "$options": "i"}
 }
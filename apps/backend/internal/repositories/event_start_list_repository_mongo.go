@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+
+	"backend/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *mongoEventRepository) getStartListCollection() *mongo.Collection {
+	return r.db.Database(r.dbName).Collection("start_list_entries")
+}
+
+// ReplaceStartList reemplaza los inscritos del evento y guarda la descripción de la lista
+func (r *mongoEventRepository) ReplaceStartList(eventID primitive.ObjectID, startList *domain.StartList, entries []*domain.StartListEntry) error {
+	if _, err := r.getStartListCollection().DeleteMany(context.Background(), bson.M{"eventId": eventID}); err != nil {
+		return err
+	}
+
+	if len(entries) > 0 {
+		docs := make([]interface{}, len(entries))
+		for i, entry := range entries {
+			if entry.ID.IsZero() {
+				entry.ID = primitive.NewObjectID()
+			}
+			entry.EventID = eventID
+			docs[i] = entry
+		}
+		if _, err := r.getStartListCollection().InsertMany(context.Background(), docs); err != nil {
+			return err
+		}
+	}
+
+	return r.UpdateStartList(eventID, startList)
+}
+
+// UpdateStartList guarda la descripción de la lista de inscritos del evento
+func (r *mongoEventRepository) UpdateStartList(eventID primitive.ObjectID, startList *domain.StartList) error {
+	_, err := r.getEventCollection().UpdateOne(
+		context.Background(),
+		bson.M{"_id": eventID},
+		bson.M{"$set": bson.M{"startList": startList}},
+	)
+	return err
+}
+
+// DeleteStartList elimina los inscritos y la descripción de la lista del evento
+func (r *mongoEventRepository) DeleteStartList(eventID primitive.ObjectID) error {
+	if _, err := r.getStartListCollection().DeleteMany(context.Background(), bson.M{"eventId": eventID}); err != nil {
+		return err
+	}
+	_, err := r.getEventCollection().UpdateOne(
+		context.Background(),
+		bson.M{"_id": eventID},
+		bson.M{"$unset": bson.M{"startList": ""}},
+	)
+	return err
+}
+
+// FindStartList obtiene los inscritos del evento en el orden del archivo
+func (r *mongoEventRepository) FindStartList(eventID primitive.ObjectID) ([]*domain.StartListEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "line", Value: 1}})
+	cursor, err := r.getStartListCollection().Find(context.Background(), bson.M{"eventId": eventID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	entries := []*domain.StartListEntry{}
+	if err := cursor.All(context.Background(), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
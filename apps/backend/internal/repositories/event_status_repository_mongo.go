@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *mongoEventRepository) getParticipantStatusCollection() *mongo.Collection {
+	return r.db.Database(r.dbName).Collection("participant_statuses")
+}
+
+// SaveParticipantStatus guarda el estado fijado para el dorsal, reemplazando el anterior
+func (r *mongoEventRepository) SaveParticipantStatus(status *domain.ParticipantStatus) error {
+	status.UpdatedAt = time.Now()
+	result := r.getParticipantStatusCollection().FindOneAndUpdate(
+		context.Background(),
+		bson.M{"eventId": status.EventID, "bib": status.Bib},
+		bson.M{
+			"$set": bson.M{
+				"status":    status.Status,
+				"reason":    status.Reason,
+				"updatedAt": status.UpdatedAt,
+			},
+			"$setOnInsert": bson.M{"_id": primitive.NewObjectID()},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	)
+	return result.Decode(status)
+}
+
+func (r *mongoEventRepository) DeleteParticipantStatus(eventID primitive.ObjectID, bib string) error {
+	_, err := r.getParticipantStatusCollection().DeleteOne(context.Background(), bson.M{"eventId": eventID, "bib": bib})
+	return err
+}
+
+func (r *mongoEventRepository) DeleteParticipantStatuses(eventID primitive.ObjectID) error {
+	_, err := r.getParticipantStatusCollection().DeleteMany(context.Background(), bson.M{"eventId": eventID})
+	return err
+}
+
+func (r *mongoEventRepository) FindParticipantStatuses(eventID primitive.ObjectID) ([]*domain.ParticipantStatus, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "bib", Value: 1}})
+	cursor, err := r.getParticipantStatusCollection().Find(context.Background(), bson.M{"eventId": eventID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	statuses := []*domain.ParticipantStatus{}
+	if err := cursor.All(context.Background(), &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"backend/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// versionRecord es un participante guardado como parte de una versión del archivo
+type versionRecord struct {
+	VersionID        primitive.ObjectID `bson:"versionId"`
+	domain.EventData `bson:",inline"`
+}
+
+func (r *mongoEventRepository) getVersionCollection() *mongo.Collection {
+	return r.db.Database(r.dbName).Collection("event_versions")
+}
+
+func (r *mongoEventRepository) getVersionDataCollection() *mongo.Collection {
+	return r.db.Database(r.dbName).Collection("event_version_data")
+}
+
+func (r *mongoEventRepository) SaveVersion(version *domain.EventVersion, data []domain.EventData) error {
+	if len(data) > 0 {
+		docs := make([]interface{}, len(data))
+		for i, d := range data {
+			d.ID = primitive.NilObjectID
+			docs[i] = versionRecord{VersionID: version.ID, EventData: d}
+		}
+		if _, err := r.getVersionDataCollection().InsertMany(context.Background(), docs); err != nil {
+			return fmt.Errorf("could not save version data: %w", err)
+		}
+	}
+
+	// La versión se guarda al final para que nunca quede visible sin sus participantes
+	_, err := r.getVersionCollection().InsertOne(context.Background(), version)
+	return err
+}
+
+func (r *mongoEventRepository) getVersionCounterCollection() *mongo.Collection {
+	return r.db.Database(r.dbName).Collection("event_version_counters")
+}
+
+func (r *mongoEventRepository) NextVersionNumber(eventID primitive.ObjectID) (int, error) {
+	ctx := context.Background()
+
+	// Los eventos con versiones anteriores al contador continúan desde su última versión
+	var last domain.EventVersion
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "number", Value: -1}}).
+		SetProjection(bson.M{"number": 1})
+	err := r.getVersionCollection().FindOne(ctx, bson.M{"eventId": eventID}, opts).Decode(&last)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return 0, err
+	}
+	_, err = r.getVersionCounterCollection().UpdateOne(ctx,
+		bson.M{"_id": eventID},
+		bson.M{"$max": bson.M{"seq": last.Number}},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		return 0, err
+	}
+
+	var counter struct {
+		Seq int `bson:"seq"`
+	}
+	err = r.getVersionCounterCollection().FindOneAndUpdate(ctx,
+		bson.M{"_id": eventID},
+		bson.M{"$inc": bson.M{"seq": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&counter)
+	if err != nil {
+		return 0, err
+	}
+	return counter.Seq, nil
+}
+
+func (r *mongoEventRepository) FindVersions(eventID primitive.ObjectID) ([]*domain.EventVersion, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "number", Value: -1}}).
+		SetProjection(bson.M{"content": 0})
+	cursor, err := r.getVersionCollection().Find(context.Background(), bson.M{"eventId": eventID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	versions := []*domain.EventVersion{}
+	if err := cursor.All(context.Background(), &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (r *mongoEventRepository) FindVersion(id primitive.ObjectID) (*domain.EventVersion, error) {
+	var version domain.EventVersion
+	err := r.getVersionCollection().FindOne(context.Background(), bson.M{"_id": id}).Decode(&version)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &version, nil
+}
+
+func (r *mongoEventRepository) FindVersionData(versionID primitive.ObjectID) ([]domain.EventData, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "raceNumber", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.getVersionDataCollection().Find(context.Background(), bson.M{"versionId": versionID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var records []versionRecord
+	if err := cursor.All(context.Background(), &records); err != nil {
+		return nil, err
+	}
+
+	data := make([]domain.EventData, len(records))
+	for i, record := range records {
+		data[i] = record.EventData
+	}
+	return data, nil
+}
+
+func (r *mongoEventRepository) DeleteVersion(id primitive.ObjectID) error {
+	if _, err := r.getVersionCollection().DeleteOne(context.Background(), bson.M{"_id": id}); err != nil {
+		return err
+	}
+	_, err := r.getVersionDataCollection().DeleteMany(context.Background(), bson.M{"versionId": id})
+	return err
+}
+
+func (r *mongoEventRepository) DeleteVersions(eventID primitive.ObjectID) error {
+	versions, err := r.FindVersions(eventID)
+	if err != nil {
+		return err
+	}
+
+	versionIDs := make([]primitive.ObjectID, len(versions))
+	for i, version := range versions {
+		versionIDs[i] = version.ID
+	}
+	if len(versionIDs) > 0 {
+		if _, err := r.getVersionDataCollection().DeleteMany(context.Background(), bson.M{"versionId": bson.M{"$in": versionIDs}}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := r.getVersionCollection().DeleteMany(context.Background(), bson.M{"eventId": eventID}); err != nil {
+		return err
+	}
+	_, err = r.getVersionCounterCollection().DeleteOne(context.Background(), bson.M{"_id": eventID})
+	return err
+}
@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"backend/internal/core/domain"
+	"backend/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoSeriesRepository struct {
+	db     *mongo.Client
+	dbName string
+}
+
+func NewMongoSeriesRepository(db *mongo.Client) ports.SeriesRepository {
+	return &mongoSeriesRepository{
+		db:     db,
+		dbName: os.Getenv("MONGO_DATABASE"),
+	}
+}
+
+func (r *mongoSeriesRepository) getSeriesCollection() *mongo.Collection {
+	return r.db.Database(r.dbName).Collection("series")
+}
+
+func (r *mongoSeriesRepository) getStandingsCollection() *mongo.Collection {
+	return r.db.Database(r.dbName).Collection("series_standings")
+}
+
+func (r *mongoSeriesRepository) SaveSeries(series *domain.Series) error {
+	if series.ID.IsZero() {
+		series.ID = primitive.NewObjectID()
+	}
+	series.CreatedAt = time.Now()
+	series.UpdatedAt = series.CreatedAt
+	_, err := r.getSeriesCollection().InsertOne(context.Background(), series)
+	return err
+}
+
+func (r *mongoSeriesRepository) UpdateSeries(series *domain.Series) error {
+	series.UpdatedAt = time.Now()
+	_, err := r.getSeriesCollection().UpdateOne(
+		context.Background(),
+		bson.M{"_id": series.ID},
+		bson.M{"$set": bson.M{
+			"name":        series.Name,
+			"slug":        series.Slug,
+			"description": series.Description,
+			"eventIds":    series.EventIDs,
+			"scoring":     series.Scoring,
+			"updatedAt":   series.UpdatedAt,
+		}},
+	)
+	return err
+}
+
+func (r *mongoSeriesRepository) DeleteSeries(id primitive.ObjectID) error {
+	if _, err := r.getStandingsCollection().DeleteOne(context.Background(), bson.M{"_id": id}); err != nil {
+		return err
+	}
+	_, err := r.getSeriesCollection().DeleteOne(context.Background(), bson.M{"_id": id})
+	return err
+}
+
+func (r *mongoSeriesRepository) FindSeriesByID(id primitive.ObjectID) (*domain.Series, error) {
+	return r.findOneSeries(bson.M{"_id": id})
+}
+
+func (r *mongoSeriesRepository) FindSeriesBySlug(slug string) (*domain.Series, error) {
+	return r.findOneSeries(bson.M{"slug": slug})
+}
+
+func (r *mongoSeriesRepository) findOneSeries(filter bson.M) (*domain.Series, error) {
+	var series domain.Series
+	err := r.getSeriesCollection().FindOne(context.Background(), filter).Decode(&series)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+func (r *mongoSeriesRepository) FindSeries(page int, limit int) (*ports.FindSeriesResult, error) {
+	totalCount, err := r.getSeriesCollection().CountDocuments(context.Background(), bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64((page - 1) * limit))
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := r.getSeriesCollection().Find(context.Background(), bson.M{}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	series := []*domain.Series{}
+	if err = cursor.All(context.Background(), &series); err != nil {
+		return nil, err
+	}
+
+	return &ports.FindSeriesResult{
+		Series:     series,
+		TotalCount: totalCount,
+	}, nil
+}
+
+// FindSeriesByEvent retorna los campeonatos que tienen al evento como fecha
+func (r *mongoSeriesRepository) FindSeriesByEvent(eventID primitive.ObjectID) ([]*domain.Series, error) {
+	cursor, err := r.getSeriesCollection().Find(context.Background(), bson.M{"eventIds": eventID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	series := []*domain.Series{}
+	if err := cursor.All(context.Background(), &series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (r *mongoSeriesRepository) SaveStandings(standings *domain.SeriesStandings) error {
+	_, err := r.getStandingsCollection().ReplaceOne(
+		context.Background(),
+		bson.M{"_id": standings.SeriesID},
+		standings,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *mongoSeriesRepository) FindStandings(seriesID primitive.ObjectID) (*domain.SeriesStandings, error) {
+	var standings domain.SeriesStandings
+	err := r.getStandingsCollection().FindOne(context.Background(), bson.M{"_id": seriesID}).Decode(&standings)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &standings, nil
+}
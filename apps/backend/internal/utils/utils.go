@@ -2,6 +2,7 @@ package utils
 
 import (
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -48,6 +49,26 @@ func removeAccents(input string) string {
 	return result.String()
 }
 
+// nonAlphanumericPattern matches the runs of characters dropped by NormalizeKey and NormalizeName
+var nonAlphanumericPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// NormalizeKey converts a column or field name into an upper case key without
+// accents, spaces or punctuation, e.g. "Pos. Cat." -> "POSCAT"
+func NormalizeKey(input string) string {
+	folded := removeAccents(strings.ToLower(strings.TrimSpace(input)))
+	return strings.ToUpper(nonAlphanumericPattern.ReplaceAllString(folded, ""))
+}
+
+// NormalizeName folds a person name so the same runner matches across files:
+// lower case, without accents or punctuation, and with the words sorted so
+// "Inostroza Cristobal" and "Cristóbal Inostroza" are equal
+func NormalizeName(input string) string {
+	folded := removeAccents(strings.ToLower(strings.TrimSpace(input)))
+	words := strings.Fields(nonAlphanumericPattern.ReplaceAllString(folded, " "))
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
 // IsValidSlug checks if a string is a valid slug format
 func IsValidSlug(slug string) bool {
 	if slug == "" {